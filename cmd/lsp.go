@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/lsp"
+)
+
+var lspConfigPath string
+var lspDebounce int
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a Language Server Protocol server over stdio",
+	Long: "lsp speaks the Language Server Protocol on stdin/stdout. It publishes diagnostics for open " +
+		"Markdown buffers as they change and offers fixes and disable comments as code actions.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srv := lsp.NewServer(os.Stdin, os.Stdout, lsp.Options{
+			ConfigPath: lspConfigPath,
+			Debounce:   time.Duration(lspDebounce) * time.Millisecond,
+			Version:    version,
+		})
+		return srv.Run()
+	},
+}
+
+func init() {
+	lspCmd.Flags().StringVar(&lspConfigPath, "config", "", "path to config file (default: .gomarklint.json in the workspace root)")
+	lspCmd.Flags().IntVar(&lspDebounce, "debounce", int(lsp.DefaultDebounce/time.Millisecond), "milliseconds to wait after an edit before linting")
+}
@@ -1,17 +1,36 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/shinagawa-web/gomarklint/v3/internal/app"
+	"github.com/shinagawa-web/gomarklint/v3/internal/cache"
 	"github.com/shinagawa-web/gomarklint/v3/internal/config"
 )
 
+// ErrLintViolations is returned by Execute when violations were reported.
+// The output has already been written, so callers usually only set the exit
+// status.
+var ErrLintViolations = app.ErrLintViolations
+
 var configFilePath string
 var outputFormat string
 var minSeverity string
+var fixFlag bool
+var fixDryRun bool
+var baselinePath string
+var updateBaseline bool
+var diffBase string
+var changedFlag bool
+var stdinFilename string
+var noGitignore bool
+var jobs int
+var cacheDir string
+var noCache bool
+var refreshLinks bool
 
 var rootCmd = &cobra.Command{
 	Use:   "gomarklint [files or directories]",
@@ -23,8 +42,24 @@ var rootCmd = &cobra.Command{
 
 func runLint(cmd *cobra.Command, args []string) error {
 	opts := app.Options{
-		ConfigPath: configFilePath,
-		Args:       args,
+		ConfigPath:     configFilePath,
+		Args:           args,
+		Fix:            fixFlag,
+		FixDryRun:      fixDryRun,
+		Baseline:       baselinePath,
+		UpdateBaseline: updateBaseline,
+		DiffBase:       diffBase,
+		Stdin:          cmd.InOrStdin(),
+		StdinFilename:  stdinFilename,
+		NoGitignore:    noGitignore,
+		Version:        version,
+		RefreshLinks:   refreshLinks,
+	}
+	if changedFlag {
+		if diffBase != "" {
+			return fmt.Errorf("--changed and --diff-base cannot be used together")
+		}
+		opts.DiffBase = "HEAD"
 	}
 	if cmd.Flags().Changed("output") {
 		opts.OutputFormat = outputFormat
@@ -32,19 +67,56 @@ func runLint(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("severity") {
 		opts.MinSeverity = config.RuleSeverity(minSeverity)
 	}
+	if cmd.Flags().Changed("jobs") {
+		opts.Jobs = jobs
+	}
+	if err := setCacheDir(&opts); err != nil {
+		return err
+	}
 	return app.Run(os.Stdout, opts)
 }
 
+// setCacheDir picks the cache directory: --cache-dir, or the user
+// cache directory when the platform has one, unless --no-cache is given.
+func setCacheDir(opts *app.Options) error {
+	switch {
+	case noCache && cacheDir != "":
+		return fmt.Errorf("--cache-dir and --no-cache cannot be used together")
+	case noCache:
+		return nil
+	case cacheDir != "":
+		opts.CacheDir = cacheDir
+		return nil
+	}
+	if dir, err := cache.DefaultDir(); err == nil {
+		opts.CacheDir = dir
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.Version = version
 	rootCmd.SetVersionTemplate("gomarklint version {{.Version}}\n")
 	rootCmd.SilenceUsage = true
 	rootCmd.SilenceErrors = true
 	rootCmd.Flags().StringVar(&configFilePath, "config", ".gomarklint.json", "path to config file (default: .gomarklint.json)")
-	rootCmd.Flags().StringVar(&outputFormat, "output", "text", "output format: text or json")
+	rootCmd.Flags().StringVar(&outputFormat, "output", "text", "output format: text, json, sarif or junit")
 	rootCmd.Flags().StringVar(&minSeverity, "severity", "warning", "minimum severity to report: warning or error")
+	rootCmd.Flags().BoolVar(&fixFlag, "fix", false, "fix violations in place where possible, then report what remains")
+	rootCmd.Flags().BoolVar(&fixDryRun, "fix-dry-run", false, "print the changes --fix would make as a unified diff, without writing files")
+	rootCmd.Flags().StringVar(&baselinePath, "baseline", "", "baseline file of known violations not to report")
+	rootCmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "write the current violations to the --baseline file")
+	rootCmd.Flags().StringVar(&diffBase, "diff-base", "", "lint only lines changed relative to this git ref")
+	rootCmd.Flags().BoolVar(&changedFlag, "changed", false, "lint only lines changed relative to HEAD (same as --diff-base HEAD)")
+	rootCmd.Flags().StringVar(&stdinFilename, "stdin-filename", "", "path to report, and match ignore patterns against, when linting stdin with '-'")
+	rootCmd.Flags().IntVar(&jobs, "jobs", 0, "number of files to lint at once (default: GOMAXPROCS)")
+	rootCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory of the result and link caches (default: $XDG_CACHE_HOME/gomarklint)")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "lint every file from scratch, without reading or writing the result or link cache")
+	rootCmd.Flags().BoolVar(&refreshLinks, "refresh-links", false, "check every external link again instead of reusing cached results")
+	rootCmd.Flags().BoolVar(&noGitignore, "no-gitignore", false, "lint files excluded by .gitignore and .ignore files")
 
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(lspCmd)
 }
 
 func Execute() error {
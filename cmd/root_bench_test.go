@@ -139,7 +139,7 @@ func BenchmarkFullLinting_ExtraLarge(b *testing.B) {
 }
 
 // BenchmarkEndToEnd exercises the full lint.Run path: file discovery, concurrent
-// file reading, goroutine fan-out with mutex, and result collection.
+// file reading on the worker pool, and result collection.
 // 50 files × 100 sections each (~1700 lines/file, ~85k lines total).
 func BenchmarkEndToEnd(b *testing.B) {
 	dir := b.TempDir()
@@ -159,7 +159,75 @@ func BenchmarkEndToEnd(b *testing.B) {
 
 	b.ResetTimer()
 	for b.Loop() {
-		paths := file.ExpandPaths([]string{dir}, cfg.Ignore)
+		paths := file.ExpandPaths([]string{dir}, cfg.Ignore, true)
+		_ = lint.Run(paths)
+	}
+}
+
+// writeMixedCorpus writes files whose sizes vary by two orders of magnitude,
+// so scheduling order matters: a large file started last keeps one worker
+// busy after the others have finished.
+func writeMixedCorpus(b *testing.B, dir string) {
+	b.Helper()
+	large := generateComplexMarkdown(500)
+	small := generateComplexMarkdown(5)
+	for i := range 200 {
+		content := small
+		if i%50 == 49 {
+			content = large
+		}
+		path := filepath.Join(dir, fmt.Sprintf("doc%03d.md", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkEndToEnd_Jobs runs lint.Run over a mixed-size corpus with
+// different worker pool sizes. jobs=0 is the default, GOMAXPROCS.
+func BenchmarkEndToEnd_Jobs(b *testing.B) {
+	dir := b.TempDir()
+	writeMixedCorpus(b, dir)
+
+	for _, jobs := range []int{1, 2, 4, 0} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			cfg := benchmarkConfig()
+			cfg.Jobs = jobs
+			lint, err := linter.New(cfg)
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+			paths := file.ExpandPaths([]string{dir}, cfg.Ignore, true)
+
+			b.ResetTimer()
+			for b.Loop() {
+				_ = lint.Run(paths)
+			}
+		})
+	}
+}
+
+// BenchmarkEndToEnd_ManyFiles lints a few thousand small files, the case
+// where one goroutine and open file per path cost the most.
+func BenchmarkEndToEnd_ManyFiles(b *testing.B) {
+	dir := b.TempDir()
+	content := generateComplexMarkdown(3)
+	for i := range 3000 {
+		path := filepath.Join(dir, fmt.Sprintf("doc%04d.md", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	cfg := benchmarkConfig()
+	lint, err := linter.New(cfg)
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	paths := file.ExpandPaths([]string{dir}, cfg.Ignore, true)
+
+	b.ResetTimer()
+	for b.Loop() {
 		_ = lint.Run(paths)
 	}
 }
@@ -2,5 +2,6 @@
 // formatting issues in Markdown files, including heading structure,
 // blank lines, duplicate headings, and link validation.
 //
-// It can be used as a command-line tool or imported as a Go package.
+// It can be used as a command-line tool, or from Go programs through the
+// github.com/shinagawa-web/gomarklint/v3/lint package.
 package main
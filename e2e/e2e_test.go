@@ -2,8 +2,11 @@ package e2e
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -11,6 +14,28 @@ const (
 	binaryName = "gomarklint-e2e-test"
 )
 
+// TestMain points the user cache directory of every gomarklint run at a
+// temporary directory, so the on-disk cache neither fills the developer's
+// real one nor carries results, such as link failures, between runs.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gomarklint-e2e-cache")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// os.UserCacheDir reads XDG_CACHE_HOME on Linux, LocalAppData on
+	// Windows and HOME on macOS.
+	for _, key := range []string{"XDG_CACHE_HOME", "LocalAppData"} {
+		os.Setenv(key, dir)
+	}
+	if runtime.GOOS == "darwin" {
+		os.Setenv("HOME", dir)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // runTest is a helper function to run the gomarklint binary and return only the output.
 // It ignores the exit code since E2E tests check output content, not exit status.
 func runTest(t *testing.T, args ...string) []byte {
@@ -478,6 +503,115 @@ func TestE2E_BasicFunctionality(t *testing.T) {
 		assertOutputContains(t, output, "expected dash marker, got asterisk marker")
 		assertOutputContains(t, output, "1 issues found")
 	})
+
+	t.Run("RelativeLinksValid", func(t *testing.T) {
+		output := runTest(t, "fixtures/relative_links_valid.md", "--config", "config-relative-links.json")
+		assertOutputContains(t, output, "No issues found")
+		assertOutputNotContains(t, output, "relative-links")
+	})
+
+	t.Run("RelativeLinksViolation", func(t *testing.T) {
+		output, err := runTestWithCmd(t, "fixtures/relative_links_violation.md", "--config", "config-relative-links.json")
+		if err == nil {
+			t.Error("expected non-zero exit code for lint violations")
+		}
+		assertOutputContains(t, output, "Errors in fixtures/relative_links_violation.md:")
+		assertOutputContains(t, output, "fixtures/relative_links_violation.md:3:")
+		assertOutputContains(t, output, "relative-links: missing.md not found")
+		assertOutputContains(t, output, "fixtures/relative_links_violation.md:5:")
+		assertOutputContains(t, output, "relative-links: fragment #setup not found in valid.md")
+		assertOutputContains(t, output, "fixtures/relative_links_violation.md:7:")
+		assertOutputContains(t, output, "relative-links: ./gone/readme.md not found")
+		assertOutputContains(t, output, "3 issues found")
+	})
+
+	t.Run("LocalAssetsValid", func(t *testing.T) {
+		output := runTest(t, "fixtures/local_assets_valid.md", "--config", "config-local-assets.json")
+		assertOutputContains(t, output, "No issues found")
+		assertOutputNotContains(t, output, "local-assets")
+	})
+
+	t.Run("LocalAssetsViolation", func(t *testing.T) {
+		output, err := runTestWithCmd(t, "fixtures/local_assets_violation.md", "--config", "config-local-assets.json")
+		if err == nil {
+			t.Error("expected non-zero exit code for lint violations")
+		}
+		assertOutputContains(t, output, "Errors in fixtures/local_assets_violation.md:")
+		assertOutputContains(t, output, "fixtures/local_assets_violation.md:3:")
+		assertOutputContains(t, output, "local-assets: assets/missing.png not found")
+		assertOutputContains(t, output, "fixtures/local_assets_violation.md:5:")
+		assertOutputContains(t, output, "local-assets: assets/Logo.svg differs in case from assets/logo.svg on disk")
+		assertOutputContains(t, output, "fixtures/local_assets_violation.md:7:")
+		assertOutputContains(t, output, "local-assets: assets/banner.png not found")
+		assertOutputContains(t, output, "fixtures/local_assets_violation.md:9:")
+		assertOutputContains(t, output, "local-assets: assets is a directory")
+		assertOutputContains(t, output, "4 issues found")
+	})
+
+	t.Run("ReferencesValid", func(t *testing.T) {
+		output := runTest(t, "fixtures/references_valid.md", "--config", "config-references.json")
+		assertOutputContains(t, output, "No issues found")
+		assertOutputNotContains(t, output, "definition")
+	})
+
+	t.Run("ReferencesViolation", func(t *testing.T) {
+		output, err := runTestWithCmd(t, "fixtures/references_violation.md", "--config", "config-references.json")
+		if err == nil {
+			t.Error("expected non-zero exit code for lint violations")
+		}
+		assertOutputContains(t, output, "Errors in fixtures/references_violation.md:")
+		assertOutputContains(t, output, "fixtures/references_violation.md:3:")
+		assertOutputContains(t, output, "no-undefined-references: reference [gide] has no definition")
+		assertOutputContains(t, output, "fixtures/references_violation.md:7:")
+		assertOutputContains(t, output, "no-unused-definitions: definition [guide] is never used")
+		assertOutputContains(t, output, "fixtures/references_violation.md:9:")
+		assertOutputContains(t, output, "no-duplicate-definitions: [FAQ] is already defined on line 8")
+		assertOutputContains(t, output, "fixtures/references_violation.md:10:")
+		assertOutputContains(t, output, "no-unused-definitions: definition [unused] is never used")
+		assertOutputContains(t, output, "4 issues found")
+	})
+
+	t.Run("TableFormattingValid", func(t *testing.T) {
+		output := runTest(t, "fixtures/table_formatting_valid.md", "--config", "config-table-formatting.json")
+		assertOutputContains(t, output, "No issues found")
+		assertOutputNotContains(t, output, "table-formatting")
+	})
+
+	t.Run("TableFormattingViolation", func(t *testing.T) {
+		output, err := runTestWithCmd(t, "fixtures/table_formatting_violation.md", "--config", "config-table-formatting.json")
+		if err == nil {
+			t.Error("expected non-zero exit code for lint violations")
+		}
+		assertOutputContains(t, output, "Errors in fixtures/table_formatting_violation.md:")
+		assertOutputContains(t, output, "fixtures/table_formatting_violation.md:3:")
+		assertOutputContains(t, output, "table-formatting: table columns are not aligned")
+		assertOutputContains(t, output, "fixtures/table_formatting_violation.md:6:")
+		assertOutputContains(t, output, "table-formatting: expected leading and trailing pipes, got no leading or trailing pipes")
+		assertOutputContains(t, output, "fixtures/table_formatting_violation.md:8:")
+		assertOutputContains(t, output, "3 issues found")
+	})
+
+	t.Run("NoTrailingSpacesValid", func(t *testing.T) {
+		output := runTest(t, "fixtures/no_trailing_spaces_valid.md", "--config", "config-no-trailing-spaces.json")
+		assertOutputContains(t, output, "No issues found")
+		assertOutputNotContains(t, output, "no-trailing-spaces")
+	})
+
+	t.Run("NoTrailingSpacesViolation", func(t *testing.T) {
+		output, err := runTestWithCmd(t, "fixtures/no_trailing_spaces_violation.md", "--config", "config-no-trailing-spaces.json")
+		if err == nil {
+			t.Error("expected non-zero exit code for lint violations")
+		}
+		assertOutputContains(t, output, "Errors in fixtures/no_trailing_spaces_violation.md:")
+		assertOutputContains(t, output, "fixtures/no_trailing_spaces_violation.md:1:")
+		assertOutputContains(t, output, "fixtures/no_trailing_spaces_violation.md:3:")
+		// Three spaces before a continuation line are one too many for brSpaces 2.
+		assertOutputContains(t, output, "fixtures/no_trailing_spaces_violation.md:5:")
+		assertOutputContains(t, output, "fixtures/no_trailing_spaces_violation.md:8:")
+		assertOutputContains(t, output, "fixtures/no_trailing_spaces_violation.md:9:")
+		assertOutputContains(t, output, "no-trailing-spaces: trailing whitespace found")
+		assertOutputContains(t, output, "5 issues found")
+	})
 }
 
 func TestE2E_Configuration(t *testing.T) {
@@ -535,7 +669,7 @@ func TestE2E_OutputFormats(t *testing.T) {
 	t.Run("TextFormat", func(t *testing.T) {
 		output := runTest(t, "fixtures/invalid_heading_level.md", "--config", ".gomarklint.json", "--output", "text")
 		assertOutputContains(t, output, "Errors in fixtures/invalid_heading_level.md:")
-		assertOutputContains(t, output, "fixtures/invalid_heading_level.md:1:")
+		assertOutputContains(t, output, "fixtures/invalid_heading_level.md:1:1:")
 		assertOutputContains(t, output, "First heading should be level 2")
 		assertOutputContains(t, output, "Checked 1 file(s)")
 		assertOutputContains(t, output, "1 issues found")
@@ -549,10 +683,29 @@ func TestE2E_OutputFormats(t *testing.T) {
 		assertOutputContains(t, output, `"elapsed_ms"`)
 		assertOutputContains(t, output, `"file": "fixtures/invalid_heading_level.md"`)
 		assertOutputContains(t, output, `"line": 1`)
+		assertOutputContains(t, output, `"column": 1`)
 		assertOutputContains(t, output, `"message": "First heading should be level 2`)
 		assertOutputContains(t, output, `{`)
 		assertOutputContains(t, output, `}`)
 	})
+
+	t.Run("SARIFFormat", func(t *testing.T) {
+		output := runTest(t, "fixtures/invalid_heading_level.md", "--config", ".gomarklint.json", "--output", "sarif")
+		assertOutputContains(t, output, `"version": "2.1.0"`)
+		assertOutputContains(t, output, `"name": "gomarklint"`)
+		assertOutputContains(t, output, `"ruleId": "heading-level"`)
+		assertOutputContains(t, output, `"uri": "fixtures/invalid_heading_level.md"`)
+		assertOutputContains(t, output, `"startLine": 1`)
+		assertOutputContains(t, output, `"startColumn": 1`)
+	})
+
+	t.Run("JUnitFormat", func(t *testing.T) {
+		output := runTest(t, "fixtures/invalid_heading_level.md", "--config", ".gomarklint.json", "--output", "junit")
+		assertOutputContains(t, output, `<?xml version="1.0" encoding="UTF-8"?>`)
+		assertOutputContains(t, output, `<testsuite name="fixtures/invalid_heading_level.md"`)
+		assertOutputContains(t, output, `<testcase name="heading-level" classname="fixtures/invalid_heading_level.md">`)
+		assertOutputContains(t, output, `fixtures/invalid_heading_level.md:1:1: First heading should be level 2`)
+	})
 }
 
 func TestE2E_MultipleFiles(t *testing.T) {
@@ -618,7 +771,7 @@ func TestE2E_MultipleFiles(t *testing.T) {
 		// no_hard_tabs_context.md (#337 preprocess e2e): tabs outside fenced code
 		// are still reported.
 		assertOutputContains(t, output, "Errors in fixtures/no_hard_tabs_context.md:")
-		assertOutputContains(t, output, "Checked 60 file(s)")
+		assertOutputContains(t, output, "Checked 72 file(s)")
 		assertOutputNotContains(t, output, "Errors in fixtures/valid.md")
 		assertOutputNotContains(t, output, "Errors in fixtures/with_frontmatter.md")
 		assertOutputNotContains(t, output, "Errors in fixtures/frontmatter_only.md")
@@ -1059,3 +1212,276 @@ func TestE2E_DisableComments(t *testing.T) {
 		assertOutputContains(t, output, "fixtures/disable_comment.md:26:")
 	})
 }
+
+func TestE2E_Plugins(t *testing.T) {
+	t.Run("FindingsUseConfiguredSeverity", func(t *testing.T) {
+		output, err := runTestWithCmd(t, "fixtures/plugin_todo.md", "--config", "config-plugin.json")
+		if err != nil {
+			t.Errorf("expected exit 0 for a warning-only result, got: %v\noutput: %s", err, output)
+		}
+		assertOutputContains(t, output, "fixtures/plugin_todo.md:3:1:")
+		assertOutputContains(t, output, "house-style: TODO left in text")
+		assertOutputContains(t, output, "1 warning found")
+	})
+
+	t.Run("JSONOutput", func(t *testing.T) {
+		output := runTest(t, "fixtures/plugin_todo.md", "--config", "config-plugin.json", "--output", "json")
+		assertOutputContains(t, output, `"rule": "house-style"`)
+	})
+
+	t.Run("CleanFile", func(t *testing.T) {
+		output := runTest(t, "fixtures/valid.md", "--config", "config-plugin.json")
+		assertOutputNotContains(t, output, "house-style")
+	})
+}
+
+func TestE2E_CustomRules(t *testing.T) {
+	output, err := runTestWithCmd(t, "fixtures/custom_rules.md", "--config", "config-custom-rules.json")
+	if err == nil {
+		t.Error("expected non-zero exit code for an error-severity custom rule")
+	}
+	assertOutputContains(t, output, "fixtures/custom_rules.md:3:31: [error] no-internal-hosts: use the public hostname")
+	assertOutputContains(t, output, `fixtures/custom_rules.md:3:4: [warning] no-utilize: found "utilize"`)
+	assertOutputNotContains(t, output, "custom_rules.md:6:")
+	assertOutputNotContains(t, output, "custom_rules.md:8:")
+}
+
+func TestE2E_Baseline(t *testing.T) {
+	path := copyFixture(t, "invalid_heading_level.md")
+	bl := filepath.Join(t.TempDir(), "baseline.json")
+
+	runTest(t, path, "--config", ".gomarklint.json", "--baseline", bl, "--update-baseline")
+	output := runTest(t, path, "--config", ".gomarklint.json", "--baseline", bl, "--output", "json")
+	assertOutputContains(t, output, `"suppressed": `)
+	assertOutputNotContains(t, output, "First heading should be level 2")
+
+	if err := os.WriteFile(path, []byte("## Fixed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output = runTest(t, path, "--config", ".gomarklint.json", "--baseline", bl)
+	assertOutputContains(t, output, "run with --update-baseline to remove them")
+}
+
+func TestE2E_DiffBase(t *testing.T) {
+	binaryPath, err := filepath.Abs(binaryName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	run := func(name string, args ...string) ([]byte, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		return cmd.CombinedOutput()
+	}
+	git := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.email=e2e@example.com", "-c", "user.name=e2e"}, args...)
+		if out, err := run("git", args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	data, err := os.ReadFile("fixtures/invalid_heading_level.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "doc.md")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	output, err := run(binaryPath, "--changed", ".")
+	if err != nil {
+		t.Errorf("expected legacy violations ignored, got: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "Checked 0 file(s)")
+
+	if err := os.WriteFile(path, append(data, "\n# Another\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output, err = run(binaryPath, "--changed", ".")
+	if err == nil {
+		t.Error("expected non-zero exit code for a violation on a changed line")
+	}
+	assertOutputContains(t, output, "Multiple H1")
+	assertOutputNotContains(t, output, "First heading should be level 2")
+
+	output, err = run(binaryPath, "--changed", "--diff-base", "HEAD", ".")
+	if err == nil {
+		t.Error("expected an error for conflicting flags")
+	}
+	assertOutputContains(t, output, "--changed and --diff-base cannot be used together")
+}
+
+func TestE2E_Stdin(t *testing.T) {
+	data, err := os.ReadFile("fixtures/invalid_heading_level.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("./"+binaryName, args...)
+		cmd.Stdin = bytes.NewReader(data)
+		return cmd.CombinedOutput()
+	}
+
+	output, err := run("-", "--config", ".gomarklint.json", "--stdin-filename", "docs/piped.md")
+	if err == nil {
+		t.Error("expected non-zero exit code for lint violations")
+	}
+	assertOutputContains(t, output, "docs/piped.md:1:1")
+
+	output, err = run("-", "--config", "config-ignore-stdin.json", "--stdin-filename", "generated/piped.md")
+	if err != nil {
+		t.Errorf("expected ignored stdin filename to pass, got: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "Checked 0 file(s)")
+}
+
+func TestE2E_IgnoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("fixtures/invalid_heading_level.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range map[string][]byte{
+		".gitignore":           []byte("vendor/\n"),
+		".gomarklintignore":    []byte("generated.md\n"),
+		"vendor/pkg/README.md": data,
+		"generated.md":         data,
+		"docs/guide.md":        []byte("## Guide\n"),
+	} {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := runTestWithCmd(t, dir, "--config", ".gomarklint.json")
+	if err != nil {
+		t.Errorf("expected ignored files skipped, got: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "Checked 1 file(s)")
+
+	output, err = runTestWithCmd(t, dir, "--config", ".gomarklint.json", "--no-gitignore")
+	if err == nil {
+		t.Error("expected non-zero exit code with --no-gitignore")
+	}
+	assertOutputContains(t, output, "Checked 2 file(s)")
+	assertOutputContains(t, output, "README.md")
+	assertOutputNotContains(t, output, "generated.md")
+}
+
+func TestE2E_Cache(t *testing.T) {
+	cacheDir := t.TempDir()
+	args := []string{"fixtures/invalid_heading_level.md", "--config", ".gomarklint.json", "--cache-dir", cacheDir}
+
+	first, _ := runTestWithCmd(t, args...)
+	second, err := runTestWithCmd(t, args...)
+	if err == nil {
+		t.Error("expected non-zero exit code for cached violations")
+	}
+	assertOutputContains(t, second, "First heading should be level 2")
+	if countLines(first) != countLines(second) {
+		t.Errorf("expected the cached run to report the same issues:\n%s\n---\n%s", first, second)
+	}
+
+	output, err := runTestWithCmd(t, append(args, "--no-cache")...)
+	if err == nil {
+		t.Error("expected an error for conflicting flags")
+	}
+	assertOutputContains(t, output, "--cache-dir and --no-cache cannot be used together")
+}
+
+func countLines(b []byte) int {
+	return bytes.Count(b, []byte("\n"))
+}
+
+// copyFixture copies a fixture into a temp dir so --fix can rewrite it.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("fixtures", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestE2E_Fix(t *testing.T) {
+	cases := []struct {
+		fixture string
+		config  string
+	}{
+		{"blanks_around_headings_violation.md", "config-blanks-around-headings.json"},
+		{"blanks_around_lists_violation.md", "config-blanks-around-lists.json"},
+		{"blanks_around_fences_violation.md", "config-blanks-around-fences.json"},
+		{"consistent_list_marker_violation.md", "config-consistent-list-marker.json"},
+		{"consistent_emphasis_style_violation.md", "config-consistent-emphasis-style.json"},
+		{"no_hard_tabs_violation.md", "config-no-hard-tabs.json"},
+		{"table_formatting_violation.md", "config-table-formatting.json"},
+		{"no_trailing_spaces_violation.md", "config-no-trailing-spaces.json"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			path := copyFixture(t, tc.fixture)
+
+			output, err := runTestWithCmd(t, path, "--config", tc.config, "--fix")
+			if err != nil {
+				t.Errorf("expected exit 0 after --fix, got %v: %s", err, output)
+			}
+			assertOutputContains(t, output, "Fixed ")
+			assertOutputContains(t, output, "No issues found")
+
+			output = runTest(t, path, "--config", tc.config)
+			assertOutputContains(t, output, "No issues found")
+		})
+	}
+
+	t.Run("DryRunPrintsApplicableDiff", func(t *testing.T) {
+		path := copyFixture(t, "blanks_around_headings_violation.md")
+		before, _ := os.ReadFile(path)
+
+		output, err := runTestWithCmd(t, path, "--config", "config-blanks-around-headings.json", "--fix-dry-run")
+		if err == nil {
+			t.Error("expected non-zero exit code for a non-empty diff")
+		}
+		// The copy lies outside the working directory, so it keeps its
+		// absolute path.
+		assertOutputContains(t, output, "--- "+filepath.ToSlash(path)+"\n")
+		assertOutputContains(t, output, "+++ "+filepath.ToSlash(path)+"\n")
+		assertOutputContains(t, output, "@@ -")
+
+		after, _ := os.ReadFile(path)
+		if !bytes.Equal(before, after) {
+			t.Error("expected file unchanged with --fix-dry-run")
+		}
+	})
+
+	t.Run("KeepsHardLineBreaks", func(t *testing.T) {
+		path := copyFixture(t, "no_trailing_spaces_violation.md")
+		runTest(t, path, "--config", "config-no-trailing-spaces.json", "--fix")
+		after, _ := os.ReadFile(path)
+		if !bytes.Contains(after, []byte("three spaces  \ncontinues")) {
+			t.Errorf("expected the hard break trimmed to two spaces, got %q", after)
+		}
+	})
+
+	t.Run("WithoutFlagLeavesFileUntouched", func(t *testing.T) {
+		path := copyFixture(t, "blanks_around_headings_violation.md")
+		before, _ := os.ReadFile(path)
+		runTest(t, path, "--config", "config-blanks-around-headings.json")
+		after, _ := os.ReadFile(path)
+		if !bytes.Equal(before, after) {
+			t.Error("expected file unchanged without --fix")
+		}
+	})
+}
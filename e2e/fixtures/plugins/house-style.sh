@@ -0,0 +1,7 @@
+#!/bin/sh
+# Reports a TODO on line 3 when the request mentions one.
+if grep -q TODO; then
+  echo '{"findings":[{"line":3,"column":1,"end_column":5,"message":"TODO left in text"}]}'
+else
+  echo '{"findings":[]}'
+fi
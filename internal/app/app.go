@@ -4,10 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/shinagawa-web/gomarklint/v3/internal/baseline"
+	"github.com/shinagawa-web/gomarklint/v3/internal/cache"
 	"github.com/shinagawa-web/gomarklint/v3/internal/config"
 	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/fix"
+	"github.com/shinagawa-web/gomarklint/v3/internal/gitdiff"
 	"github.com/shinagawa-web/gomarklint/v3/internal/linter"
 	"github.com/shinagawa-web/gomarklint/v3/internal/output"
 	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
@@ -20,8 +28,42 @@ type Options struct {
 	Args         []string
 	OutputFormat string
 	MinSeverity  config.RuleSeverity
+	Fix          bool
+	FixDryRun    bool
+	// Baseline is the path of a baseline file whose violations are not
+	// reported. UpdateBaseline rewrites it with the current violations.
+	Baseline       string
+	UpdateBaseline bool
+	// DiffBase is a git ref. When set, only files changed relative to it are
+	// linted, and only violations on changed lines are reported.
+	DiffBase string
+	// Stdin is read when Args is just StdinPath, and defaults to os.Stdin.
+	// StdinFilename is the path its content is linted and reported as.
+	Stdin         io.Reader
+	StdinFilename string
+	// NoGitignore stops directory expansion from honoring .gitignore and
+	// .ignore files. .gomarklintignore files still apply.
+	NoGitignore bool
+	// Jobs overrides the config's number of files linted at once when
+	// non-zero.
+	Jobs int
+	// CacheDir is the directory of the result and link caches. Empty
+	// disables them. Version is the gomarklint version cache entries are
+	// keyed by.
+	CacheDir string
+	Version  string
+	// RefreshLinks checks every external link again instead of reusing
+	// cached results. The new results are still cached.
+	RefreshLinks bool
 }
 
+// StdinPath is the path argument that reads Markdown from standard input.
+const StdinPath = "-"
+
+// defaultStdinName is the path standard input is reported as when no
+// filename is given for it.
+const defaultStdinName = "<stdin>"
+
 func Run(w io.Writer, opts Options) error {
 	start := time.Now()
 
@@ -36,43 +78,366 @@ func Run(w io.Writer, opts Options) error {
 	if opts.MinSeverity != "" {
 		cfg.MinSeverity = opts.MinSeverity
 	}
+	if opts.Jobs != 0 {
+		cfg.Jobs = opts.Jobs
+	}
 
 	if err := config.Validate(cfg); err != nil {
 		return err
 	}
+	if opts.Fix && opts.FixDryRun {
+		return fmt.Errorf("--fix and --fix-dry-run cannot be used together")
+	}
+	bl, err := loadBaseline(opts)
+	if err != nil {
+		return err
+	}
 
+	in, err := resolveInput(opts, cfg)
+	if err != nil {
+		return err
+	}
+
+	lint, err := linter.New(cfg)
+	if err != nil {
+		return err
+	}
+	if opts.CacheDir != "" {
+		if err := useCache(lint, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.FixDryRun {
+		return writeFixDiff(w, lint, in.files, in.read)
+	}
+
+	fixed := 0
+	if opts.Fix {
+		if fixed, err = fixFiles(lint, in.files); err != nil {
+			return err
+		}
+	}
+	result := in.lint(lint)
+	// The link cache only saves work; a failed write costs nothing else.
+	_ = lint.SaveLinks()
+
+	stats, err := applyBaseline(opts, bl, result, in)
+	if err != nil {
+		return err
+	}
+	if in.changes != nil {
+		dropUnchanged(result, in.changes)
+	}
+
+	if err := formatOutput(w, cfg, result, len(in.files)-len(result.FailedFiles), fixed, stats, time.Since(start)); err != nil {
+		return err
+	}
+
+	if result.TotalErrors > 0 {
+		return ErrLintViolations
+	}
+	return nil
+}
+
+// input is the set of files a run lints. A file read from standard input is
+// linted from memory under the name it was given.
+type input struct {
+	files   []string
+	changes gitdiff.Changes
+	stdin   map[string]string
+}
+
+func (in input) read(path string) (string, error) {
+	if content, ok := in.stdin[path]; ok {
+		return content, nil
+	}
+	return file.ReadFile(path)
+}
+
+func (in input) lint(lint *linter.Linter) *linter.Result {
+	if in.stdin == nil {
+		return lint.Run(in.files)
+	}
+	result := &linter.Result{Errors: map[string][]rule.LintError{}, FailedFiles: map[string]error{}}
+	for _, path := range in.files {
+		errs, lines, links := lint.LintContent(path, in.stdin[path])
+		result.Errors[path] = errs
+		result.OrderedPaths = append(result.OrderedPaths, path)
+		result.TotalLines += lines
+		result.TotalLinksChecked += links
+	}
+	recount(result)
+	return result
+}
+
+// resolveInput returns what the run lints: standard input when the only path
+// is StdinPath, otherwise the files named by opts and cfg.
+func resolveInput(opts Options, cfg config.Config) (input, error) {
+	for _, arg := range opts.Args {
+		if arg == StdinPath {
+			return readStdin(opts, cfg)
+		}
+	}
+	if opts.StdinFilename != "" {
+		return input{}, fmt.Errorf("--stdin-filename requires '-' as the path")
+	}
+	files, changes, err := resolveFiles(opts, cfg)
+	return input{files: files, changes: changes}, err
+}
+
+// readStdin reads standard input as the single file to lint. It is linted
+// as opts.StdinFilename, unless that path is ignored by cfg.
+func readStdin(opts Options, cfg config.Config) (input, error) {
+	switch {
+	case len(opts.Args) > 1:
+		return input{}, fmt.Errorf("'-' (stdin) cannot be combined with other paths")
+	case opts.Fix:
+		return input{}, fmt.Errorf("--fix cannot be used with stdin; use --fix-dry-run to print the changes")
+	case opts.DiffBase != "":
+		return input{}, fmt.Errorf("--diff-base and --changed cannot be used with stdin")
+	}
+	r := opts.Stdin
+	if r == nil {
+		r = os.Stdin
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return input{}, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	name := opts.StdinFilename
+	if name == "" {
+		name = defaultStdinName
+	}
+	in := input{stdin: map[string]string{name: string(data)}}
+	if opts.StdinFilename == "" || !file.ShouldIgnore(name, cfg.Ignore) {
+		in.files = []string{name}
+	}
+	return in, nil
+}
+
+// resolveFiles returns the files to lint. With opts.DiffBase set, only files
+// changed relative to it are considered, and the changes are returned too.
+func resolveFiles(opts Options, cfg config.Config) ([]string, gitdiff.Changes, error) {
 	args := opts.Args
 	if len(args) == 0 {
-		if len(cfg.Include) > 0 {
+		switch {
+		case len(cfg.Include) > 0:
 			args = cfg.Include
-		} else {
-			return fmt.Errorf("please provide a markdown file or directory (or set 'include' in .gomarklint.json)")
+		case opts.DiffBase != "":
+			args = []string{"."}
+		default:
+			return nil, nil, fmt.Errorf("please provide a markdown file or directory (or set 'include' in .gomarklint.json)")
 		}
 	}
+	if opts.DiffBase == "" {
+		return file.ExpandPaths(args, cfg.Ignore, !opts.NoGitignore), nil, nil
+	}
 
-	files := file.ExpandPaths(args, cfg.Ignore)
+	changes, err := gitdiff.Load(opts.DiffBase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file.ExpandPaths(changedPaths(changes, args), cfg.Ignore, !opts.NoGitignore), changes, nil
+}
 
-	lint, err := linter.New(cfg)
+// changedPaths returns the changed files under any of roots. Like directory
+// expansion, it skips files in hidden directories below a root.
+func changedPaths(changes gitdiff.Changes, roots []string) []string {
+	var paths []string
+	for _, path := range changes.Files() {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		for _, root := range roots {
+			if isUnder(abs, root) {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+func isUnder(abs, root string) bool {
+	absRoot, err := filepath.Abs(root)
 	if err != nil {
-		return err
+		return false
+	}
+	rel, err := filepath.Rel(absRoot, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	dirs := strings.Split(filepath.Dir(rel), string(filepath.Separator))
+	for _, dir := range dirs {
+		if dir != "." && strings.HasPrefix(dir, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// dropUnchanged removes the violations outside changed lines from result.
+func dropUnchanged(result *linter.Result, changes gitdiff.Changes) {
+	for path, errs := range result.Errors {
+		var kept []rule.LintError
+		for _, e := range errs {
+			if changes.Touches(path, e.Line, e.EndLine) {
+				kept = append(kept, e)
+			}
+		}
+		result.Errors[path] = kept
 	}
-	result := lint.Run(files)
+	recount(result)
+}
 
-	if err := formatOutput(w, cfg, result, len(files)-len(result.FailedFiles), time.Since(start)); err != nil {
+// useCache makes lint keep its results, and those of external link checks,
+// in opts.CacheDir.
+func useCache(lint *linter.Linter, opts Options) error {
+	c, err := cache.Open(opts.CacheDir, cache.Salt(opts.Version))
+	if err != nil {
 		return err
 	}
+	lint.UseLinkCache(c, opts.RefreshLinks)
+	return lint.UseCache(c)
+}
 
-	if result.TotalErrors > 0 {
+// loadBaseline reads the baseline named by opts, if any. A missing file is
+// only an error when it is not about to be written.
+func loadBaseline(opts Options) (*baseline.Baseline, error) {
+	if opts.UpdateBaseline && opts.Baseline == "" {
+		return nil, fmt.Errorf("--update-baseline requires --baseline")
+	}
+	if opts.Baseline == "" {
+		return nil, nil
+	}
+	bl, err := baseline.Load(opts.Baseline)
+	if err != nil && opts.UpdateBaseline && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return bl, err
+}
+
+// baselineStats is what applying a baseline did to a run.
+type baselineStats struct {
+	suppressed int
+	stale      int
+}
+
+// applyBaseline drops the violations listed in the baseline from result,
+// after rewriting the baseline first when opts.UpdateBaseline is set.
+func applyBaseline(opts Options, bl *baseline.Baseline, result *linter.Result, in input) (baselineStats, error) {
+	if opts.Baseline == "" {
+		return baselineStats{}, nil
+	}
+	lines := fileLines(in.read)
+	if opts.UpdateBaseline {
+		bl = bl.Update(result.Errors, in.files, lines)
+		if err := bl.Write(opts.Baseline); err != nil {
+			return baselineStats{}, err
+		}
+	}
+	kept, suppressed, stale := bl.Filter(result.Errors, in.files, lines)
+	result.Errors = kept
+	recount(result)
+	return baselineStats{suppressed: suppressed, stale: len(stale)}, nil
+}
+
+// recount sets the totals of result from its remaining violations.
+func recount(result *linter.Result) {
+	result.TotalErrors, result.TotalWarnings = 0, 0
+	for _, errs := range result.Errors {
+		for _, e := range errs {
+			if e.Severity == string(config.SeverityWarning) {
+				result.TotalWarnings++
+			} else {
+				result.TotalErrors++
+			}
+		}
+	}
+}
+
+// fileLines returns a baseline.Lines that reads each file once with read.
+func fileLines(read func(string) (string, error)) baseline.Lines {
+	cache := map[string][]string{}
+	return func(path string) []string {
+		if ls, ok := cache[path]; ok {
+			return ls
+		}
+		content, err := read(path)
+		var ls []string
+		if err == nil {
+			ls = strings.Split(content, "\n")
+		}
+		cache[path] = ls
+		return ls
+	}
+}
+
+// fixFiles rewrites each file with every available fix applied and returns
+// the number of fixes. Unreadable files are skipped here; the lint run that
+// follows reports them.
+func fixFiles(lint *linter.Linter, files []string) (int, error) {
+	total := 0
+	for _, path := range files {
+		content, err := file.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		fixed, n := lint.FixContent(path, content)
+		if n == 0 {
+			continue
+		}
+		if err := file.WriteFile(path, fixed); err != nil {
+			return total, fmt.Errorf("failed to write fixes to %s: %w", path, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// writeFixDiff prints the fixes that --fix would apply as a unified diff,
+// without touching any file. It returns ErrLintViolations when the diff is
+// non-empty so CI fails with a patch reviewers can apply. Unreadable files
+// are skipped, as fixFiles skips them.
+func writeFixDiff(w io.Writer, lint *linter.Linter, files []string, read func(string) (string, error)) error {
+	paths := append([]string(nil), files...)
+	sort.Strings(paths)
+	changed := false
+	for i, path := range paths {
+		if i > 0 && path == paths[i-1] {
+			continue
+		}
+		content, err := read(path)
+		if err != nil {
+			continue
+		}
+		fixed, n := lint.FixContent(path, content)
+		if n == 0 {
+			continue
+		}
+		if _, err := io.WriteString(w, fix.UnifiedDiff(path, content, fixed)); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if changed {
 		return ErrLintViolations
 	}
 	return nil
 }
 
-func formatOutput(w io.Writer, cfg config.Config, result *linter.Result, fileCount int, duration time.Duration) error {
+func formatOutput(w io.Writer, cfg config.Config, result *linter.Result, fileCount int, fixed int, bs baselineStats, duration time.Duration) error {
 	var formatter output.Formatter
-	if cfg.OutputFormat == "json" {
+	switch cfg.OutputFormat {
+	case "json":
 		formatter = output.NewJSONFormatter()
-	} else {
+	case "sarif":
+		formatter = output.NewSARIFFormatter(ruleNames(cfg))
+	case "junit":
+		formatter = output.NewJUnitFormatter(enabledRuleNames(cfg))
+	default:
 		formatter = output.NewTextFormatter()
 	}
 
@@ -88,15 +453,40 @@ func formatOutput(w io.Writer, cfg config.Config, result *linter.Result, fileCou
 		Lines:        result.TotalLines,
 		Total:        errCount + warnCount,
 		Warnings:     warnCount,
+		Fixed:        fixed,
+		Suppressed:   bs.suppressed,
+		Stale:        bs.stale,
 		LinksChecked: linksChecked,
 		Duration:     duration,
 		Details:      details,
 		OrderedPaths: result.OrderedPaths,
+		FailedFiles:  result.FailedFiles,
 	}
 
 	return formatter.Format(w, outputResult)
 }
 
+// ruleNames returns the built-in rules followed by the config's plugins and
+// custom rules.
+func ruleNames(cfg config.Config) []string {
+	return append(linter.RuleNames(), cfg.DeclaredRuleNames()...)
+}
+
+func enabledRuleNames(cfg config.Config) []string {
+	var names []string
+	for _, name := range linter.RuleNames() {
+		if cfg.IsEnabled(name) {
+			names = append(names, name)
+		}
+	}
+	for _, name := range cfg.DeclaredRuleNames() {
+		if cfg.IsDeclaredRuleEnabled(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func filterBySeverity(details map[string][]rule.LintError, minSev config.RuleSeverity) (map[string][]rule.LintError, int, int) {
 	filtered := make(map[string][]rule.LintError, len(details))
 	errCount := 0
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/gitdiff"
 	"github.com/shinagawa-web/gomarklint/v3/internal/linter"
 	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
 )
@@ -119,6 +122,52 @@ func TestRun_OutputFormatOverride(t *testing.T) {
 	}
 }
 
+func TestRun_SARIFOutput(t *testing.T) {
+	f := writeTempFile(t, "invalid.md", "# H1 heading\n")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath:   "/nonexistent/.gomarklint.json",
+		Args:         []string{f},
+		OutputFormat: "sarif",
+	})
+	if !errors.Is(err, ErrLintViolations) {
+		t.Errorf("expected ErrLintViolations, got: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"version": "2.1.0"`) {
+		t.Errorf("expected SARIF version in output, got: %s", out)
+	}
+	if !strings.Contains(out, `"ruleId": "heading-level"`) {
+		t.Errorf("expected heading-level result in output, got: %s", out)
+	}
+}
+
+func TestRun_JUnitOutput(t *testing.T) {
+	cfgFile := writeTempFile(t, "junit.json", `{"default":false,"rules":{"single-h1":true,"final-blank-line":true}}`)
+	f := writeTempFile(t, "invalid.md", "## A\n\n# B\n\n# C\n")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath:   cfgFile,
+		Args:         []string{f},
+		OutputFormat: "junit",
+	})
+	if !errors.Is(err, ErrLintViolations) {
+		t.Errorf("expected ErrLintViolations, got: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `<testcase name="single-h1"`) || !strings.Contains(out, `<testcase name="final-blank-line"`) {
+		t.Errorf("expected one testcase per enabled rule, got: %s", out)
+	}
+	if strings.Contains(out, `<testcase name="heading-level"`) {
+		t.Errorf("expected disabled rules to be omitted, got: %s", out)
+	}
+	if !strings.Contains(out, `<failure message="1 violation" type="error">`) {
+		t.Errorf("expected single-h1 failure, got: %s", out)
+	}
+}
+
 func TestRun_MinSeverityOverride(t *testing.T) {
 	cfgFile := writeTempFile(t, "warning-rule.json", `{
 		"default": false,
@@ -157,6 +206,354 @@ func TestRun_MinSeverityOverride(t *testing.T) {
 	}
 }
 
+func TestRun_Fix(t *testing.T) {
+	f := writeTempFile(t, "fixable.md", "## Title\ntext\n\n\n\n* a\n- b\n")
+	if err := os.Chmod(f, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath: "/nonexistent/.gomarklint.json",
+		Args:       []string{f},
+		Fix:        true,
+	})
+	if err != nil {
+		t.Errorf("expected no remaining violations, got: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Fixed 4 issue(s)") {
+		t.Errorf("expected fixed count in output, got: %s", buf.String())
+	}
+
+	got, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "## Title\n\ntext\n\n* a\n* b\n"; string(got) != want {
+		t.Errorf("file content = %q, want %q", got, want)
+	}
+	info, err := os.Stat(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestRun_FixReportsRemaining(t *testing.T) {
+	f := writeTempFile(t, "h1.md", "# Title\ntext")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath: "/nonexistent/.gomarklint.json",
+		Args:       []string{f},
+		Fix:        true,
+	})
+	if !errors.Is(err, ErrLintViolations) {
+		t.Errorf("expected ErrLintViolations for unfixable heading-level, got: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "First heading should be level 2") || strings.Contains(out, "blanks-around-headings") {
+		t.Errorf("expected only the unfixable violation reported, got: %s", out)
+	}
+}
+
+func TestRun_FixDryRun(t *testing.T) {
+	content := "## Title\ntext\n"
+	f := writeTempFile(t, "fixable.md", content)
+	t.Chdir(filepath.Dir(f))
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath: "/nonexistent/.gomarklint.json",
+		Args:       []string{f},
+		FixDryRun:  true,
+	})
+	if !errors.Is(err, ErrLintViolations) {
+		t.Errorf("expected ErrLintViolations for a non-empty diff, got: %v", err)
+	}
+	// The absolute path is labelled relative to the working directory.
+	want := "--- a/fixable.md\n+++ b/fixable.md\n" +
+		"@@ -1,2 +1,3 @@\n ## Title\n+\n text\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	got, _ := os.ReadFile(f)
+	if string(got) != content {
+		t.Errorf("expected file untouched, got %q", got)
+	}
+}
+
+func TestRun_FixDryRun_NothingToFix(t *testing.T) {
+	f := writeTempFile(t, "h1.md", "# Title\n")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath: "/nonexistent/.gomarklint.json",
+		Args:       []string{f},
+		FixDryRun:  true,
+	})
+	if err != nil {
+		t.Errorf("expected no error when no fix applies, got: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected empty output, got: %s", buf.String())
+	}
+}
+
+func TestWriteFixDiff_SkipsUnreadableFile(t *testing.T) {
+	lint, err := linter.New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func(path string) (string, error) {
+		if path == "a.md" {
+			return "", os.ErrPermission
+		}
+		return "## Title\ntext\n", nil
+	}
+
+	var buf bytes.Buffer
+	err = writeFixDiff(&buf, lint, []string{"a.md", "b.md"}, read)
+	if !errors.Is(err, ErrLintViolations) {
+		t.Errorf("expected ErrLintViolations for the readable file's diff, got: %v", err)
+	}
+	if out := buf.String(); strings.Contains(out, "a.md") || !strings.Contains(out, "+++ b/b.md") {
+		t.Errorf("expected a diff for b.md only, got:\n%s", out)
+	}
+}
+
+func TestRun_FixAndFixDryRun_ReturnsError(t *testing.T) {
+	f := writeTempFile(t, "valid.md", "## Hello\n\nWorld.\n")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath: "/nonexistent/.gomarklint.json",
+		Args:       []string{f},
+		Fix:        true,
+		FixDryRun:  true,
+	})
+	if err == nil || errors.Is(err, ErrLintViolations) {
+		t.Errorf("expected a usage error, got: %v", err)
+	}
+}
+
+func TestRun_Baseline(t *testing.T) {
+	f := writeTempFile(t, "doc.md", "# Title\n\ntext\n")
+	bl := filepath.Join(filepath.Dir(f), "baseline.json")
+	run := func(opts Options) (string, error) {
+		var buf bytes.Buffer
+		opts.ConfigPath = "/nonexistent/.gomarklint.json"
+		opts.Args = []string{f}
+		opts.Baseline = bl
+		err := Run(&buf, opts)
+		return buf.String(), err
+	}
+
+	if out, err := run(Options{UpdateBaseline: true}); err != nil {
+		t.Fatalf("expected update to suppress every violation, got: %v\n%s", err, out)
+	}
+	out, err := run(Options{})
+	if err != nil || !strings.Contains(out, "Suppressed 1 issue(s)") {
+		t.Errorf("expected known violation suppressed, got: %v\n%s", err, out)
+	}
+
+	// A new violation is reported; the known one stays suppressed after
+	// moving down a line.
+	if err := os.WriteFile(f, []byte("\n# Title\n\n# Another\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(Options{})
+	if !errors.Is(err, ErrLintViolations) || strings.Contains(out, "First heading") || !strings.Contains(out, "Multiple H1") {
+		t.Errorf("expected only the new violation, got: %v\n%s", err, out)
+	}
+
+	// Fixing the known violation leaves its entry stale.
+	if err := os.WriteFile(f, []byte("## Title\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run(Options{})
+	if err != nil || !strings.Contains(out, "1 baseline entry no longer matches") {
+		t.Errorf("expected a stale entry, got: %v\n%s", err, out)
+	}
+}
+
+func TestRun_UpdateBaselineWithoutBaseline_ReturnsError(t *testing.T) {
+	f := writeTempFile(t, "doc.md", "## Title\n")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath:     "/nonexistent/.gomarklint.json",
+		Args:           []string{f},
+		UpdateBaseline: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--update-baseline requires --baseline") {
+		t.Errorf("expected flag error, got: %v", err)
+	}
+}
+
+func TestRun_MissingBaseline_ReturnsError(t *testing.T) {
+	f := writeTempFile(t, "doc.md", "## Title\n")
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{
+		ConfigPath: "/nonexistent/.gomarklint.json",
+		Args:       []string{f},
+		Baseline:   filepath.Join(t.TempDir(), "missing.json"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to read baseline") {
+		t.Errorf("expected read error, got: %v", err)
+	}
+}
+
+func TestRun_DiffBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Chdir(t.TempDir())
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll("docs", 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "# Legacy\n\ntext\n"
+	if err := os.WriteFile("docs/legacy.md", []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("docs/touched.md", []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q")
+	git("-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-q", "--allow-empty", "-m", "empty")
+	git("add", ".")
+	git("-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-q", "-m", "docs")
+
+	if err := os.WriteFile("docs/touched.md", []byte(legacy+"\n# Another\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err := Run(&buf, Options{ConfigPath: "/nonexistent/.gomarklint.json", Args: []string{"."}, DiffBase: "HEAD"})
+	out := buf.String()
+	if !errors.Is(err, ErrLintViolations) || !strings.Contains(out, "touched.md:5:1") {
+		t.Errorf("expected the violation on the changed line, got: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "legacy.md") || strings.Contains(out, "First heading") {
+		t.Errorf("expected violations outside changed lines dropped, got: %s", out)
+	}
+	if !strings.Contains(out, "Checked 1 file(s)") {
+		t.Errorf("expected only the changed file linted, got: %s", out)
+	}
+
+	buf.Reset()
+	err = Run(&buf, Options{ConfigPath: "/nonexistent/.gomarklint.json", Args: []string{"docs"}, DiffBase: "HEAD~1"})
+	if !errors.Is(err, ErrLintViolations) || !strings.Contains(buf.String(), "legacy.md:1:1") {
+		t.Errorf("expected files added since the base fully linted, got: %v\n%s", err, buf.String())
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	changes := gitdiff.Changes{
+		"README.md":                              nil,
+		filepath.Join("docs", "a.md"):            nil,
+		filepath.Join("docs", ".hidden", "b.md"): nil,
+		filepath.Join("docs", ".c.md"):           nil,
+		filepath.Join("other", "d.md"):           nil,
+	}
+	got := changedPaths(changes, []string{"./docs/", "README.md"})
+	want := []string{"README.md", filepath.Join("docs", ".c.md"), filepath.Join("docs", "a.md")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRun_Stdin(t *testing.T) {
+	cfgFile := writeTempFile(t, ".gomarklint.json", `{"ignore": ["vendor/**"]}`)
+	run := func(opts Options) (string, error) {
+		var buf bytes.Buffer
+		opts.ConfigPath = cfgFile
+		opts.Args = []string{StdinPath}
+		opts.Stdin = strings.NewReader("# Title\n\ntext\n")
+		err := Run(&buf, opts)
+		return buf.String(), err
+	}
+
+	out, err := run(Options{})
+	if !errors.Is(err, ErrLintViolations) || !strings.Contains(out, "<stdin>:1:1") {
+		t.Errorf("expected violation reported for <stdin>, got: %v\n%s", err, out)
+	}
+
+	out, err = run(Options{StdinFilename: "docs/page.md"})
+	if !errors.Is(err, ErrLintViolations) || !strings.Contains(out, "docs/page.md:1:1") || !strings.Contains(out, "Checked 1 file(s), 4 line(s)") {
+		t.Errorf("expected violation reported for the stdin filename, got: %v\n%s", err, out)
+	}
+
+	out, err = run(Options{StdinFilename: "vendor/page.md"})
+	if err != nil || !strings.Contains(out, "Checked 0 file(s)") {
+		t.Errorf("expected ignored stdin filename skipped, got: %v\n%s", err, out)
+	}
+
+	var buf bytes.Buffer
+	err = Run(&buf, Options{
+		ConfigPath:    cfgFile,
+		Args:          []string{StdinPath},
+		Stdin:         strings.NewReader("## Title\ntext\n"),
+		StdinFilename: "docs/page.md",
+		FixDryRun:     true,
+	})
+	out = buf.String()
+	if !errors.Is(err, ErrLintViolations) || !strings.Contains(out, "+++ b/docs/page.md") {
+		t.Errorf("expected a diff labelled with the stdin filename, got: %v\n%s", err, out)
+	}
+}
+
+func TestRun_Stdin_InvalidCombinations(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"other paths", Options{Args: []string{StdinPath, "README.md"}}, "cannot be combined with other paths"},
+		{"fix", Options{Args: []string{StdinPath}, Fix: true}, "--fix cannot be used with stdin"},
+		{"diff base", Options{Args: []string{StdinPath}, DiffBase: "HEAD"}, "cannot be used with stdin"},
+		{"filename without stdin", Options{Args: []string{"README.md"}, StdinFilename: "a.md"}, "--stdin-filename requires '-'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.ConfigPath = "/nonexistent/.gomarklint.json"
+			tt.opts.Stdin = strings.NewReader("")
+			var buf bytes.Buffer
+			err := Run(&buf, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Cache(t *testing.T) {
+	f := writeTempFile(t, "doc.md", "# Title\n\ntext\n")
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		err := Run(&buf, Options{ConfigPath: "/nonexistent/.gomarklint.json", Args: []string{f}, CacheDir: dir, Version: "test"})
+		if !errors.Is(err, ErrLintViolations) || !strings.Contains(buf.String(), "First heading should be level 2") {
+			t.Errorf("run %d: expected the violation reported, got: %v\n%s", i+1, err, buf.String())
+		}
+	}
+	entries, err := filepath.Glob(filepath.Join(dir, "results", "*", "*.json"))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected one cache entry, got %v (%v)", entries, err)
+	}
+}
+
 func TestRun_UsesConfigInclude(t *testing.T) {
 	f := writeTempFile(t, "valid.md", "## Hello\n\nWorld.\n")
 	cfgFile := writeTempFile(t, "include.json", `{"default":true,"rules":{},"include":["`+f+`"]}`)
@@ -185,7 +582,7 @@ func TestFormatOutput_WriterError(t *testing.T) {
 		Errors:       map[string][]rule.LintError{},
 		OrderedPaths: []string{},
 	}
-	err := formatOutput(&errorWriter{}, cfg, result, 1, time.Millisecond)
+	err := formatOutput(&errorWriter{}, cfg, result, 1, 0, baselineStats{}, time.Millisecond)
 	if err == nil {
 		t.Error("expected error from bad writer, got nil")
 	}
@@ -281,7 +678,7 @@ func TestFormatOutput(t *testing.T) {
 			OrderedPaths: []string{},
 		}
 		var buf bytes.Buffer
-		err := formatOutput(&buf, cfg, result, 1, time.Millisecond)
+		err := formatOutput(&buf, cfg, result, 1, 0, baselineStats{}, time.Millisecond)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -299,7 +696,7 @@ func TestFormatOutput(t *testing.T) {
 			TotalErrors:  1,
 		}
 		var buf bytes.Buffer
-		err := formatOutput(&buf, cfg, result, 1, time.Millisecond)
+		err := formatOutput(&buf, cfg, result, 1, 0, baselineStats{}, time.Millisecond)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -317,7 +714,7 @@ func TestFormatOutput(t *testing.T) {
 			TotalWarnings: 1,
 		}
 		var buf bytes.Buffer
-		err := formatOutput(&buf, cfg, result, 1, time.Millisecond)
+		err := formatOutput(&buf, cfg, result, 1, 0, baselineStats{}, time.Millisecond)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -336,7 +733,7 @@ func TestFormatOutput(t *testing.T) {
 			OrderedPaths: []string{},
 		}
 		var buf bytes.Buffer
-		err := formatOutput(&buf, cfgJSON, result, 1, time.Millisecond)
+		err := formatOutput(&buf, cfgJSON, result, 1, 0, baselineStats{}, time.Millisecond)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -354,7 +751,7 @@ func TestFormatOutput(t *testing.T) {
 			TotalLinksChecked: 5,
 		}
 		var buf bytes.Buffer
-		err := formatOutput(&buf, cfgLink, result, 1, time.Millisecond)
+		err := formatOutput(&buf, cfgLink, result, 1, 0, baselineStats{}, time.Millisecond)
 		if err != nil {
 			t.Fatal(err)
 		}
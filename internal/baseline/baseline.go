@@ -0,0 +1,218 @@
+// Package baseline records known violations so later runs report only new
+// ones.
+//
+// An entry identifies a violation by file, rule and a fingerprint of the
+// content of the line it is on, not by line number, so it keeps matching
+// when unrelated edits move the line.
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// Version is the version field of baseline files this package writes and
+// reads.
+const Version = 1
+
+// Baseline is the content of a baseline file.
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is one known violation, or Count identical ones.
+type Entry struct {
+	File        string `json:"file"`
+	Rule        string `json:"rule"`
+	Fingerprint string `json:"fingerprint"`
+	// Count is the number of violations with this file, rule and
+	// fingerprint. Zero means one.
+	Count int `json:"count,omitempty"`
+	// Message is the first of those violations' messages. It is kept for
+	// readers of the file and plays no part in matching.
+	Message string `json:"message"`
+}
+
+func (e Entry) count() int {
+	if e.Count < 1 {
+		return 1
+	}
+	return e.Count
+}
+
+type key struct {
+	file, rule, fingerprint string
+}
+
+func (e Entry) key() key { return key{e.File, e.Rule, e.Fingerprint} }
+
+// Lines returns the lines of the file at path, or nil when it cannot be
+// read.
+type Lines func(path string) []string
+
+// Fingerprint identifies the content of a line, ignoring surrounding
+// whitespace.
+func Fingerprint(line string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(line)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NormalizePath returns path in the form baseline entries store it.
+func NormalizePath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+func entryFor(e rule.LintError, lines Lines) Entry {
+	var text string
+	if ls := lines(e.File); e.Line >= 1 && e.Line <= len(ls) {
+		text = ls[e.Line-1]
+	}
+	return Entry{File: NormalizePath(e.File), Rule: e.Rule, Fingerprint: Fingerprint(text), Message: e.Message}
+}
+
+// Build returns a baseline holding every violation in details.
+func Build(details map[string][]rule.LintError, lines Lines) *Baseline {
+	index := map[key]int{}
+	b := &Baseline{Version: Version, Entries: []Entry{}}
+	for _, errs := range details {
+		for _, e := range errs {
+			entry := entryFor(e, lines)
+			if i, ok := index[entry.key()]; ok {
+				b.Entries[i].Count = b.Entries[i].count() + 1
+				continue
+			}
+			index[entry.key()] = len(b.Entries)
+			b.Entries = append(b.Entries, entry)
+		}
+	}
+	b.sort()
+	return b
+}
+
+func (b *Baseline) sort() {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Rule != c.Rule {
+			return a.Rule < c.Rule
+		}
+		if a.Fingerprint != c.Fingerprint {
+			return a.Fingerprint < c.Fingerprint
+		}
+		return a.Message < c.Message
+	})
+}
+
+// Update returns a baseline holding every violation in details, plus the
+// entries of b, which may be nil, for files that were not linted and still
+// exist.
+func (b *Baseline) Update(details map[string][]rule.LintError, linted []string, lines Lines) *Baseline {
+	updated := Build(details, lines)
+	if b == nil {
+		return updated
+	}
+	lintedSet := pathSet(linted)
+	for _, e := range b.Entries {
+		if !lintedSet[e.File] && exists(e.File) {
+			updated.Entries = append(updated.Entries, e)
+		}
+	}
+	updated.sort()
+	return updated
+}
+
+// Filter removes the violations b lists from details. It returns the
+// violations left, the number removed, and the stale entries: those for a
+// linted or deleted file that matched no violation, with Count reduced by
+// the matches they did have.
+func (b *Baseline) Filter(details map[string][]rule.LintError, linted []string, lines Lines) (map[string][]rule.LintError, int, []Entry) {
+	remaining := map[key]int{}
+	for _, e := range b.Entries {
+		remaining[e.key()] += e.count()
+	}
+
+	kept := make(map[string][]rule.LintError, len(details))
+	suppressed := 0
+	for path, errs := range details {
+		var left []rule.LintError
+		for _, e := range errs {
+			k := entryFor(e, lines).key()
+			if remaining[k] > 0 {
+				remaining[k]--
+				suppressed++
+				continue
+			}
+			left = append(left, e)
+		}
+		kept[path] = left
+	}
+
+	lintedSet := pathSet(linted)
+	var stale []Entry
+	for _, e := range b.Entries {
+		n := remaining[e.key()]
+		if n == 0 || (!lintedSet[e.File] && exists(e.File)) {
+			continue
+		}
+		// Report a key split over several entries only once.
+		remaining[e.key()] = 0
+		e.Count = n
+		if n == 1 {
+			e.Count = 0
+		}
+		stale = append(stale, e)
+	}
+	return kept, suppressed, stale
+}
+
+func pathSet(paths []string) map[string]bool {
+	set := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		set[NormalizePath(p)] = true
+	}
+	return set
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(filepath.FromSlash(path))
+	return err == nil
+}
+
+// Load reads a baseline file.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s (expected %d)", b.Version, path, Version)
+	}
+	return &b, nil
+}
+
+// Write saves b to path, creating or replacing the file.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
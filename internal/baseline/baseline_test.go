@@ -0,0 +1,163 @@
+package baseline
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// linesOf returns a Lines serving fixed file contents.
+func linesOf(files map[string]string) Lines {
+	return func(path string) []string {
+		content, ok := files[path]
+		if !ok {
+			return nil
+		}
+		return strings.Split(content, "\n")
+	}
+}
+
+func lintErr(path string, line int, ruleName string) rule.LintError {
+	return rule.LintError{File: path, Line: line, Rule: ruleName, Message: ruleName + ": message", Severity: "error"}
+}
+
+func TestBuild(t *testing.T) {
+	lines := linesOf(map[string]string{"a.md": "# Title\n\nbad\nbad\n"})
+	b := Build(map[string][]rule.LintError{
+		"a.md": {lintErr("a.md", 1, "heading-level"), lintErr("a.md", 3, "x"), lintErr("a.md", 4, "x")},
+	}, lines)
+
+	want := []Entry{
+		{File: "a.md", Rule: "heading-level", Fingerprint: Fingerprint("# Title"), Message: "heading-level: message"},
+		{File: "a.md", Rule: "x", Fingerprint: Fingerprint("bad"), Count: 2, Message: "x: message"},
+	}
+	if b.Version != Version || !reflect.DeepEqual(b.Entries, want) {
+		t.Errorf("got %+v, want entries %+v", b, want)
+	}
+}
+
+func TestFingerprint_IgnoresSurroundingWhitespace(t *testing.T) {
+	if Fingerprint("  text\r") != Fingerprint("text") {
+		t.Error("expected surrounding whitespace to be ignored")
+	}
+	if Fingerprint("text") == Fingerprint("other") {
+		t.Error("expected different content to differ")
+	}
+}
+
+func TestFilter_SurvivesMovedLines(t *testing.T) {
+	before := linesOf(map[string]string{"a.md": "# Title\n\nbad\n"})
+	b := Build(map[string][]rule.LintError{"a.md": {lintErr("a.md", 1, "heading-level"), lintErr("a.md", 3, "x")}}, before)
+
+	// Two lines were inserted above "bad", and a new violation appeared.
+	after := linesOf(map[string]string{"a.md": "# Title\n\nnew\n\nbad\nworse\n"})
+	details := map[string][]rule.LintError{"a.md": {lintErr("a.md", 1, "heading-level"), lintErr("a.md", 5, "x"), lintErr("a.md", 6, "x")}}
+	kept, suppressed, stale := b.Filter(details, []string{"a.md"}, after)
+
+	if suppressed != 2 || len(stale) != 0 {
+		t.Errorf("suppressed=%d stale=%v, want 2 and none", suppressed, stale)
+	}
+	if len(kept["a.md"]) != 1 || kept["a.md"][0].Line != 6 {
+		t.Errorf("expected only the new violation, got %+v", kept["a.md"])
+	}
+}
+
+func TestFilter_Stale(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.md")
+	if err := os.WriteFile(kept, []byte("bad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	linted := filepath.Join(dir, "linted.md")
+	deleted := filepath.Join(dir, "deleted.md")
+	b := &Baseline{Version: Version, Entries: []Entry{
+		{File: NormalizePath(linted), Rule: "x", Fingerprint: Fingerprint("bad"), Count: 3},
+		{File: NormalizePath(kept), Rule: "x", Fingerprint: Fingerprint("bad")},
+		{File: NormalizePath(deleted), Rule: "x", Fingerprint: Fingerprint("bad")},
+	}}
+
+	lines := linesOf(map[string]string{linted: "bad\n"})
+	_, suppressed, stale := b.Filter(map[string][]rule.LintError{linted: {lintErr(linted, 1, "x")}}, []string{linted}, lines)
+
+	if suppressed != 1 {
+		t.Errorf("suppressed = %d, want 1", suppressed)
+	}
+	want := []Entry{
+		{File: NormalizePath(linted), Rule: "x", Fingerprint: Fingerprint("bad"), Count: 2},
+		{File: NormalizePath(deleted), Rule: "x", Fingerprint: Fingerprint("bad")},
+	}
+	if !reflect.DeepEqual(stale, want) {
+		t.Errorf("stale = %+v, want %+v", stale, want)
+	}
+}
+
+func TestUpdate_KeepsEntriesOfFilesNotLinted(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "other.md")
+	if err := os.WriteFile(other, []byte("bad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := &Baseline{Version: Version, Entries: []Entry{
+		{File: "a.md", Rule: "x", Fingerprint: Fingerprint("fixed since")},
+		{File: NormalizePath(other), Rule: "x", Fingerprint: Fingerprint("bad")},
+		{File: NormalizePath(filepath.Join(dir, "gone.md")), Rule: "x", Fingerprint: Fingerprint("bad")},
+	}}
+	lines := linesOf(map[string]string{"a.md": "new\n"})
+	got := old.Update(map[string][]rule.LintError{"a.md": {lintErr("a.md", 1, "y")}}, []string{"a.md"}, lines)
+
+	want := []Entry{
+		{File: NormalizePath(other), Rule: "x", Fingerprint: Fingerprint("bad")},
+		{File: "a.md", Rule: "y", Fingerprint: Fingerprint("new"), Message: "y: message"},
+	}
+	if !reflect.DeepEqual(got.Entries, want) {
+		t.Errorf("got %+v, want %+v", got.Entries, want)
+	}
+
+	var none *Baseline
+	if got := none.Update(map[string][]rule.LintError{}, nil, lines); len(got.Entries) != 0 {
+		t.Errorf("expected an empty baseline, got %+v", got.Entries)
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	b := &Baseline{Version: Version, Entries: []Entry{{File: "a.md", Rule: "x", Fingerprint: "0123456789abcdef", Count: 2, Message: "x: m"}}}
+	if err := b.Write(path); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("got %+v, want %+v", got, b)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bad); err == nil || !strings.Contains(err.Error(), "failed to parse baseline") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version": 2, "entries": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(future); err == nil || !strings.Contains(err.Error(), "unsupported baseline version 2") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Package cache stores the findings for each linted file on disk, so a later
+// run can skip rules on files that have not changed, and the results of
+// external link checks, so a later run need not request the URLs again.
+//
+// Entries are keyed by a hash of everything the findings depend on, so they
+// never need invalidating: a change to the file, the config or gomarklint
+// itself produces a different key. Old entries are never read again, and
+// Open removes those not read for maxEntryAge. Link results expire too; see
+// SaveLinks.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// resultsDir is the subdirectory of the cache directory holding findings.
+const resultsDir = "results"
+
+const (
+	// maxEntryAge is how long an entry is kept after it was last written or
+	// read.
+	maxEntryAge = 30 * 24 * time.Hour
+	// pruneInterval is how often Open looks for old entries. pruneStamp, in
+	// the cache directory, records when it last did.
+	pruneInterval = 24 * time.Hour
+	pruneStamp    = "last-prune"
+)
+
+// Cache is a directory of cached findings. It is safe for concurrent use,
+// including by several processes sharing the directory.
+type Cache struct {
+	dir  string
+	salt string
+}
+
+// DefaultDir returns the cache directory used when none is given:
+// gomarklint under the user cache directory, which is $XDG_CACHE_HOME or
+// ~/.cache on Linux.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gomarklint"), nil
+}
+
+// Open returns the cache in dir, creating the directory if needed. salt is
+// mixed into every key; pass Salt(version). At most once per pruneInterval,
+// it removes the entries not read for maxEntryAge.
+func Open(dir, salt string) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, resultsDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	c := &Cache{dir: dir, salt: salt}
+	c.prune(time.Now())
+	return c, nil
+}
+
+// prune removes the entries last written or read before now-maxEntryAge,
+// unless the cache was pruned within pruneInterval. The stamp is updated
+// first, so processes opening the cache together do not all walk it.
+// Errors only leave entries behind, so they are ignored.
+func (c *Cache) prune(now time.Time) {
+	stamp := filepath.Join(c.dir, pruneStamp)
+	if info, err := os.Stat(stamp); err == nil && now.Sub(info.ModTime()) < pruneInterval {
+		return
+	}
+	if err := writeFile(stamp, nil); err != nil {
+		return
+	}
+	_ = os.Chtimes(stamp, now, now)
+	cutoff := now.Add(-maxEntryAge)
+	_ = filepath.WalkDir(filepath.Join(c.dir, resultsDir), func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().Before(cutoff) {
+			_ = os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// Salt identifies the gomarklint build for cache keys. Development builds
+// all share one version string, so for them the salt also includes the size
+// and modification time of the running executable.
+func Salt(version string) string {
+	if version != "dev" {
+		return version
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return version
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return version
+	}
+	return version + "-" + strconv.FormatInt(info.Size(), 10) + "-" + strconv.FormatInt(info.ModTime().UnixNano(), 10)
+}
+
+// Key hashes parts, together with the cache's salt, into an entry key.
+func (c *Cache) Key(parts ...string) string {
+	h := sha256.New()
+	h.Write([]byte(c.salt))
+	for _, p := range parts {
+		// The length prefix keeps ("ab", "c") and ("a", "bc") apart.
+		fmt.Fprintf(h, "\x00%d\x00%s", len(p), p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, resultsDir, key[:2], key+".json")
+}
+
+// Get returns the findings stored under key. A missing or unreadable entry
+// is a miss. A hit updates the entry's modification time, so that prune
+// keeps entries that are still read.
+func (c *Cache) Get(key string) ([]rule.LintError, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var errs []rule.LintError
+	if err := json.Unmarshal(data, &errs); err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return errs, true
+}
+
+// Put stores errs under key. The entry is written to a temporary file and
+// renamed into place, so a concurrent Get never sees a partial entry.
+func (c *Cache) Put(key string, errs []rule.LintError) error {
+	if errs == nil {
+		errs = []rule.LintError{}
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// writeFile writes data to a temporary file and renames it to path, so a
+// concurrent reader never sees a partial file.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func TestKey(t *testing.T) {
+	c := &Cache{salt: "v1"}
+	if c.Key("a", "b") != c.Key("a", "b") {
+		t.Error("expected equal parts to give equal keys")
+	}
+	if c.Key("ab", "c") == c.Key("a", "bc") {
+		t.Error("expected part boundaries to matter")
+	}
+	if c.Key("a") == (&Cache{salt: "v2"}).Key("a") {
+		t.Error("expected the salt to matter")
+	}
+}
+
+func TestGetPut(t *testing.T) {
+	c, err := Open(t.TempDir(), "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := c.Key("doc.md", "content")
+	if _, ok := c.Get(key); ok {
+		t.Fatal("expected a miss before Put")
+	}
+
+	errs := []rule.LintError{{
+		File: "doc.md", Line: 2, Column: 3, EndLine: 2, EndColumn: 5, Rule: "x", Message: "x: m", Severity: "error",
+		Fix: &rule.Fix{Edits: []rule.Edit{{Line: 2, Column: 3, EndLine: 2, EndColumn: 5, NewText: "y"}}},
+	}}
+	if err := c.Put(key, errs); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, ok := c.Get(key)
+	if !ok || !reflect.DeepEqual(got, errs) {
+		t.Errorf("got %+v, %v, want %+v", got, ok, errs)
+	}
+
+	// A file with no findings is cached too.
+	clean := c.Key("clean.md", "")
+	if err := c.Put(clean, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.Get(clean); !ok || len(got) != 0 {
+		t.Errorf("expected a cached empty result, got %+v, %v", got, ok)
+	}
+}
+
+func TestGet_CorruptEntryIsAMiss(t *testing.T) {
+	c, err := Open(t.TempDir(), "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := c.Key("doc.md")
+	if err := os.MkdirAll(filepath.Dir(c.path(key)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.path(key), []byte("{truncated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(key); ok {
+		t.Error("expected a corrupt entry to be a miss")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	c, err := Open(t.TempDir(), "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey, newKey := c.Key("old.md"), c.Key("new.md")
+	for _, key := range []string{oldKey, newKey} {
+		if err := c.Put(key, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	old := now.Add(-maxEntryAge - time.Hour)
+	if err := os.Chtimes(c.path(oldKey), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Open pruned just now, so this does nothing.
+	c.prune(now)
+	if _, err := os.Stat(c.path(oldKey)); err != nil {
+		t.Fatalf("expected no pruning within pruneInterval, got %v", err)
+	}
+
+	c.prune(now.Add(pruneInterval))
+	if _, err := os.Stat(c.path(oldKey)); !os.IsNotExist(err) {
+		t.Errorf("expected the old entry removed, got %v", err)
+	}
+	if _, ok := c.Get(newKey); !ok {
+		t.Error("expected the recent entry kept")
+	}
+}
+
+func TestGet_RefreshesEntry(t *testing.T) {
+	c, err := Open(t.TempDir(), "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := c.Key("doc.md")
+	if err := c.Put(key, nil); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-maxEntryAge - time.Hour)
+	if err := os.Chtimes(c.path(key), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(key); !ok {
+		t.Fatal("expected a hit")
+	}
+	info, err := os.Stat(c.path(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().After(old) {
+		t.Errorf("expected Get to refresh the modification time, got %v", info.ModTime())
+	}
+}
+
+func TestOpen_Error(t *testing.T) {
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(notDir, "v1"); err == nil || !strings.Contains(err.Error(), "failed to create cache directory") {
+		t.Errorf("expected a create error, got %v", err)
+	}
+}
+
+func TestSalt(t *testing.T) {
+	if got := Salt("v3.1.0"); got != "v3.1.0" {
+		t.Errorf("Salt(release) = %q", got)
+	}
+	if got := Salt("dev"); !strings.HasPrefix(got, "dev-") {
+		t.Errorf("expected the executable to be part of a dev salt, got %q", got)
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// linksFile is the file in the cache directory holding external-link results,
+// keyed by URL.
+const linksFile = "links.json"
+
+// Links returns the external-link results saved in the cache. A missing or
+// unreadable file holds none.
+func (c *Cache) Links() map[string]rule.CachedLink {
+	links := map[string]rule.CachedLink{}
+	data, err := os.ReadFile(filepath.Join(c.dir, linksFile))
+	if err != nil {
+		return links
+	}
+	if err := json.Unmarshal(data, &links); err != nil {
+		return map[string]rule.CachedLink{}
+	}
+	return links
+}
+
+// SaveLinks merges links into the saved results and writes them back,
+// keeping only those keep accepts. The newer check of a URL wins, so several
+// processes can share the cache: the file is replaced atomically, and at
+// worst a result saved by another process in the meantime is lost and its
+// URL checked again.
+func (c *Cache) SaveLinks(links map[string]rule.CachedLink, keep func(rule.CachedLink) bool) error {
+	merged := c.Links()
+	for url, l := range links {
+		if saved, ok := merged[url]; !ok || l.CheckedAt.After(saved.CheckedAt) {
+			merged[url] = l
+		}
+	}
+	for url, l := range merged {
+		if !keep(l) {
+			delete(merged, url)
+		}
+	}
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(c.dir, linksFile), data)
+}
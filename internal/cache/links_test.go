@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func TestSaveLinks(t *testing.T) {
+	c, err := Open(t.TempDir(), "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Links(); len(got) != 0 {
+		t.Fatalf("expected no links before saving, got %v", got)
+	}
+	keepAll := func(rule.CachedLink) bool { return true }
+	now := time.Now().UTC().Truncate(time.Second)
+
+	first := map[string]rule.CachedLink{
+		"https://a.example": {Status: 200, CheckedAt: now},
+		"https://b.example": {Status: 404, CheckedAt: now},
+	}
+	if err := c.SaveLinks(first, keepAll); err != nil {
+		t.Fatalf("SaveLinks: %v", err)
+	}
+
+	// Another process saving an older result for a URL does not replace the
+	// newer one, and its other results are merged in.
+	second := map[string]rule.CachedLink{
+		"https://a.example": {Error: "timeout", CheckedAt: now.Add(-time.Hour)},
+		"https://c.example": {Status: 200, CheckedAt: now},
+	}
+	if err := c.SaveLinks(second, keepAll); err != nil {
+		t.Fatal(err)
+	}
+	got := c.Links()
+	if len(got) != 3 || got["https://a.example"].Status != 200 || !got["https://a.example"].CheckedAt.Equal(now) {
+		t.Errorf("unexpected merge: %+v", got)
+	}
+
+	// Results keep rejects are dropped.
+	if err := c.SaveLinks(nil, func(l rule.CachedLink) bool { return !l.Failed() }); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Links(); len(got) != 2 || got["https://b.example"] != (rule.CachedLink{}) {
+		t.Errorf("expected the failure to be dropped, got %+v", got)
+	}
+}
+
+func TestLinks_CorruptFileIsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, linksFile), []byte("{truncated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Links(); len(got) != 0 {
+		t.Errorf("expected a corrupt file to hold no links, got %v", got)
+	}
+}
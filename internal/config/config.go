@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 )
 
 const DefaultNoTrailingPunctuation = ".,;:!"
@@ -24,17 +25,24 @@ const DefaultConfigJSON = `{
     "blanks-around-headings": true,
     "no-bare-urls": true,
     "no-empty-links": true,
+    "no-undefined-references": { "enabled": false, "checkShortcuts": false },
+    "no-unused-definitions": false,
+    "no-duplicate-definitions": false,
     "no-emphasis-as-heading": true,
     "blanks-around-lists": true,
     "blanks-around-fences": true,
     "no-hard-tabs": true,
     "no-trailing-punctuation": { "punctuation": ".,;:!" },
+    "no-trailing-spaces": { "enabled": false, "brSpaces": 2, "allowInContainers": false },
     "consistent-code-fence": { "style": "consistent" },
     "consistent-emphasis-style": { "style": "consistent" },
     "consistent-list-marker": { "style": "consistent" },
+    "table-formatting": { "enabled": false, "pipeStyle": "consistent", "alignColumns": false },
     "max-line-length": { "enabled": false, "lineLength": 80 },
     "external-link": { "enabled": false, "severity": "error", "timeoutSeconds": 5, "maxConcurrency": 10, "maxRetries": 2, "perHostConcurrency": 2, "perHostIntervalMs": 3000, "skipPatterns": [] },
-    "link-fragments": { "enabled": true, "slug-algorithm": "github" }
+    "link-fragments": { "enabled": true, "slug-algorithm": "github" },
+    "relative-links": { "enabled": false, "slug-algorithm": "github" },
+    "local-assets": { "enabled": false, "siteRoot": "", "maxBytes": 0 }
   },
   "include": ["README.md", "testdata"],
   "ignore": [],
@@ -145,13 +153,41 @@ func (r *RuleConfig) applyObjectField(k string, v json.RawMessage) error {
 	return nil
 }
 
+// PluginConfig declares a rule implemented by an external executable.
+type PluginConfig struct {
+	// Name is the rule name the plugin's findings are reported under and
+	// its key in "rules".
+	Name string `json:"name"`
+	// Command is the executable and its arguments.
+	Command []string `json:"command"`
+	// TimeoutSeconds bounds one run of the plugin on one file. Zero means
+	// the default of 10 seconds.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// CustomRuleConfig declares a rule that reports every match of a regular
+// expression.
+type CustomRuleConfig struct {
+	// Pattern is an RE2 regular expression.
+	Pattern string `json:"pattern"`
+	// Message is reported for each match. Empty reports the matched text.
+	Message string `json:"message,omitempty"`
+	// Scope selects the text Pattern is matched against: "text" (the
+	// default), "code", "heading" or "link-url".
+	Scope string `json:"scope,omitempty"`
+}
+
 type Config struct {
-	Default      bool                   `json:"default"`
-	Rules        map[string]*RuleConfig `json:"rules"`
-	Include      []string               `json:"include"`
-	Ignore       []string               `json:"ignore"`
-	OutputFormat string                 `json:"output"`
-	MinSeverity  RuleSeverity           `json:"-"`
+	Default      bool                        `json:"default"`
+	Rules        map[string]*RuleConfig      `json:"rules"`
+	Plugins      []PluginConfig              `json:"plugins,omitempty"`
+	CustomRules  map[string]CustomRuleConfig `json:"custom-rules,omitempty"`
+	Include      []string                    `json:"include"`
+	Ignore       []string                    `json:"ignore"`
+	OutputFormat string                      `json:"output"`
+	// Jobs is the number of files linted at once. Zero means GOMAXPROCS.
+	Jobs        int          `json:"jobs,omitempty"`
+	MinSeverity RuleSeverity `json:"-"`
 }
 
 func (c *Config) IsEnabled(name string) bool {
@@ -162,6 +198,29 @@ func (c *Config) IsEnabled(name string) bool {
 	return rc.Enabled
 }
 
+// IsDeclaredRuleEnabled reports whether the plugin or custom rule called
+// name runs. Declaring such a rule enables it whatever "default" says; an
+// entry in "rules" can still turn it off.
+func (c *Config) IsDeclaredRuleEnabled(name string) bool {
+	rc, ok := c.Rules[name]
+	return !ok || rc == nil || rc.Enabled
+}
+
+// DeclaredRuleNames returns the names of the config's plugins, in order,
+// followed by its custom rules, sorted.
+func (c *Config) DeclaredRuleNames() []string {
+	var names []string
+	for _, p := range c.Plugins {
+		names = append(names, p.Name)
+	}
+	custom := make([]string, 0, len(c.CustomRules))
+	for name := range c.CustomRules {
+		custom = append(custom, name)
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
 func (c *Config) RuleOptions(name string) map[string]interface{} {
 	rc, ok := c.Rules[name]
 	if !ok || rc == nil || rc.Options == nil {
@@ -182,6 +241,12 @@ func enabledRule() *RuleConfig {
 	return &RuleConfig{Enabled: true, Severity: SeverityError, Options: map[string]interface{}{}}
 }
 
+// disabledRule is an opt-in rule: off until the config enables it, and then
+// an error.
+func disabledRule() *RuleConfig {
+	return &RuleConfig{Enabled: false, Severity: SeverityError, Options: map[string]interface{}{}}
+}
+
 func Default() Config {
 	return Config{
 		Default: true,
@@ -197,15 +262,27 @@ func Default() Config {
 			"blanks-around-headings":  enabledRule(),
 			"no-bare-urls":            enabledRule(),
 			"no-empty-links":          enabledRule(),
-			"no-emphasis-as-heading":  enabledRule(),
-			"blanks-around-lists":     enabledRule(),
-			"blanks-around-fences":    enabledRule(),
-			"no-hard-tabs":            enabledRule(),
+			"no-undefined-references": {
+				Enabled:  false,
+				Severity: SeverityError,
+				Options:  map[string]interface{}{"checkShortcuts": false},
+			},
+			"no-unused-definitions":    disabledRule(),
+			"no-duplicate-definitions": disabledRule(),
+			"no-emphasis-as-heading":   enabledRule(),
+			"blanks-around-lists":      enabledRule(),
+			"blanks-around-fences":     enabledRule(),
+			"no-hard-tabs":             enabledRule(),
 			"no-trailing-punctuation": {
 				Enabled:  true,
 				Severity: SeverityError,
 				Options:  map[string]interface{}{"punctuation": DefaultNoTrailingPunctuation},
 			},
+			"no-trailing-spaces": {
+				Enabled:  false,
+				Severity: SeverityError,
+				Options:  map[string]interface{}{"brSpaces": 2, "allowInContainers": false},
+			},
 			"consistent-code-fence": {
 				Enabled:  true,
 				Severity: SeverityError,
@@ -221,6 +298,11 @@ func Default() Config {
 				Severity: SeverityError,
 				Options:  map[string]interface{}{"style": "consistent"},
 			},
+			"table-formatting": {
+				Enabled:  false,
+				Severity: SeverityError,
+				Options:  map[string]interface{}{"pipeStyle": "consistent", "alignColumns": false},
+			},
 			"max-line-length": {
 				Enabled:  false,
 				Severity: SeverityOff,
@@ -241,6 +323,16 @@ func Default() Config {
 				Severity: SeverityError,
 				Options:  map[string]interface{}{"slug-algorithm": "github"},
 			},
+			"relative-links": {
+				Enabled:  false,
+				Severity: SeverityError,
+				Options:  map[string]interface{}{"slug-algorithm": "github"},
+			},
+			"local-assets": {
+				Enabled:  false,
+				Severity: SeverityError,
+				Options:  map[string]interface{}{"siteRoot": "", "maxBytes": 0},
+			},
 		},
 		Include:      []string{"README.md", "testdata"},
 		Ignore:       []string{},
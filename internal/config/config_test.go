@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -357,6 +358,58 @@ func TestIsEnabled(t *testing.T) {
 	}
 }
 
+func TestIsDeclaredRuleEnabled(t *testing.T) {
+	cfg := Config{
+		Default: false,
+		Rules: map[string]*RuleConfig{
+			"off-plugin": {Enabled: false, Severity: SeverityOff},
+			"nil-plugin": nil,
+		},
+	}
+	if cfg.IsDeclaredRuleEnabled("off-plugin") {
+		t.Error("expected off-plugin to be disabled")
+	}
+	if !cfg.IsDeclaredRuleEnabled("nil-plugin") || !cfg.IsDeclaredRuleEnabled("unlisted") {
+		t.Error("expected plugins without a rules entry to run regardless of Default")
+	}
+}
+
+func TestParse_Plugins(t *testing.T) {
+	cfg, err := Parse([]byte(`{"plugins": [{"name": "house-style", "command": ["./lint-rules.py", "-v"], "timeoutSeconds": 30}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []PluginConfig{{Name: "house-style", Command: []string{"./lint-rules.py", "-v"}, TimeoutSeconds: 30}}
+	if !reflect.DeepEqual(cfg.Plugins, want) {
+		t.Errorf("Plugins = %+v, want %+v", cfg.Plugins, want)
+	}
+	if _, err := Parse([]byte(`{"plugins": [{"name": "p", "cmd": ["x"]}]}`)); err == nil {
+		t.Error("expected error for an unknown plugin field")
+	}
+}
+
+func TestParse_CustomRules(t *testing.T) {
+	cfg, err := Parse([]byte(`{"custom-rules": {"no-internal-hosts": {"pattern": "corp\\.internal", "message": "internal host", "scope": "link-url"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]CustomRuleConfig{"no-internal-hosts": {Pattern: `corp\.internal`, Message: "internal host", Scope: "link-url"}}
+	if !reflect.DeepEqual(cfg.CustomRules, want) {
+		t.Errorf("CustomRules = %+v, want %+v", cfg.CustomRules, want)
+	}
+}
+
+func TestDeclaredRuleNames(t *testing.T) {
+	cfg := Config{
+		Plugins:     []PluginConfig{{Name: "z-plugin"}, {Name: "a-plugin"}},
+		CustomRules: map[string]CustomRuleConfig{"no-foo": {}, "no-bar": {}},
+	}
+	want := []string{"z-plugin", "a-plugin", "no-bar", "no-foo"}
+	if got := cfg.DeclaredRuleNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeclaredRuleNames() = %v, want %v", got, want)
+	}
+}
+
 func TestIsEnabled_NilRuleEntry(t *testing.T) {
 	cfg := Config{
 		Default: true,
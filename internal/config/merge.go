@@ -33,13 +33,18 @@ func MergeFlags(cfg Config, cmd *cobra.Command, flags FlagValues) Config {
 }
 
 func Validate(cfg Config) error {
-	if cfg.OutputFormat != "text" && cfg.OutputFormat != "json" {
-		return fmt.Errorf("invalid output format: %q (must be 'text' or 'json')", cfg.OutputFormat)
+	switch cfg.OutputFormat {
+	case "text", "json", "sarif", "junit":
+	default:
+		return fmt.Errorf("invalid output format: %q (must be 'text', 'json', 'sarif' or 'junit')", cfg.OutputFormat)
 	}
 	switch cfg.MinSeverity {
 	case SeverityWarning, SeverityError:
 	default:
 		return fmt.Errorf("invalid severity: %q (must be 'warning' or 'error')", cfg.MinSeverity)
 	}
+	if cfg.Jobs < 0 {
+		return fmt.Errorf("invalid jobs: %d (must be 0 or more)", cfg.Jobs)
+	}
 	return nil
 }
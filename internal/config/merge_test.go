@@ -136,6 +136,20 @@ func TestValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("ValidSARIFFormat", func(t *testing.T) {
+		cfg := Config{OutputFormat: "sarif", MinSeverity: SeverityWarning}
+		if err := Validate(cfg); err != nil {
+			t.Errorf("unexpected error for valid sarif format: %v", err)
+		}
+	})
+
+	t.Run("ValidJUnitFormat", func(t *testing.T) {
+		cfg := Config{OutputFormat: "junit", MinSeverity: SeverityWarning}
+		if err := Validate(cfg); err != nil {
+			t.Errorf("unexpected error for valid junit format: %v", err)
+		}
+	})
+
 	t.Run("InvalidOutputFormat", func(t *testing.T) {
 		cfg := Config{OutputFormat: "xml", MinSeverity: SeverityWarning}
 		if err := Validate(cfg); err == nil {
@@ -149,4 +163,11 @@ func TestValidate(t *testing.T) {
 			t.Error("expected error for invalid severity")
 		}
 	})
+
+	t.Run("NegativeJobs", func(t *testing.T) {
+		cfg := Config{OutputFormat: "text", MinSeverity: SeverityWarning, Jobs: -1}
+		if err := Validate(cfg); err == nil {
+			t.Error("expected error for negative jobs")
+		}
+	})
 }
@@ -6,7 +6,12 @@ import (
 	"strings"
 )
 
-func ExpandPaths(paths []string, ignorePatterns []string) []string {
+// ExpandPaths returns the Markdown files named by paths, walking directories.
+// Paths matching ignorePatterns are left out, as are files and directories
+// that a .gomarklintignore file excludes within a walked directory, or, when
+// gitignore is true, a .gitignore or .ignore file. Files named directly are
+// not checked against ignore files.
+func ExpandPaths(paths []string, ignorePatterns []string, gitignore bool) []string {
 	var results []string
 
 	for _, p := range paths {
@@ -16,7 +21,7 @@ func ExpandPaths(paths []string, ignorePatterns []string) []string {
 		}
 
 		if info.IsDir() {
-			results = append(results, expandDirectory(p, ignorePatterns)...)
+			results = append(results, expandDirectory(p, ignorePatterns, ignoreFileNames(gitignore))...)
 		} else if strings.HasSuffix(info.Name(), ".md") {
 			if !ShouldIgnore(p, ignorePatterns) {
 				results = append(results, p)
@@ -27,22 +32,37 @@ func ExpandPaths(paths []string, ignorePatterns []string) []string {
 	return results
 }
 
-func expandDirectory(root string, ignorePatterns []string) []string {
+func expandDirectory(root string, ignorePatterns []string, ignoreNames []string) []string {
 	var results []string
 
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	// stacks holds the ignore files in effect in each directory walked.
+	stacks := map[string]ignoreStack{}
+
 	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
 
+		rel, _ := filepath.Rel(root, path)
+		abs := filepath.Join(absRoot, rel)
+		parent := stacks[filepath.Dir(abs)]
+		if path == root {
+			parent = ancestorIgnores(absRoot, ignoreNames)
+		}
+
 		if d.IsDir() {
-			if shouldSkipDirectory(path, root, d.Name()) {
+			if shouldSkipDirectory(path, root, d.Name()) || (path != root && parent.ignored(abs, true)) {
 				return filepath.SkipDir
 			}
+			stacks[abs] = parent.withDir(abs, ignoreNames)
 			return nil
 		}
 
-		if isMarkdownFile(d.Name()) && !ShouldIgnore(path, ignorePatterns) {
+		if isMarkdownFile(d.Name()) && !ShouldIgnore(path, ignorePatterns) && !parent.ignored(abs, false) {
 			results = append(results, path)
 		}
 		return nil
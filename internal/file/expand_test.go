@@ -68,7 +68,7 @@ func TestExpandPaths(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ExpandPaths(tt.input, []string{})
+			got := ExpandPaths(tt.input, []string{}, true)
 
 			var gotEnds []string
 			for _, path := range got {
@@ -95,7 +95,7 @@ func TestExpandPaths(t *testing.T) {
 		}()
 
 		// Unreadable subdirectory should be silently skipped, not error.
-		got := ExpandPaths([]string{base}, []string{})
+		got := ExpandPaths([]string{base}, []string{}, true)
 		if len(got) != 0 {
 			t.Errorf("expected no files from unreadable directory, got %v", got)
 		}
@@ -0,0 +1,237 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Ignore files read during directory expansion. GitignoreFiles are skipped
+// when gitignore support is turned off; ToolIgnoreFile always applies.
+var (
+	GitignoreFiles = []string{".gitignore", ".ignore"}
+	ToolIgnoreFile = ".gomarklintignore"
+)
+
+// ignoreRule is one pattern line of an ignore file.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreFile is the rules of one ignore file. They match paths relative to
+// dir, an absolute path.
+type ignoreFile struct {
+	dir   string
+	rules []ignoreRule
+}
+
+// ignoreStack is the ignore files that apply in a directory, outermost first,
+// so later rules take precedence as in git.
+type ignoreStack []ignoreFile
+
+// ignored reports whether the absolute path is excluded: the last rule that
+// matches it decides, and a negated rule re-includes it.
+func (s ignoreStack) ignored(path string, isDir bool) bool {
+	ignored := false
+	for _, f := range s {
+		rel, err := filepath.Rel(f.dir, path)
+		rel = filepath.ToSlash(rel)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		for _, r := range f.rules {
+			if (!r.dirOnly || isDir) && r.re.MatchString(rel) {
+				ignored = !r.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// withDir returns s followed by the ignore files found in dir, an absolute
+// path. s itself is never modified, so sibling directories can share it.
+func (s ignoreStack) withDir(dir string, names []string) ignoreStack {
+	var added []ignoreFile
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if rules := parseIgnoreRules(string(data)); len(rules) > 0 {
+			added = append(added, ignoreFile{dir: dir, rules: rules})
+		}
+	}
+	if len(added) == 0 {
+		return s
+	}
+	return append(s[:len(s):len(s)], added...)
+}
+
+// ignoreFileNames returns the ignore files to read, in increasing precedence.
+func ignoreFileNames(gitignore bool) []string {
+	if !gitignore {
+		return []string{ToolIgnoreFile}
+	}
+	return append(append([]string(nil), GitignoreFiles...), ToolIgnoreFile)
+}
+
+// ancestorIgnores returns the ignore files above root, an absolute directory,
+// that apply inside it: those up to the root of the enclosing git repository,
+// or, outside a repository, those up to the working directory when root is
+// below it.
+func ancestorIgnores(root string, names []string) ignoreStack {
+	if isRepoRoot(root) || filepath.Dir(root) == root {
+		return nil
+	}
+	var dirs []string
+	for dir := filepath.Dir(root); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+		if isRepoRoot(dir) {
+			return stackOf(dirs, names)
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return stackOf(dirsUpToWorkingDir(dirs), names)
+}
+
+func isRepoRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// dirsUpToWorkingDir trims dirs, which run from a directory's parent upwards,
+// to those at or below the working directory.
+func dirsUpToWorkingDir(dirs []string) []string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	for i, dir := range dirs {
+		if dir == wd {
+			return dirs[:i+1]
+		}
+	}
+	return nil
+}
+
+// stackOf reads the ignore files of dirs, which run from innermost to
+// outermost.
+func stackOf(dirs []string, names []string) ignoreStack {
+	var s ignoreStack
+	for i := len(dirs) - 1; i >= 0; i-- {
+		s = s.withDir(dirs[i], names)
+	}
+	return s
+}
+
+func parseIgnoreRules(content string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range strings.Split(content, "\n") {
+		if r, ok := parseIgnoreRule(line); ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreRule compiles one line of an ignore file with gitignore syntax.
+// It reports false for blank lines, comments and invalid patterns.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = trimIgnoreTrailingSpace(strings.TrimSuffix(line, "\r"))
+	if line == "" || line[0] == '#' {
+		return ignoreRule{}, false
+	}
+	var r ignoreRule
+	if line[0] == '!' {
+		r.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	// A slash anywhere but the end anchors the pattern to the directory of
+	// the ignore file; otherwise it matches at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	r.re = re
+	return r, true
+}
+
+// trimIgnoreTrailingSpace removes trailing spaces that are not escaped with
+// a backslash.
+func trimIgnoreTrailingSpace(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// globToRegexp translates a gitignore glob to a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case glob[i:] == "**" && i > 0 && glob[i-1] == '/':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			class, n := globClass(glob[i:])
+			b.WriteString(class)
+			i += n - 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// globClass translates the bracket expression at the start of s and returns
+// it with the number of bytes consumed. An unterminated bracket is a literal.
+func globClass(s string) (string, int) {
+	end := strings.IndexByte(s[1:], ']')
+	if end == 0 {
+		// "[]...]" includes the closing bracket as its first member.
+		if next := strings.IndexByte(s[2:], ']'); next >= 0 {
+			end = next + 1
+		} else {
+			end = -1
+		}
+	}
+	if end < 0 {
+		return `\[`, 1
+	}
+	body := s[1 : end+1]
+	if body != "" && body[0] == '!' {
+		body = "^" + body[1:]
+	}
+	return "[" + body + "]", end + 2
+}
@@ -0,0 +1,150 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.md", "a.md", false, true},
+		{"*.md", "docs/deep/a.md", false, true},
+		{"*.md", "a.mdx", false, false},
+		{"build", "build", true, true},
+		{"build", "src/build", true, true},
+		{"build/", "build", false, false},
+		{"build/", "src/build", true, true},
+		{"/build", "build", true, true},
+		{"/build", "src/build", true, false},
+		{"docs/gen", "docs/gen", true, true},
+		{"docs/gen", "x/docs/gen", true, false},
+		{"docs/*.md", "docs/a.md", false, true},
+		{"docs/*.md", "docs/sub/a.md", false, false},
+		{"**/gen", "gen", true, true},
+		{"**/gen", "a/b/gen", true, true},
+		{"docs/**", "docs/a/b.md", false, true},
+		{"a/**/b.md", "a/b.md", false, true},
+		{"a/**/b.md", "a/x/y/b.md", false, true},
+		{"?.md", "a.md", false, true},
+		{"?.md", "ab.md", false, false},
+		{"[ab].md", "b.md", false, true},
+		{"[!ab].md", "b.md", false, false},
+		{"[!ab].md", "c.md", false, true},
+		{`\#notes.md`, "#notes.md", false, true},
+		{`\!important.md`, "!important.md", false, true},
+		{"trailing.md   ", "trailing.md", false, true},
+		{"a+b(1).md", "a+b(1).md", false, true},
+	}
+	for _, tt := range tests {
+		r, ok := parseIgnoreRule(tt.pattern)
+		if !ok {
+			t.Errorf("parseIgnoreRule(%q) rejected the pattern", tt.pattern)
+			continue
+		}
+		got := (!r.dirOnly || tt.isDir) && r.re.MatchString(tt.path)
+		if got != tt.want {
+			t.Errorf("pattern %q on %q (dir=%v): got %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestParseIgnoreRule_SkipsBlankAndComments(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "!", "/"} {
+		if _, ok := parseIgnoreRule(line); ok {
+			t.Errorf("expected %q to be skipped", line)
+		}
+	}
+	if r, ok := parseIgnoreRule("!keep.md"); !ok || !r.negate {
+		t.Errorf("expected a negated rule, got %+v", r)
+	}
+}
+
+func TestExpandPaths_IgnoreFiles(t *testing.T) {
+	base := t.TempDir()
+	mustWrite := func(relPath, content string) {
+		full := filepath.Join(base, relPath)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustWrite(".gitignore", "node_modules/\n/build\n*.gen.md\n!keep.gen.md\n")
+	mustWrite("README.md", "")
+	mustWrite("node_modules/pkg/README.md", "")
+	mustWrite("build/out.md", "")
+	mustWrite("docs/build/ok.md", "")
+	mustWrite("docs/api.gen.md", "")
+	mustWrite("docs/keep.gen.md", "")
+	mustWrite("docs/.gitignore", "drafts/\n!/api.gen.md\n")
+	mustWrite("docs/drafts/wip.md", "")
+	mustWrite("docs/.ignore", "scratch.md\n")
+	mustWrite("docs/scratch.md", "")
+	mustWrite(".gomarklintignore", "CHANGELOG.md\n")
+	mustWrite("CHANGELOG.md", "")
+
+	rel := func(paths []string) []string {
+		var out []string
+		for _, p := range paths {
+			r, _ := filepath.Rel(base, p)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return sorted(out)
+	}
+
+	got := rel(ExpandPaths([]string{base}, nil, true))
+	want := []string{"README.md", "docs/api.gen.md", "docs/build/ok.md", "docs/keep.gen.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("with gitignore: got %v, want %v", got, want)
+	}
+
+	got = rel(ExpandPaths([]string{base}, nil, false))
+	want = []string{
+		"README.md", "build/out.md", "docs/api.gen.md", "docs/build/ok.md", "docs/drafts/wip.md",
+		"docs/keep.gen.md", "docs/scratch.md", "node_modules/pkg/README.md",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without gitignore: got %v, want %v", got, want)
+	}
+
+	// Files named directly are linted even when ignored.
+	got = rel(ExpandPaths([]string{filepath.Join(base, "build", "out.md")}, nil, true))
+	if !reflect.DeepEqual(got, []string{"build/out.md"}) {
+		t.Errorf("explicit file: got %v", got)
+	}
+}
+
+func TestExpandPaths_AncestorIgnoreFiles(t *testing.T) {
+	base := t.TempDir()
+	for _, dir := range []string{".git", "docs/generated", "docs/guide"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{
+		".gitignore":                "docs/generated/\n",
+		"docs/generated/api.md":     "",
+		"docs/guide/intro.md":       "",
+		"docs/guide/intro.draft.md": "",
+		"docs/.gomarklintignore":    "*.draft.md\n",
+	} {
+		if err := os.WriteFile(filepath.Join(base, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := ExpandPaths([]string{filepath.Join(base, "docs")}, nil, true)
+	want := []string{filepath.Join(base, "docs", "guide", "intro.md")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
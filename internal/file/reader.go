@@ -28,3 +28,18 @@ func StripFrontmatter(content string) (string, int) {
 	}
 	return content, 0
 }
+
+// Frontmatter returns the text between the leading "---" lines of content,
+// or "" when content has no frontmatter.
+func Frontmatter(content string) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return ""
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], "\n")
+		}
+	}
+	return ""
+}
@@ -105,3 +105,23 @@ title: "Oops"`,
 		})
 	}
 }
+
+func TestFrontmatter(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "with frontmatter", input: "---\ntitle: \"Test\"\ndate: 2025-01-01\n---\n\n# Hello", want: "title: \"Test\"\ndate: 2025-01-01"},
+		{name: "empty frontmatter", input: "---\n---\n# Hello", want: ""},
+		{name: "no frontmatter", input: "# Hello", want: ""},
+		{name: "incomplete frontmatter", input: "---\ntitle: \"Oops\"", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Frontmatter(tt.input); got != tt.want {
+				t.Errorf("Frontmatter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
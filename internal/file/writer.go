@@ -0,0 +1,12 @@
+package file
+
+import "os"
+
+// WriteFile replaces the contents of an existing file, keeping its permissions.
+func WriteFile(path string, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), info.Mode().Perm())
+}
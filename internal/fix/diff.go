@@ -0,0 +1,205 @@
+package fix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change,
+// matching the default of diff -u and git diff.
+const diffContext = 3
+
+// diffOp is one line of an edit script. a and b are the 0-based positions in
+// the old and new line slices; for an insertion a is the old position the
+// line is inserted at, and for a deletion b is the new position likewise.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	a, b int
+}
+
+// UnifiedDiff returns a unified diff from before to after, labelled with path
+// relative to the working directory in the a/ and b/ form that git apply
+// expects. A path outside the working directory is written as an absolute
+// path with no prefix, as diff -u does. It returns "" when the two are equal.
+func UnifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+	a, b := splitLines(before), splitLines(after)
+	ops := diffLines(a, b)
+
+	oldLabel, newLabel := diffLabels(path)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldLabel, newLabel)
+	for _, h := range hunks(ops) {
+		writeHunk(&sb, a, b, ops[h[0]:h[1]])
+	}
+	return sb.String()
+}
+
+// diffLabels returns the old and new file labels for path.
+func diffLabels(path string) (string, string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(abs), filepath.ToSlash(abs)
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs), filepath.ToSlash(abs)
+	}
+	name := filepath.ToSlash(rel)
+	return "a/" + name, "b/" + name
+}
+
+// splitLines splits s after each "\n", so every element keeps its line
+// ending and a missing final newline shows up as a difference.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script with Myers' algorithm. Each step
+// stores only the diagonals it can reach, so memory grows with the square of
+// the number of changes rather than with the file size.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	limit := n + m
+	off := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+search:
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		snap := trace[d] // diagonals -d..d as they stood before step d
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && snap[k-1+d] < snap[k+1+d]) {
+			prevK = k + 1
+		}
+		prevX := snap[prevK+d]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{' ', x, y})
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', x, y - 1})
+		} else {
+			ops = append(ops, diffOp{'-', x - 1, y})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, diffOp{' ', x, y})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// hunks groups ops into [start, end) ranges, each holding a run of changes
+// with up to diffContext unchanged lines on either side. Changes separated by
+// no more than twice the context share a hunk.
+func hunks(ops []diffOp) [][2]int {
+	var out [][2]int
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+		start := max(0, i-diffContext)
+		j := i
+		for {
+			for j < len(ops) && ops[j].kind != ' ' {
+				j++
+			}
+			k := j
+			for k < len(ops) && k < j+2*diffContext && ops[k].kind == ' ' {
+				k++
+			}
+			if k < len(ops) && ops[k].kind != ' ' {
+				j = k
+				continue
+			}
+			break
+		}
+		end := min(len(ops), j+diffContext)
+		out = append(out, [2]int{start, end})
+		i = end - 1
+	}
+	return out
+}
+
+func writeHunk(sb *strings.Builder, a, b []string, ops []diffOp) {
+	aLen, bLen := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(ops[0].a, aLen), hunkRange(ops[0].b, bLen))
+	for _, op := range ops {
+		var line string
+		if op.kind == '+' {
+			line = b[op.b]
+		} else {
+			line = a[op.a]
+		}
+		sb.WriteByte(op.kind)
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats a hunk's start line and length. An empty range names the
+// line before it, as diff -u does.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
@@ -0,0 +1,152 @@
+package fix
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "equal",
+			path:   "a.md",
+			before: "same\n",
+			after:  "same\n",
+			want:   "",
+		},
+		{
+			name:   "insert line",
+			path:   "./docs/a.md",
+			before: "text\n# H\n",
+			after:  "text\n\n# H\n",
+			want: "--- a/docs/a.md\n+++ b/docs/a.md\n" +
+				"@@ -1,2 +1,3 @@\n text\n+\n # H\n",
+		},
+		{
+			name:   "missing final newline",
+			path:   "a.md",
+			before: "a\nb",
+			after:  "a\nb\n",
+			want: "--- a/a.md\n+++ b/a.md\n" +
+				"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:   "from empty file",
+			path:   "a.md",
+			before: "",
+			after:  "\n",
+			want:   "--- a/a.md\n+++ b/a.md\n@@ -0,0 +1,1 @@\n+\n",
+		},
+		{
+			name:   "keeps CRLF",
+			path:   "a.md",
+			before: "x\r\n\r\n\r\ny\r\n",
+			after:  "x\r\n\r\ny\r\n",
+			want: "--- a/a.md\n+++ b/a.md\n" +
+				"@@ -1,4 +1,3 @@\n x\r\n \r\n-\r\n y\r\n",
+		},
+		{
+			name:   "distant changes get separate hunks",
+			path:   "a.md",
+			before: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			after:  "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- a/a.md\n+++ b/a.md\n" +
+				"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name:   "nearby changes share a hunk",
+			path:   "a.md",
+			before: "a\n1\n2\n3\n4\nb\n",
+			after:  "A\n1\n2\n3\n4\nB\n",
+			want: "--- a/a.md\n+++ b/a.md\n" +
+				"@@ -1,6 +1,6 @@\n-a\n+A\n 1\n 2\n 3\n 4\n-b\n+B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff(tt.path, tt.before, tt.after); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// applyDiff replays the hunks of a diff produced by UnifiedDiff onto before.
+func applyDiff(t *testing.T, before, diff string) string {
+	t.Helper()
+	src := splitLines(before)
+	var out []string
+	pos := 0
+	var last byte
+	lines := strings.SplitAfter(diff, "\n")
+	for i := 2; i < len(lines) && lines[i] != ""; i++ {
+		l := lines[i]
+		switch {
+		case strings.HasPrefix(l, "@@"):
+			var aStart, aLen int
+			if _, err := fmt.Sscanf(l, "@@ -%d,%d", &aStart, &aLen); err != nil {
+				t.Fatalf("bad hunk header %q: %v", l, err)
+			}
+			if aLen > 0 {
+				aStart--
+			}
+			out = append(out, src[pos:aStart]...)
+			pos = aStart
+		case strings.HasPrefix(l, `\`):
+			if last != '-' {
+				out[len(out)-1] = strings.TrimSuffix(out[len(out)-1], "\n")
+			}
+		case l[0] == ' ':
+			out = append(out, l[1:])
+			pos++
+		case l[0] == '-':
+			pos++
+		case l[0] == '+':
+			out = append(out, l[1:])
+		}
+		last = l[0]
+	}
+	out = append(out, src[pos:]...)
+	return strings.Join(out, "")
+}
+
+func TestUnifiedDiff_RoundTrip(t *testing.T) {
+	pairs := [][2]string{
+		{"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n", "a\nb\nX\nd\ne\nf\ng\nh\ni\nk\nY"},
+		{"one", "zero\none\ntwo\n"},
+		{"x\ny\nx\ny\nx\n", "y\nx\ny\n"},
+		{"a\n\n\n\nb\n\n\n\nc", "a\n\nb\n\nc\n"},
+	}
+	for _, p := range pairs {
+		diff := UnifiedDiff("f.md", p[0], p[1])
+		if got := applyDiff(t, p[0], diff); got != p[1] {
+			t.Errorf("applying diff to %q gave %q, want %q\n%s", p[0], got, p[1], diff)
+		}
+	}
+}
+
+func TestUnifiedDiff_AbsolutePath(t *testing.T) {
+	wd := t.TempDir()
+	t.Chdir(wd)
+
+	inside := filepath.Join(wd, "docs", "a.md")
+	if got := UnifiedDiff(inside, "a\n", "b\n"); !strings.HasPrefix(got, "--- a/docs/a.md\n+++ b/docs/a.md\n") {
+		t.Errorf("expected labels relative to the working directory, got:\n%s", got)
+	}
+
+	outside := filepath.Join(t.TempDir(), "a.md")
+	label := filepath.ToSlash(outside)
+	if got := UnifiedDiff(outside, "a\n", "b\n"); !strings.HasPrefix(got, "--- "+label+"\n+++ "+label+"\n") {
+		t.Errorf("expected unprefixed absolute labels, got:\n%s", got)
+	}
+}
@@ -0,0 +1,143 @@
+// Package fix applies the edits attached to lint errors to file content.
+package fix
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// byteEdit is a rule.Edit resolved to byte offsets within the content.
+type byteEdit struct {
+	start, end int
+	text       string
+}
+
+// Apply applies the fixes carried by errs to content and returns the result
+// with the number of fixes applied. A fix whose edits overlap an edit that was
+// already accepted is skipped as a whole; callers re-lint and apply again to
+// pick it up once the earlier fix has landed.
+func Apply(content string, errs []rule.LintError) (string, int) {
+	lineStarts := lineOffsets(content)
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+
+	// Each entry holds the edits of one fix, resolved and sorted by start.
+	var fixes [][]byteEdit
+	for _, e := range errs {
+		if e.Fix == nil || len(e.Fix.Edits) == 0 {
+			continue
+		}
+		if edits, ok := resolve(content, lineStarts, e.Fix.Edits, eol); ok {
+			fixes = append(fixes, edits)
+		}
+	}
+	sort.SliceStable(fixes, func(a, b int) bool {
+		return fixes[a][0].start < fixes[b][0].start
+	})
+
+	var accepted []byteEdit
+	applied := 0
+	for _, f := range fixes {
+		if conflicts(accepted, f) {
+			continue
+		}
+		accepted = append(accepted, f...)
+		applied++
+	}
+	if applied == 0 {
+		return content, 0
+	}
+
+	// Apply from the end so earlier offsets stay valid.
+	sort.Slice(accepted, func(a, b int) bool { return accepted[a].start > accepted[b].start })
+	out := content
+	for _, ed := range accepted {
+		out = out[:ed.start] + ed.text + out[ed.end:]
+	}
+	return out, applied
+}
+
+// lineOffsets returns the byte offset at which each line of content starts.
+func lineOffsets(content string) []int {
+	starts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// resolve converts edits to byte offsets, sorted by start. It fails if any
+// position lies outside content, so a stale fix is never half-applied.
+func resolve(content string, lineStarts []int, edits []rule.Edit, eol string) ([]byteEdit, bool) {
+	out := make([]byteEdit, 0, len(edits))
+	for _, ed := range edits {
+		start, ok := offset(content, lineStarts, ed.Line, ed.Column)
+		if !ok {
+			return nil, false
+		}
+		end, ok := offset(content, lineStarts, ed.EndLine, ed.EndColumn)
+		if !ok || end < start {
+			return nil, false
+		}
+		text := ed.NewText
+		if eol != "\n" {
+			text = strings.ReplaceAll(text, "\n", eol)
+		}
+		out = append(out, byteEdit{start: start, end: end, text: text})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].start < out[b].start })
+	for i := 1; i < len(out); i++ {
+		if overlaps(out[i-1], out[i]) {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// offset converts a 1-based line and character column to a byte offset. The
+// column may point one past the last character of the line (including a
+// trailing "\r"), which addresses the position just before the line break.
+func offset(content string, lineStarts []int, line, col int) (int, bool) {
+	if line < 1 || line > len(lineStarts) || col < 1 {
+		return 0, false
+	}
+	start := lineStarts[line-1]
+	end := len(content)
+	if line < len(lineStarts) {
+		end = lineStarts[line] - 1
+	}
+	pos := start
+	for n := 1; n < col; n++ {
+		if pos >= end {
+			return 0, false
+		}
+		_, size := utf8.DecodeRuneInString(content[pos:end])
+		pos += size
+	}
+	return pos, true
+}
+
+// overlaps reports whether two edits touch the same bytes, insert at the
+// same position, where their relative order would be ambiguous, or one
+// inserts inside the bytes the other replaces.
+func overlaps(a, b byteEdit) bool {
+	return a.start == b.start || (a.start < b.end && b.start < a.end)
+}
+
+func conflicts(accepted, edits []byteEdit) bool {
+	for _, a := range accepted {
+		for _, b := range edits {
+			if overlaps(a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package fix
+
+import (
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func withEdits(edits ...rule.Edit) rule.LintError {
+	return rule.LintError{Fix: &rule.Fix{Edits: edits}}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errs    []rule.LintError
+		want    string
+		applied int
+	}{
+		{
+			name:    "no fixes",
+			content: "a\nb\n",
+			errs:    []rule.LintError{{Line: 1}},
+			want:    "a\nb\n",
+			applied: 0,
+		},
+		{
+			name:    "insert blank line",
+			content: "a\n# H\n",
+			errs:    []rule.LintError{withEdits(rule.Edit{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "\n"})},
+			want:    "a\n\n# H\n",
+			applied: 1,
+		},
+		{
+			name:    "delete line with preceding break",
+			content: "a\n\n\nb",
+			errs:    []rule.LintError{withEdits(rule.Edit{Line: 2, Column: 1, EndLine: 3, EndColumn: 1})},
+			want:    "a\n\nb",
+			applied: 1,
+		},
+		{
+			name:    "replace multibyte column",
+			content: "日本*語*\n",
+			errs: []rule.LintError{withEdits(
+				rule.Edit{Line: 1, Column: 3, EndLine: 1, EndColumn: 4, NewText: "_"},
+				rule.Edit{Line: 1, Column: 5, EndLine: 1, EndColumn: 6, NewText: "_"},
+			)},
+			want:    "日本_語_\n",
+			applied: 1,
+		},
+		{
+			name:    "append at end of file",
+			content: "a",
+			errs:    []rule.LintError{withEdits(rule.Edit{Line: 1, Column: 2, EndLine: 1, EndColumn: 2, NewText: "\n"})},
+			want:    "a\n",
+			applied: 1,
+		},
+		{
+			name:    "keeps CRLF line endings",
+			content: "a\r\n# H\r\n",
+			errs:    []rule.LintError{withEdits(rule.Edit{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "\n"})},
+			want:    "a\r\n\r\n# H\r\n",
+			applied: 1,
+		},
+		{
+			name:    "identical insertions apply once",
+			content: "# H\n- a\n",
+			errs: []rule.LintError{
+				withEdits(rule.Edit{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "\n"}),
+				withEdits(rule.Edit{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "\n"}),
+			},
+			want:    "# H\n\n- a\n",
+			applied: 1,
+		},
+		{
+			name:    "overlapping fix is skipped as a whole",
+			content: "abcdef\n",
+			errs: []rule.LintError{
+				withEdits(rule.Edit{Line: 1, Column: 2, EndLine: 1, EndColumn: 4, NewText: "X"}),
+				withEdits(
+					rule.Edit{Line: 1, Column: 3, EndLine: 1, EndColumn: 5, NewText: "Y"},
+					rule.Edit{Line: 1, Column: 6, EndLine: 1, EndColumn: 7, NewText: "Z"},
+				),
+			},
+			want:    "aXdef\n",
+			applied: 1,
+		},
+		{
+			name:    "insertion inside a replaced range is skipped",
+			content: "abc\ndef\n",
+			errs: []rule.LintError{
+				withEdits(rule.Edit{Line: 1, Column: 1, EndLine: 2, EndColumn: 4, NewText: "X"}),
+				withEdits(rule.Edit{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "| "}),
+			},
+			want:    "X\n",
+			applied: 1,
+		},
+		{
+			name:    "adjacent edits both apply",
+			content: "ab\n",
+			errs: []rule.LintError{
+				withEdits(rule.Edit{Line: 1, Column: 1, EndLine: 1, EndColumn: 2, NewText: "A"}),
+				withEdits(rule.Edit{Line: 1, Column: 2, EndLine: 1, EndColumn: 3, NewText: "B"}),
+			},
+			want:    "AB\n",
+			applied: 2,
+		},
+		{
+			name:    "out of range position is ignored",
+			content: "ab\n",
+			errs: []rule.LintError{
+				withEdits(rule.Edit{Line: 1, Column: 9, EndLine: 1, EndColumn: 9, NewText: "x"}),
+				withEdits(rule.Edit{Line: 5, Column: 1, EndLine: 5, EndColumn: 1, NewText: "x"}),
+			},
+			want:    "ab\n",
+			applied: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, applied := Apply(tt.content, tt.errs)
+			if got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+			if applied != tt.applied {
+				t.Errorf("applied = %d, want %d", applied, tt.applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package gitdiff finds the files and lines that changed relative to a git
+// ref, using the git binary on PATH.
+package gitdiff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LineRange is a run of changed lines, 1-based and inclusive.
+type LineRange struct {
+	Start, End int
+}
+
+// Changes maps each changed file, as a path relative to the working
+// directory, to its changed lines in the working tree.
+type Changes map[string][]LineRange
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// Load returns the changes in the working tree relative to base. Untracked
+// files that are not ignored count as changed throughout.
+func Load(base string) (Changes, error) {
+	diff, err := git("diff", "--no-color", "--no-ext-diff", "--unified=0", "--relative",
+		"--diff-filter=d", "--src-prefix=a/", "--dst-prefix=b/", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	changes := Parse(diff)
+
+	untracked, err := git("ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(untracked, "\x00") {
+		if path != "" {
+			changes[filepath.FromSlash(path)] = []LineRange{{Start: 1, End: math.MaxInt}}
+		}
+	}
+	return changes, nil
+}
+
+func git(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); line != "" {
+				return "", fmt.Errorf("git %s failed: %s", args[0], line)
+			}
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// Parse reads the changed lines of each file from a zero-context unified diff
+// with a/ and b/ prefixes. Files whose hunks only delete lines are listed
+// with no ranges.
+func Parse(diff string) Changes {
+	changes := Changes{}
+	var current string
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++ ") {
+			current = newPath(strings.TrimPrefix(line, "+++ "))
+			if current != "" {
+				if _, ok := changes[current]; !ok {
+					changes[current] = nil
+				}
+			}
+			continue
+		}
+		m := hunkHeader.FindStringSubmatch(line)
+		if m == nil || current == "" {
+			continue
+		}
+		start, _ := strconv.Atoi(m[1])
+		count := 1
+		if m[2] != "" {
+			count, _ = strconv.Atoi(m[2])
+		}
+		if count > 0 {
+			changes[current] = append(changes[current], LineRange{Start: start, End: start + count - 1})
+		}
+	}
+	return changes
+}
+
+// newPath returns the file named on a "+++" line, or "" for /dev/null.
+func newPath(name string) string {
+	name = strings.TrimSuffix(name, "\t")
+	if strings.HasPrefix(name, `"`) {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+	}
+	if !strings.HasPrefix(name, "b/") {
+		return ""
+	}
+	return filepath.FromSlash(strings.TrimPrefix(name, "b/"))
+}
+
+// Files returns the changed files, sorted.
+func (c Changes) Files() []string {
+	files := make([]string, 0, len(c))
+	for path := range c {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Touches reports whether any line from start to end of path changed.
+func (c Changes) Touches(path string, start, end int) bool {
+	if end < start {
+		end = start
+	}
+	for _, r := range c[filepath.Clean(path)] {
+		if start <= r.End && r.Start <= end {
+			return true
+		}
+	}
+	return false
+}
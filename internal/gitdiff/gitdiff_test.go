@@ -0,0 +1,125 @@
+package gitdiff
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	diff := `diff --git a/docs/a.md b/docs/a.md
+index 1111111..2222222 100644
+--- a/docs/a.md
++++ b/docs/a.md
+@@ -3 +3 @@ Title
+-old
++new
+@@ -10,0 +11,2 @@ more
++added
++added
+@@ -20,3 +21,0 @@
+-gone
+-gone
+-gone
+diff --git a/only-deleted.md b/only-deleted.md
+--- a/only-deleted.md
++++ b/only-deleted.md
+@@ -1 +0,0 @@
+-line
+diff --git "a/caf\303\251.md" "b/caf\303\251.md"
+new file mode 100644
+--- /dev/null
++++ "b/caf\303\251.md"
+@@ -0,0 +1,3 @@
++a
++b
++c
+`
+	want := Changes{
+		filepath.FromSlash("docs/a.md"): {{Start: 3, End: 3}, {Start: 11, End: 12}},
+		"only-deleted.md":               nil,
+		"café.md":                       {{Start: 1, End: 3}},
+	}
+	if got := Parse(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestChanges_Touches(t *testing.T) {
+	c := Changes{"a.md": {{Start: 3, End: 5}}}
+	tests := []struct {
+		path       string
+		start, end int
+		want       bool
+	}{
+		{"a.md", 3, 0, true},
+		{"a.md", 5, 5, true},
+		{"a.md", 2, 0, false},
+		{"a.md", 6, 0, false},
+		{"a.md", 1, 3, true},
+		{"./a.md", 4, 0, true},
+		{"b.md", 4, 0, false},
+	}
+	for _, tt := range tests {
+		if got := c.Touches(tt.path, tt.start, tt.end); got != tt.want {
+			t.Errorf("Touches(%q, %d, %d) = %v, want %v", tt.path, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+	write("a.md", "one\ntwo\nthree\n")
+	write("gone.md", "x\n")
+	write(".gitignore", "ignored.md\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	write("a.md", "one\nTWO\nthree\nfour\n")
+	if err := os.Remove("gone.md"); err != nil {
+		t.Fatal(err)
+	}
+	write("new.md", "new\n")
+	write("ignored.md", "ignored\n")
+
+	changes, err := Load("HEAD")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := Changes{
+		"a.md":   {{Start: 2, End: 2}, {Start: 4, End: 4}},
+		"new.md": {{Start: 1, End: math.MaxInt}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+
+	if _, err := Load("no-such-ref"); err == nil || !strings.Contains(err.Error(), "git diff failed") {
+		t.Errorf("expected a git error, got %v", err)
+	}
+}
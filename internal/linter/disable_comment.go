@@ -114,6 +114,19 @@ func parseDisableComments(lines []string, offset int) disabledSet {
 	return set
 }
 
+// nextLineDirectives returns the absolute line numbers of disable-next-line
+// comments. Fixes must not add or remove lines right after one, or the
+// directive would end up silencing a different line.
+func nextLineDirectives(lines []string, offset int) map[int]bool {
+	set := map[int]bool{}
+	for i, line := range lines {
+		if directive, _ := parseDirectiveLine(line); directive == "disable-next-line" {
+			set[i+1+offset] = true
+		}
+	}
+	return set
+}
+
 func removeAll(s []string, remove []string) []string {
 	result := s[:0]
 	for _, v := range s {
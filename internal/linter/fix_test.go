@@ -0,0 +1,196 @@
+package linter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+)
+
+func TestFixContent_PerRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		options map[string]interface{}
+		input   string
+		want    string
+	}{
+		{"final-blank-line", nil, "text", "text\n"},
+		{"no-multiple-blank-lines", nil, "a\n\n\n\nb\n", "a\n\nb\n"},
+		{"no-multiple-blank-lines", nil, "a\n\n\n", "a\n"},
+		{"blanks-around-headings", nil, "text\n## H\ntext\n", "text\n\n## H\n\ntext\n"},
+		{"blanks-around-lists", nil, "text\n- a\n- b\ntext\n", "text\n\n- a\n- b\n\ntext\n"},
+		{"blanks-around-fences", nil, "text\n```go\nx\n```\ntext\n", "text\n\n```go\nx\n```\n\ntext\n"},
+		{"no-hard-tabs", nil, "a\tb\n\tc\n", "a   b\n    c\n"},
+		{"no-hard-tabs", nil, "ab\t\tc\n", "ab      c\n"},
+		{"consistent-list-marker", nil, "- a\n* b\n+ c\n", "- a\n- b\n- c\n"},
+		{"consistent-list-marker", map[string]interface{}{"style": "asterisk"}, "- a\n  - b\n", "* a\n  * b\n"},
+		{"consistent-code-fence", nil, "```\na\n```\n\n~~~~ go\nb\n~~~~\n", "```\na\n```\n\n```` go\nb\n````\n"},
+		{"consistent-code-fence", map[string]interface{}{"style": "tilde"}, "```go\nx\n", "~~~go\nx\n"},
+		{"consistent-emphasis-style", nil, "*a* and _b_ and __c__ and **d**\n", "*a* and *b* and __c__ and __d__\n"},
+		{"consistent-emphasis-style", map[string]interface{}{"style": "underscore"}, "*a* and **b**\n", "_a_ and __b__\n"},
+		{"no-setext-headings", nil, "Title\n=====\n\nSub\n---\n", "# Title\n\n## Sub\n"},
+		{"no-trailing-punctuation", nil, "## Hello!\n\nTitle.\n===\n", "## Hello\n\nTitle\n===\n"},
+		{"no-trailing-spaces", nil, "a \nb  \nc  \n\n# H  \nd\t\n  \n", "a\nb  \nc\n\n# H\nd\n\n"},
+		{"no-trailing-spaces", map[string]interface{}{"brSpaces": 0}, "a  \nb\t\n  \n", "a\\\nb\n\n"},
+		{"no-trailing-spaces", map[string]interface{}{"brSpaces": 3}, "a  \nb\n", "a\\\nb\n"},
+		{"no-trailing-spaces", map[string]interface{}{"brSpaces": 2}, "a    \nb  \nc  \n", "a  \nb  \nc\n"},
+		{"table-formatting", nil, "| a | b |\n| - | - |\n1 | 2\n| 3 | 4\n", "| a | b |\n| - | - |\n| 1 | 2 |\n| 3 | 4 |\n"},
+		{"table-formatting", map[string]interface{}{"pipeStyle": "none"}, "| a | b |\n|---|---|\n", "a | b\n---|---\n"},
+		{"table-formatting", map[string]interface{}{"alignColumns": true}, "a | Long header\n:-|--:\nwide cell | 1\n", "a         | Long header\n:-------- | ----------:\nwide cell |           1\n"},
+		{"table-formatting", map[string]interface{}{"alignColumns": true}, "|名前|x|\n|:-:|-|\n|a|b|\n", "| 名前 | x   |\n| :--: | --- |\n|  a   | b   |\n"},
+		{"table-formatting", map[string]interface{}{"alignColumns": true}, "| a | b |\n|-|-|\n| 1 | 2 |\n| 3 | 4\n", "| a   | b   |\n| --- | --- |\n| 1   | 2   |\n| 3   | 4   |\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			cfg := allOff()
+			rc := on()
+			if tt.options != nil {
+				rc.Options = tt.options
+			}
+			cfg.Rules[tt.rule] = rc
+			l := mustNew(t, cfg)
+
+			got, n := l.FixContent("test.md", tt.input)
+			if got != tt.want {
+				t.Errorf("FixContent() = %q, want %q", got, tt.want)
+			}
+			if n == 0 {
+				t.Error("expected at least one fix to be applied")
+			}
+			if errs, _, _ := l.LintContent("test.md", got); len(errs) != 0 {
+				t.Errorf("expected no remaining violations, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestFixContent_Unfixable(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		style string
+		input string
+	}{
+		{"emphasis inside a word", "consistent-emphasis-style", "underscore", "foo*bar*baz\n"},
+		{"backtick in tilde info string", "consistent-code-fence", "backtick", "~~~ `x`\ncode\n~~~\n"},
+		{"backtick fence in tilde body", "consistent-code-fence", "backtick", "~~~\n```\n~~~\n"},
+		{"multi-line setext heading", "no-setext-headings", "", "first\nsecond\n===\n"},
+		{"outer pipe next to an empty cell", "table-formatting", "", "a | b\n--|--\n| | c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := allOff()
+			rc := on()
+			if tt.style != "" {
+				rc.Options = map[string]interface{}{"style": tt.style}
+			}
+			cfg.Rules[tt.rule] = rc
+			l := mustNew(t, cfg)
+
+			got, n := l.FixContent("test.md", tt.input)
+			if got != tt.input || n != 0 {
+				t.Errorf("expected content unchanged, got %q (%d fixes)", got, n)
+			}
+		})
+	}
+}
+
+func TestFixContent_TableAlignKeepsEmptyEdgeCells(t *testing.T) {
+	tests := []struct {
+		pipeStyle string
+		input     string
+		want      string
+	}{
+		{"none", "a | b\n--|--\n| | c |\n", "a   | b\n--- | ---\n|     | c\n"},
+		{"leading", "| a | |\n| - | - |\n", "| a   |     |\n| --- | ---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pipeStyle, func(t *testing.T) {
+			cfg := allOff()
+			rc := on()
+			rc.Options = map[string]interface{}{"pipeStyle": tt.pipeStyle, "alignColumns": true}
+			cfg.Rules["table-formatting"] = rc
+			l := mustNew(t, cfg)
+
+			got, _ := l.FixContent("test.md", tt.input)
+			if got != tt.want {
+				t.Errorf("FixContent() = %q, want %q", got, tt.want)
+			}
+			// Only the pipe next to the empty cell is left to report.
+			errs, _, _ := l.LintContent("test.md", got)
+			if len(errs) != 1 || !strings.Contains(errs[0].Message, "table-formatting: expected") {
+				t.Errorf("expected one pipe style violation, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestFixContent_RespectsFrontmatter(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["blanks-around-headings"] = on()
+	cfg.Rules["no-trailing-punctuation"] = on()
+	l := mustNew(t, cfg)
+
+	input := "---\ntitle: x\n---\n\ntext\n## Hi!\n"
+	got, _ := l.FixContent("test.md", input)
+	want := "---\ntitle: x\n---\n\ntext\n\n## Hi\n"
+	if got != want {
+		t.Errorf("FixContent() = %q, want %q", got, want)
+	}
+}
+
+func TestFixContent_RespectsDisableComments(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["blanks-around-headings"] = on()
+	cfg.Rules["no-trailing-punctuation"] = on()
+	cfg.Rules["no-hard-tabs"] = on()
+	l := mustNew(t, cfg)
+
+	input := "<!-- gomarklint-disable no-hard-tabs -->\n\ta\n<!-- gomarklint-enable no-hard-tabs -->\n" +
+		"<!-- gomarklint-disable-next-line no-trailing-punctuation -->\n## Keep!\n"
+	got, _ := l.FixContent("test.md", input)
+	if got != input {
+		t.Errorf("expected disabled violations to stay unfixed, got %q", got)
+	}
+}
+
+func TestFixContent_DirectiveAllowsEndOfLineBreak(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["final-blank-line"] = on()
+	cfg.Rules["no-hard-tabs"] = on()
+	l := mustNew(t, cfg)
+
+	// The final newline goes after the target line, so it stays the line
+	// below the directive.
+	input := "## T\n\n<!-- gomarklint-disable-next-line no-hard-tabs -->\nx\ty"
+	got, n := l.FixContent("test.md", input)
+	if want := input + "\n"; got != want || n != 1 {
+		t.Errorf("FixContent() = %q (%d fixes), want %q (1 fix)", got, n, want)
+	}
+}
+
+func TestFixContent_SkipsWarningsWhenSeverityError(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["final-blank-line"] = &config.RuleConfig{Enabled: true, Severity: config.SeverityWarning, Options: map[string]interface{}{}}
+	cfg.MinSeverity = config.SeverityError
+	l := mustNew(t, cfg)
+
+	if got, n := l.FixContent("test.md", "text"); got != "text" || n != 0 {
+		t.Errorf("expected warning left unfixed, got %q (%d fixes)", got, n)
+	}
+}
+
+func TestFixContent_ResolvesOverlapsAcrossPasses(t *testing.T) {
+	cfg := config.Default()
+	l := mustNew(t, cfg)
+
+	input := "Intro\n## Section\n- a\n* b\n\n\n\nText\tend"
+	got, _ := l.FixContent("test.md", input)
+	want := "Intro\n\n## Section\n\n- a\n- b\n\nText    end\n"
+	if got != want {
+		t.Errorf("FixContent() = %q, want %q", got, want)
+	}
+}
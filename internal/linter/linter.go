@@ -1,23 +1,48 @@
 package linter
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/shinagawa-web/gomarklint/v3/internal/cache"
 	"github.com/shinagawa-web/gomarklint/v3/internal/config"
 	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/fix"
+	"github.com/shinagawa-web/gomarklint/v3/internal/plugin"
 	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
 	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
 )
 
 type Linter struct {
 	config           config.Config
+	rules            []configuredRule
+	externalLink     *configuredRule
 	compiledPatterns []*regexp.Regexp
 	urlCache         *sync.Map
+	links            *rule.LinkChecker
+
+	// cache, when set, holds the findings of cachedRules from earlier runs.
+	cache         *cache.Cache
+	configKey     string
+	cachedRules   []configuredRule
+	uncachedRules []configuredRule
+
+	// linkCache, when set, holds external-link results from earlier runs.
+	linkCache *cache.Cache
+}
+
+// configuredRule is an enabled rule with its severity and resolved options.
+type configuredRule struct {
+	rule     rule.Rule
+	severity string
+	options  rule.Options
 }
 
 type Result struct {
@@ -30,388 +55,527 @@ type Result struct {
 	FailedFiles       map[string]error
 }
 
+// New validates the options of every registered rule against its declared
+// options, including rules the config disables, and the config's plugins and
+// custom rules, and returns a linter that runs the enabled ones.
 func New(cfg config.Config) (*Linter, error) {
-	if err := validateStyleOption(cfg, "consistent-code-fence", "style", []string{"consistent", "backtick", "tilde"}); err != nil {
-		return nil, err
-	}
-	if err := validateStyleOption(cfg, "consistent-emphasis-style", "style", []string{"consistent", "asterisk", "underscore"}); err != nil {
-		return nil, err
+	l := &Linter{config: cfg, compiledPatterns: []*regexp.Regexp{}, urlCache: &sync.Map{}}
+	for _, r := range rule.Registered() {
+		opts, err := rule.ResolveOptions(r, cfg.RuleOptions(r.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !cfg.IsEnabled(r.Name()) {
+			continue
+		}
+		cr := configuredRule{rule: r, severity: ruleSeverity(cfg, r), options: opts}
+		if r.Name() == rule.ExternalLinkName {
+			l.externalLink = &cr
+			l.links = rule.NewLinkChecker(opts, l.urlCache)
+			patterns, errs := rule.CompileSkipPatterns(opts.Strings("skipPatterns"))
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "Invalid skip-link-pattern: %v\n", err)
+			}
+			l.compiledPatterns = append(l.compiledPatterns, patterns...)
+			continue
+		}
+		l.rules = append(l.rules, cr)
 	}
-	if err := validateStyleOption(cfg, "consistent-list-marker", "style", []string{"consistent", "dash", "asterisk", "plus"}); err != nil {
-		return nil, err
+	taken := map[string]bool{}
+	for _, name := range RuleNames() {
+		taken[name] = true
 	}
-
-	if err := validateExternalLinkIntOption(cfg, "maxConcurrency", 1, rule.MaxConcurrencyLimit); err != nil {
+	plugins, err := configurePlugins(cfg, taken)
+	if err != nil {
 		return nil, err
 	}
-	if err := validateExternalLinkIntOption(cfg, "maxRetries", 0, rule.MaxRetriesLimit); err != nil {
+	custom, err := configureCustomRules(cfg, taken)
+	if err != nil {
 		return nil, err
 	}
-	if err := validateExternalLinkIntOption(cfg, "perHostConcurrency", 1, rule.MaxPerHostConcurrencyLimit); err != nil {
-		return nil, err
+	l.rules = append(l.rules, plugins...)
+	l.rules = append(l.rules, custom...)
+	return l, nil
+}
+
+// configurePlugins validates the plugins the config declares and returns
+// the enabled ones. A plugin's options are passed to it unchecked.
+func configurePlugins(cfg config.Config, taken map[string]bool) ([]configuredRule, error) {
+	seen := map[string]bool{}
+	var rules []configuredRule
+	for i, pc := range cfg.Plugins {
+		switch {
+		case pc.Name == "":
+			return nil, fmt.Errorf("gomarklint: plugins[%d] has no name", i)
+		case len(pc.Command) == 0 || pc.Command[0] == "":
+			return nil, fmt.Errorf("gomarklint: plugin %q has no command", pc.Name)
+		case seen[pc.Name]:
+			return nil, fmt.Errorf("gomarklint: plugin %q is declared twice", pc.Name)
+		case taken[pc.Name]:
+			return nil, fmt.Errorf("gomarklint: plugin %q has the name of a built-in rule", pc.Name)
+		case pc.TimeoutSeconds < 0:
+			return nil, fmt.Errorf("gomarklint: plugin %q timeoutSeconds must be at least 1, got %d", pc.Name, pc.TimeoutSeconds)
+		}
+		seen[pc.Name] = true
+		taken[pc.Name] = true
+		if !cfg.IsDeclaredRuleEnabled(pc.Name) {
+			continue
+		}
+		r := plugin.New(pc)
+		rules = append(rules, configuredRule{rule: r, severity: ruleSeverity(cfg, r), options: cfg.RuleOptions(pc.Name)})
 	}
-	if err := validatePerHostIntervalMs(cfg); err != nil {
-		return nil, err
+	return rules, nil
+}
+
+// configureCustomRules validates the config's custom-rules and returns the
+// enabled ones, in name order. Their entries in "rules" accept no options.
+func configureCustomRules(cfg config.Config, taken map[string]bool) ([]configuredRule, error) {
+	names := make([]string, 0, len(cfg.CustomRules))
+	for name := range cfg.CustomRules {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	compiledPatterns := []*regexp.Regexp{}
-	if cfg.IsEnabled("external-link") {
-		opts := cfg.RuleOptions("external-link")
-		if patterns, ok := opts["skipPatterns"]; ok {
-			if arr, ok := patterns.([]interface{}); ok {
-				for _, p := range arr {
-					if s, ok := p.(string); ok {
-						re, err := regexp.Compile(s)
-						if err != nil {
-							fmt.Fprintf(os.Stderr, "Invalid skip-link-pattern: %s (error: %v)\n", s, err)
-							continue
-						}
-						compiledPatterns = append(compiledPatterns, re)
-					}
-				}
-			}
+	var rules []configuredRule
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("gomarklint: custom-rules has an entry with no name")
 		}
+		if taken[name] {
+			return nil, fmt.Errorf("gomarklint: custom rule %q has the name of a built-in rule or plugin", name)
+		}
+		r, err := rule.NewPatternRule(name, cfg.CustomRules[name])
+		if err != nil {
+			return nil, fmt.Errorf("gomarklint: custom rule %q: %w", name, err)
+		}
+		opts, err := rule.ResolveOptions(r, cfg.RuleOptions(name))
+		if err != nil {
+			return nil, err
+		}
+		if !cfg.IsDeclaredRuleEnabled(name) {
+			continue
+		}
+		rules = append(rules, configuredRule{rule: r, severity: ruleSeverity(cfg, r), options: opts})
 	}
-
-	return &Linter{
-		config:           cfg,
-		compiledPatterns: compiledPatterns,
-		urlCache:         &sync.Map{},
-	}, nil
+	return rules, nil
 }
 
-func validateStyleOption(cfg config.Config, ruleName, optKey string, valid []string) error {
-	opts := cfg.RuleOptions(ruleName)
-	raw, exists := opts[optKey]
-	if !exists {
-		return nil
+// ruleSeverity returns the severity the config sets for r, or r's default
+// when the config does not list it.
+func ruleSeverity(cfg config.Config, r rule.Rule) string {
+	if rc, ok := cfg.Rules[r.Name()]; ok && rc != nil && rc.Severity != "" {
+		return string(rc.Severity)
 	}
-	val, ok := raw.(string)
-	if !ok {
-		return fmt.Errorf("gomarklint: invalid value for %s.%s: expected string, got %T (%#v) (valid values: %s)", ruleName, optKey, raw, raw, strings.Join(valid, ", "))
-	}
-	if val == "" {
-		return nil
+	return string(r.DefaultSeverity())
+}
+
+// Run lints the files at filePaths on a pool of Jobs workers, or GOMAXPROCS
+// when the config sets none, largest files first so a big file picked up
+// last does not leave the other workers idle at the end. External links are
+// checked afterwards, for all the files at once.
+func (l *Linter) Run(filePaths []string) *Result {
+	paths := largestFirst(dedupe(filePaths))
+
+	jobs := make(chan string)
+	done := make(chan fileResult)
+	workers := min(l.jobs(), len(paths))
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				done <- l.lintFile(p)
+			}
+		}()
 	}
-	for _, v := range valid {
-		if val == v {
-			return nil
+	go func() {
+		for _, p := range paths {
+			jobs <- p
 		}
-	}
-	return fmt.Errorf("gomarklint: invalid value %q for %s.%s (valid values: %s)", val, ruleName, optKey, strings.Join(valid, ", "))
-}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
 
-// validatePerHostIntervalMs rejects values between 1 and 999 (too small to be intentional).
-func validatePerHostIntervalMs(cfg config.Config) error {
-	raw, exists := cfg.RuleOptions("external-link")["perHostIntervalMs"]
-	if !exists {
-		return nil
+	results := make([]fileResult, 0, len(paths))
+	for fr := range done {
+		results = append(results, fr)
 	}
-	f, ok := raw.(float64)
-	if !ok {
-		return fmt.Errorf("gomarklint: invalid value for external-link.perHostIntervalMs: expected integer, got %T (%#v)", raw, raw)
+	l.checkLinks(results)
+
+	result := &Result{
+		Errors:       map[string][]rule.LintError{},
+		OrderedPaths: make([]string, 0, len(paths)),
+		FailedFiles:  map[string]error{},
 	}
-	v := int(f)
-	if v == 0 {
-		return nil
+	for _, fr := range results {
+		result.add(fr)
 	}
-	if v < rule.MinPerHostIntervalMs || v > rule.MaxPerHostIntervalMsLimit {
-		return fmt.Errorf("gomarklint: external-link.perHostIntervalMs must be 0 (disabled) or between %d and %d, got %d", rule.MinPerHostIntervalMs, rule.MaxPerHostIntervalMsLimit, v)
+	sort.Strings(result.OrderedPaths)
+	return result
+}
+
+// fileResult is the outcome of linting one file.
+type fileResult struct {
+	path         string
+	errs         []rule.LintError
+	lines        int
+	links        []rule.LinkSite
+	linksChecked int
+	err          error
+}
+
+func (l *Linter) lintFile(path string) fileResult {
+	content, err := file.ReadFile(path)
+	if err != nil {
+		return fileResult{path: path, err: err}
 	}
-	return nil
+	errs, lines, links := l.collectErrors(path, content)
+	return fileResult{path: path, errs: errs, lines: lines, links: links, linksChecked: len(rule.DistinctURLs(links))}
 }
 
-func validateExternalLinkIntOption(cfg config.Config, optKey string, minVal, maxVal int) error {
-	raw, exists := cfg.RuleOptions("external-link")[optKey]
-	if !exists {
-		return nil
+// checkLinks checks the external links of every file together, so each URL
+// is requested once however many files cite it, and adds the broken ones to
+// the files' errors.
+func (l *Linter) checkLinks(results []fileResult) {
+	if l.links == nil {
+		return
 	}
-	f, ok := raw.(float64)
-	if !ok {
-		return fmt.Errorf("gomarklint: invalid value for external-link.%s: expected integer, got %T (%#v)", optKey, raw, raw)
+	var sites []rule.LinkSite
+	for _, fr := range results {
+		sites = append(sites, fr.links...)
 	}
-	v := int(f)
-	if v < minVal || v > maxVal {
-		return fmt.Errorf("gomarklint: external-link.%s must be between %d and %d, got %d", optKey, minVal, maxVal, v)
+	l.links.Check(rule.DistinctURLs(sites))
+	for i := range results {
+		results[i].errs = l.addLinkErrors(results[i].path, results[i].errs, results[i].links)
 	}
-	return nil
 }
 
-func (l *Linter) Run(filePaths []string) *Result {
-	// Deduplicate file paths to prevent double-counting
-	uniquePaths := make(map[string]struct{})
-	for _, p := range filePaths {
-		uniquePaths[p] = struct{}{}
-	}
-	deduped := make([]string, 0, len(uniquePaths))
-	for p := range uniquePaths {
-		deduped = append(deduped, p)
+// addLinkErrors adds an error to errs for each of sites whose URL is broken.
+// The links must have been checked.
+func (l *Linter) addLinkErrors(path string, errs []rule.LintError, sites []rule.LinkSite) []rule.LintError {
+	linkErrs := l.links.Errors(path, sites)
+	if len(linkErrs) == 0 {
+		return errs
 	}
+	errs = append(errs, withSeverity(linkErrs, l.externalLink.rule.Name(), l.externalLink.severity)...)
+	sortByLine(errs)
+	return errs
+}
 
-	results := map[string][]rule.LintError{}
-	orderedPaths := make([]string, 0, len(deduped))
-	failedFiles := map[string]error{}
-	totalErrors := 0
-	totalWarnings := 0
-	totalLines := 0
-	totalLinksChecked := 0
-
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	for _, path := range deduped {
-		wg.Add(1)
-		go func(p string) {
-			defer wg.Done()
-
-			content, err := file.ReadFile(p)
-			if err != nil {
-				mu.Lock()
-				failedFiles[p] = err
-				mu.Unlock()
-				fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", p, err)
-				return
-			}
-			errors, lineCount, linksChecked := l.collectErrors(p, content)
-
-			mu.Lock()
-			results[p] = errors
-			orderedPaths = append(orderedPaths, p)
-			for _, e := range errors {
-				if e.Severity == "warning" {
-					totalWarnings++
-				} else {
-					totalErrors++
-				}
-			}
-			totalLines += lineCount
-			totalLinksChecked += linksChecked
-			mu.Unlock()
-		}(path)
+func (r *Result) add(fr fileResult) {
+	if fr.err != nil {
+		r.FailedFiles[fr.path] = fr.err
+		fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", fr.path, fr.err)
+		return
+	}
+	r.Errors[fr.path] = fr.errs
+	r.OrderedPaths = append(r.OrderedPaths, fr.path)
+	for _, e := range fr.errs {
+		if e.Severity == "warning" {
+			r.TotalWarnings++
+		} else {
+			r.TotalErrors++
+		}
 	}
+	r.TotalLines += fr.lines
+	r.TotalLinksChecked += fr.linksChecked
+}
 
-	wg.Wait()
+// jobs returns the number of files linted at once.
+func (l *Linter) jobs() int {
+	if l.config.Jobs > 0 {
+		return l.config.Jobs
+	}
+	return runtime.GOMAXPROCS(0)
+}
 
-	sort.Strings(orderedPaths)
+// dedupe drops repeated paths, which would otherwise be counted twice.
+func dedupe(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	unique := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
 
-	return &Result{
-		Errors:            results,
-		OrderedPaths:      orderedPaths,
-		TotalErrors:       totalErrors,
-		TotalWarnings:     totalWarnings,
-		TotalLines:        totalLines,
-		TotalLinksChecked: totalLinksChecked,
-		FailedFiles:       failedFiles,
+// largestFirst sorts paths by decreasing file size. Files that cannot be
+// stat'ed sort last; reading them reports the error.
+func largestFirst(paths []string) []string {
+	sizes := make(map[string]int64, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			sizes[p] = info.Size()
+		} else {
+			sizes[p] = -1
+		}
 	}
+	sort.SliceStable(paths, func(i, j int) bool { return sizes[paths[i]] > sizes[paths[j]] })
+	return paths
 }
 
 func (l *Linter) LintContent(path string, content string) ([]rule.LintError, int, int) {
-	return l.collectErrors(path, content)
+	errs, lines, links := l.collectErrors(path, content)
+	urls := rule.DistinctURLs(links)
+	if len(urls) > 0 {
+		l.links.Check(urls)
+		errs = l.addLinkErrors(path, errs, links)
+	}
+	return errs, lines, len(urls)
 }
 
-func (l *Linter) withSeverity(errs []rule.LintError, ruleName string) []rule.LintError {
-	sev := l.config.RuleSeverity(ruleName)
+// withSeverity attributes errs to ruleName at severity sev. A finding whose
+// rule already set a severity, as local-assets does for oversized files,
+// keeps it.
+func withSeverity(errs []rule.LintError, ruleName, sev string) []rule.LintError {
 	for i := range errs {
 		errs[i].Rule = ruleName
-		errs[i].Severity = sev
+		if errs[i].Severity == "" {
+			errs[i].Severity = sev
+		}
 	}
 	return errs
 }
 
-func (l *Linter) headingMinLevel() int {
-	minLevel := 2
-	if v, ok := l.config.RuleOptions("heading-level")["minLevel"]; ok {
-		if f, ok := v.(float64); ok {
-			minLevel = int(f)
-		}
+// RuleNames returns the name of every registered rule, in dispatch order.
+func RuleNames() []string {
+	rules := rule.Registered()
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.Name()
 	}
-	return minLevel
+	return names
 }
 
-func (l *Linter) noTrailingPunctuation() string {
-	if v, ok := l.config.RuleOptions("no-trailing-punctuation")["punctuation"]; ok {
-		if s, ok := v.(string); ok {
-			return s
+// UseCache makes the linter keep the findings of each file in c and reuse
+// them while the file, its path and the rule config are unchanged. Plugins
+// and other uncached rules still run on every file.
+func (l *Linter) UseCache(c *cache.Cache) error {
+	key, err := json.Marshal(struct {
+		Default     bool
+		Rules       map[string]*config.RuleConfig
+		CustomRules map[string]config.CustomRuleConfig
+	}{l.config.Default, l.config.Rules, l.config.CustomRules})
+	if err != nil {
+		return err
+	}
+	l.cache = c
+	l.configKey = string(key)
+	l.cachedRules, l.uncachedRules = nil, nil
+	for _, cr := range l.rules {
+		if isCacheable(cr.rule) {
+			l.cachedRules = append(l.cachedRules, cr)
+		} else {
+			l.uncachedRules = append(l.uncachedRules, cr)
 		}
 	}
-	return config.DefaultNoTrailingPunctuation
+	return nil
 }
 
-func (l *Linter) consistentCodeFenceStyle() string {
-	style, _ := l.config.RuleOptions("consistent-code-fence")["style"].(string)
-	if style == "" {
-		return "consistent"
+// UseLinkCache makes the linter reuse the external-link results saved in c
+// while they are within the rule's cacheTTL, or failureCacheTTL for links
+// that failed, which is 0 unless configured. With refresh, every link is
+// checked again. Either way SaveLinks saves the results of the run to c.
+func (l *Linter) UseLinkCache(c *cache.Cache, refresh bool) {
+	if l.externalLink == nil {
+		return
+	}
+	l.linkCache = c
+	if refresh {
+		return
+	}
+	fresh := map[string]rule.CachedLink{}
+	for url, link := range c.Links() {
+		if l.linkFresh(link) {
+			fresh[url] = link
+		}
 	}
-	return style
+	rule.PreloadLinks(l.urlCache, fresh)
 }
 
-func (l *Linter) consistentEmphasisStyle() string {
-	style, _ := l.config.RuleOptions("consistent-emphasis-style")["style"].(string)
-	if style == "" {
-		return "consistent"
+// SaveLinks saves the external-link results of the run to the link cache, if
+// there is one, dropping those that have expired.
+func (l *Linter) SaveLinks() error {
+	if l.linkCache == nil {
+		return nil
 	}
-	return style
+	return l.linkCache.SaveLinks(rule.CheckedLinks(l.urlCache), l.linkFresh)
 }
 
-func (l *Linter) consistentListMarkerStyle() string {
-	style, _ := l.config.RuleOptions("consistent-list-marker")["style"].(string)
-	if style == "" {
-		return "consistent"
+// linkFresh reports whether a saved link result is recent enough to reuse.
+func (l *Linter) linkFresh(link rule.CachedLink) bool {
+	name := "cacheTTL"
+	if link.Failed() {
+		name = "failureCacheTTL"
 	}
-	return style
+	// The options are validated, so the duration parses.
+	ttl, _ := time.ParseDuration(l.externalLink.options.String(name))
+	return time.Since(link.CheckedAt) < ttl
 }
 
-func (l *Linter) maxLineLength() int {
-	lineLength := 80
-	if v, ok := l.config.RuleOptions("max-line-length")["lineLength"]; ok {
-		if f, ok := v.(float64); ok && int(f) > 0 {
-			lineLength = int(f)
-		}
+func isCacheable(r rule.Rule) bool {
+	if _, ok := r.(rule.FileRule); ok {
+		return false
 	}
-	return lineLength
+	_, ok := r.(rule.UncachedRule)
+	return !ok
 }
 
-func (l *Linter) externalLinkTimeout() int {
-	timeoutSeconds := 5
-	if v, ok := l.config.RuleOptions("external-link")["timeoutSeconds"]; ok {
-		if f, ok := v.(float64); ok && int(f) > 0 {
-			timeoutSeconds = int(f)
+// checkRules runs the enabled rules on a file, taking the findings of
+// cacheable rules from the cache when it has them. It also returns the
+// scanned file, or nil when every finding came from the cache.
+func (l *Linter) checkRules(path, content string, lines []string, offset int) ([]rule.LintError, *preprocess.Context) {
+	if l.cache == nil {
+		ctx := preprocess.Scan(lines)
+		return l.collectLineErrors(path, content, ctx, offset, l.rules), ctx
+	}
+	var ctx *preprocess.Context
+	key := l.cache.Key(l.configKey, path, content)
+	errs, ok := l.cache.Get(key)
+	if !ok {
+		ctx = preprocess.Scan(lines)
+		errs = l.collectLineErrors(path, content, ctx, offset, l.cachedRules)
+		// The cache only saves work; a failed write costs nothing else.
+		_ = l.cache.Put(key, errs)
+	}
+	if len(l.uncachedRules) > 0 {
+		if ctx == nil {
+			ctx = preprocess.Scan(lines)
 		}
+		errs = append(errs, l.collectLineErrors(path, content, ctx, offset, l.uncachedRules)...)
 	}
-	return timeoutSeconds
+	return errs, ctx
 }
 
-func (l *Linter) externalLinkMaxConcurrency() int {
-	if v, ok := l.config.RuleOptions("external-link")["maxConcurrency"]; ok {
-		if f, ok := v.(float64); ok && int(f) > 0 {
-			return int(f)
+func (l *Linter) collectLineErrors(path, content string, ctx *preprocess.Context, offset int, rules []configuredRule) []rule.LintError {
+	var errs []rule.LintError
+	for _, cr := range rules {
+		fr, ok := cr.rule.(rule.FileRule)
+		if !ok {
+			errs = append(errs, withSeverity(cr.rule.Check(path, ctx, offset, cr.options), cr.rule.Name(), cr.severity)...)
+			continue
+		}
+		found, err := fr.CheckFile(path, content, ctx, offset, cr.options)
+		if err != nil {
+			// A rule that fails is reported as an error on the file, whatever
+			// its configured severity, so the run cannot pass silently.
+			errs = append(errs, rule.LintError{
+				File:     path,
+				Line:     1,
+				Rule:     cr.rule.Name(),
+				Message:  fmt.Sprintf("%s: %v", cr.rule.Name(), err),
+				Severity: string(config.SeverityError),
+			})
+			continue
 		}
+		errs = append(errs, withSeverity(found, cr.rule.Name(), cr.severity)...)
 	}
-	return rule.DefaultMaxConcurrency
+	return errs
 }
 
-func (l *Linter) externalLinkMaxRetries() int {
-	if v, ok := l.config.RuleOptions("external-link")["maxRetries"]; ok {
-		if f, ok := v.(float64); ok && int(f) >= 0 {
-			return int(f)
+// maxFixPasses bounds the lint-and-fix loop in FixContent. Each pass applies
+// every non-overlapping fix, so a handful of passes settles any real file.
+const maxFixPasses = 10
+
+// FixContent applies every available fix to content, re-linting between passes
+// so fixes skipped for overlapping an earlier edit get another chance. It
+// returns the fixed content and the number of fixes applied. Violations on
+// lines silenced by disable comments, and warnings when only errors are
+// reported, are left untouched. External links are never checked.
+func (l *Linter) FixContent(path string, content string) (string, int) {
+	total := 0
+	for pass := 0; pass < maxFixPasses; pass++ {
+		errs := l.fixableErrors(path, content)
+		fixed, n := fix.Apply(content, errs)
+		if n == 0 {
+			break
 		}
+		content = fixed
+		total += n
 	}
-	return rule.DefaultMaxRetries
+	return content, total
 }
 
-func (l *Linter) externalLinkPerHostConcurrency() int {
-	if v, ok := l.config.RuleOptions("external-link")["perHostConcurrency"]; ok {
-		if f, ok := v.(float64); ok && int(f) > 0 {
-			return int(f)
-		}
+func (l *Linter) fixableErrors(path string, content string) []rule.LintError {
+	body, offset := file.StripFrontmatter(content)
+	lines := strings.Split(body, "\n")
+	disabled := disabledLines(body, lines, offset)
+	var directives map[int]bool
+	if len(disabled) > 0 {
+		directives = nextLineDirectives(lines, offset)
 	}
-	return rule.DefaultPerHostConcurrency
-}
 
-func (l *Linter) externalLinkPerHostIntervalMs() int {
-	if v, ok := l.config.RuleOptions("external-link")["perHostIntervalMs"]; ok {
-		if f, ok := v.(float64); ok && int(f) >= 0 {
-			return int(f)
+	var errs []rule.LintError
+	for _, e := range l.collectLineErrors(path, content, preprocess.Scan(lines), offset, l.rules) {
+		if e.Fix == nil || disabled.isDisabled(e.Line, e.Rule) || fixTouchesDisabled(e, disabled) || shiftsDirectiveTarget(e, directives) {
+			continue
 		}
+		if l.config.MinSeverity == config.SeverityError && e.Severity == string(config.SeverityWarning) {
+			continue
+		}
+		errs = append(errs, e)
 	}
-	return rule.DefaultPerHostIntervalMs
+	return errs
 }
 
-func (l *Linter) externalLinkAllowedStatuses() []int {
-	raw, _ := l.config.RuleOptions("external-link")["allowedStatuses"].([]interface{})
-	statuses := make([]int, 0, len(raw))
-	for _, item := range raw {
-		if f, ok := item.(float64); ok {
-			statuses = append(statuses, int(f))
-		}
+// fixTouchesDisabled reports whether any edit of e's fix starts or ends on a
+// line where e's rule is disabled.
+func fixTouchesDisabled(e rule.LintError, disabled disabledSet) bool {
+	if len(disabled) == 0 {
+		return false
 	}
-	return statuses
-}
-
-var simpleRules = []struct {
-	name string
-	fn   func(string, []string, int) []rule.LintError
-}{
-	{"final-blank-line", rule.CheckFinalBlankLine},
-}
-
-var contextRules = []struct {
-	name string
-	fn   func(string, *preprocess.Context, int) []rule.LintError
-}{
-	{"no-bare-urls", rule.CheckNoBareURLs},
-	{"single-h1", rule.CheckSingleH1},
-	{"duplicate-heading", rule.CheckDuplicateHeadings},
-	{"no-setext-headings", rule.CheckNoSetextHeadings},
-	{"blanks-around-headings", rule.CheckBlanksAroundHeadings},
-	{"no-emphasis-as-heading", rule.CheckNoEmphasisAsHeading},
-	{"unclosed-code-block", rule.CheckUnclosedCodeBlocks},
-	{"fenced-code-language", rule.CheckFencedCodeLanguage},
-	{"blanks-around-fences", rule.CheckBlanksAroundFences},
-	{"empty-alt-text", rule.CheckEmptyAltText},
-	{"no-empty-links", rule.CheckNoEmptyLinks},
-	{"no-multiple-blank-lines", rule.CheckNoMultipleBlankLines},
-	{"blanks-around-lists", rule.CheckBlanksAroundLists},
-	{"no-hard-tabs", rule.CheckNoHardTabs},
-}
-
-func (l *Linter) collectLineErrors(path string, lines []string, ctx *preprocess.Context, offset int) []rule.LintError {
-	var errs []rule.LintError
-
-	for _, r := range simpleRules {
-		if l.config.IsEnabled(r.name) {
-			errs = append(errs, l.withSeverity(r.fn(path, lines, offset), r.name)...)
+	for _, ed := range e.Fix.Edits {
+		if disabled.isDisabled(ed.Line, e.Rule) || disabled.isDisabled(ed.EndLine, e.Rule) {
+			return true
 		}
 	}
+	return false
+}
 
-	for _, r := range contextRules {
-		if l.config.IsEnabled(r.name) {
-			errs = append(errs, l.withSeverity(r.fn(path, ctx, offset), r.name)...)
+// shiftsDirectiveTarget reports whether e's fix adds or removes a line break
+// on a disable-next-line comment or at the start of the line below it, which
+// would move the comment off its target. Breaks added later on the target
+// line, such as a final newline, leave it in place.
+func shiftsDirectiveTarget(e rule.LintError, directives map[int]bool) bool {
+	for _, ed := range e.Fix.Edits {
+		if ed.EndLine == ed.Line && !strings.Contains(ed.NewText, "\n") {
+			continue
+		}
+		if directives[ed.Line] || (directives[ed.Line-1] && ed.Column == 1) {
+			return true
 		}
 	}
+	return false
+}
 
-	if l.config.IsEnabled("heading-level") {
-		errs = append(errs, l.withSeverity(rule.CheckHeadingLevels(path, ctx, offset, l.headingMinLevel()), "heading-level")...)
-	}
-	if l.config.IsEnabled("consistent-code-fence") {
-		errs = append(errs, l.withSeverity(rule.CheckConsistentCodeFence(path, ctx, offset, l.consistentCodeFenceStyle()), "consistent-code-fence")...)
-	}
-	if l.config.IsEnabled("consistent-emphasis-style") {
-		errs = append(errs, l.withSeverity(rule.CheckConsistentEmphasisStyle(path, ctx, offset, l.consistentEmphasisStyle()), "consistent-emphasis-style")...)
-	}
-	if l.config.IsEnabled("consistent-list-marker") {
-		errs = append(errs, l.withSeverity(rule.CheckConsistentListMarker(path, ctx, offset, l.consistentListMarkerStyle()), "consistent-list-marker")...)
-	}
-	if l.config.IsEnabled("max-line-length") {
-		errs = append(errs, l.withSeverity(rule.CheckMaxLineLength(path, ctx, offset, l.maxLineLength()), "max-line-length")...)
-	}
-	if l.config.IsEnabled("no-trailing-punctuation") {
-		errs = append(errs, l.withSeverity(rule.CheckNoTrailingPunctuation(path, ctx, offset, l.noTrailingPunctuation()), "no-trailing-punctuation")...)
-	}
-	if l.config.IsEnabled("link-fragments") {
-		errs = append(errs, l.withSeverity(rule.CheckLinkFragments(path, ctx, offset, l.config.RuleOptions("link-fragments")), "link-fragments")...)
+func disabledLines(body string, lines []string, offset int) disabledSet {
+	if !strings.Contains(body, "gomarklint-disable") {
+		return nil
 	}
-	return errs
+	return parseDisableComments(lines, offset)
 }
 
-func (l *Linter) collectErrors(path string, content string) ([]rule.LintError, int, int) {
+// collectErrors runs the rules on a file. It returns the external links to
+// check rather than checking them, so the links of many files can be checked
+// together.
+func (l *Linter) collectErrors(path string, content string) ([]rule.LintError, int, []rule.LinkSite) {
 	body, offset := file.StripFrontmatter(content)
 	lines := strings.Split(body, "\n")
+	disabled := disabledLines(body, lines, offset)
 
-	var disabled disabledSet
-	if strings.Contains(body, "gomarklint-disable") {
-		disabled = parseDisableComments(lines, offset)
-	}
-
-	ctx := preprocess.Scan(lines)
-
-	allErrors := l.collectLineErrors(path, lines, ctx, offset)
+	allErrors, ctx := l.checkRules(path, content, lines, offset)
 
-	linksChecked := 0
-	if l.config.IsEnabled("external-link") {
-		errors, count := rule.CheckExternalLinks(path, ctx, offset, l.compiledPatterns, l.externalLinkTimeout(), rule.DefaultRetryDelayMs, l.externalLinkMaxConcurrency(), l.externalLinkMaxRetries(), l.externalLinkAllowedStatuses(), l.urlCache, l.externalLinkPerHostConcurrency(), l.externalLinkPerHostIntervalMs())
-		allErrors = append(allErrors, l.withSeverity(errors, "external-link")...)
-		linksChecked = count
+	var links []rule.LinkSite
+	if l.externalLink != nil {
+		if ctx == nil {
+			ctx = preprocess.Scan(lines)
+		}
+		links = rule.ExternalLinkSites(ctx, offset, l.compiledPatterns)
 	}
 
 	if len(disabled) > 0 {
@@ -422,12 +586,23 @@ func (l *Linter) collectErrors(path string, content string) ([]rule.LintError, i
 			}
 		}
 		allErrors = filtered
+		enabled := links[:0]
+		for _, s := range links {
+			if !disabled.isDisabled(s.Line, rule.ExternalLinkName) {
+				enabled = append(enabled, s)
+			}
+		}
+		links = enabled
 	}
 
-	sort.Slice(allErrors, func(i, j int) bool {
-		return allErrors[i].Line < allErrors[j].Line
-	})
+	sortByLine(allErrors)
 
 	lineCount := strings.Count(content, "\n") + 1
-	return allErrors, lineCount, linksChecked
+	return allErrors, lineCount, links
+}
+
+func sortByLine(errs []rule.LintError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
 }
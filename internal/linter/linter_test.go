@@ -6,9 +6,15 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/shinagawa-web/gomarklint/v3/internal/cache"
 	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
 )
 
 // mustNew calls New and fails the test if it returns an error.
@@ -21,6 +27,18 @@ func mustNew(t *testing.T, cfg config.Config) *Linter {
 	return l
 }
 
+// ruleOptions returns the resolved options of an enabled rule.
+func ruleOptions(t *testing.T, l *Linter, name string) rule.Options {
+	t.Helper()
+	for _, cr := range l.rules {
+		if cr.rule.Name() == name {
+			return cr.options
+		}
+	}
+	t.Fatalf("rule %q is not enabled", name)
+	return nil
+}
+
 // off returns a disabled RuleConfig.
 func off() *config.RuleConfig {
 	return &config.RuleConfig{Enabled: false, Severity: config.SeverityOff, Options: map[string]interface{}{}}
@@ -152,6 +170,68 @@ func TestRun_MultipleFiles(t *testing.T) {
 	}
 }
 
+func TestRun_Jobs(t *testing.T) {
+	tmpDir := t.TempDir()
+	var paths []string
+	for i := 0; i < 20; i++ {
+		p := filepath.Join(tmpDir, fmt.Sprintf("doc%02d.md", i))
+		content := "# Test\n\n" + strings.Repeat("text\n", i) + "\n\n\nend\n"
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	missing := filepath.Join(tmpDir, "missing.md")
+
+	for _, jobs := range []int{0, 1, 3, 64} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Jobs = jobs
+			result := mustNew(t, cfg).Run(append([]string{missing, paths[0]}, paths...))
+
+			if len(result.OrderedPaths) != len(paths) || !sort.StringsAreSorted(result.OrderedPaths) {
+				t.Errorf("expected %d sorted paths, got %v", len(paths), result.OrderedPaths)
+			}
+			want := 0
+			for _, p := range paths {
+				content, _ := os.ReadFile(p)
+				errs, _, _ := mustNew(t, cfg).LintContent(p, string(content))
+				want += len(errs)
+				if len(result.Errors[p]) != len(errs) {
+					t.Errorf("%s: got %d errors, want %d", p, len(result.Errors[p]), len(errs))
+				}
+			}
+			if want == 0 || result.TotalErrors != want {
+				t.Errorf("TotalErrors = %d, want %d", result.TotalErrors, want)
+			}
+			if _, ok := result.FailedFiles[missing]; !ok || len(result.FailedFiles) != 1 {
+				t.Errorf("expected only the missing file to fail, got %v", result.FailedFiles)
+			}
+		})
+	}
+}
+
+func TestLargestFirst(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name string, size int) string {
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	small, large, medium := write("small.md", 1), write("large.md", 100), write("medium.md", 10)
+	missing := filepath.Join(tmpDir, "missing.md")
+
+	got := largestFirst([]string{small, missing, large, medium})
+	want := []string{large, medium, small, missing}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
 func TestRun_UnclosedCodeBlock(t *testing.T) {
 	cfg := allOff()
 	cfg.Rules["unclosed-code-block"] = on()
@@ -585,6 +665,31 @@ func TestRun_WarningSeverity(t *testing.T) {
 	}
 }
 
+func TestRun_RuleSetSeverity(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["local-assets"] = &config.RuleConfig{
+		Enabled:  true,
+		Severity: config.SeverityError,
+		Options:  map[string]interface{}{"maxBytes": float64(4)},
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.png"), []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testFile := filepath.Join(dir, "doc.md")
+	if err := os.WriteFile(testFile, []byte("![big](big.png)\n\n![gone](gone.png)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := mustNew(t, cfg).Run([]string{testFile})
+
+	// The oversized image is a warning the rule sets itself; the missing
+	// one takes the configured severity.
+	if result.TotalWarnings != 1 || result.TotalErrors != 1 {
+		t.Errorf("expected 1 warning and 1 error, got %d and %d: %v", result.TotalWarnings, result.TotalErrors, result.Errors)
+	}
+}
+
 func TestRun_DisableComment_BlockDisableAll(t *testing.T) {
 	cfg := allOff()
 	cfg.Rules["no-bare-urls"] = on()
@@ -731,8 +836,8 @@ func TestRun_MaxLineLength_NoOptionFallsBackToDefault(t *testing.T) {
 	}
 
 	linter := mustNew(t, cfg)
-	if linter.maxLineLength() != 80 {
-		t.Errorf("expected default maxLineLength 80, got %d", linter.maxLineLength())
+	if ruleOptions(t, linter, "max-line-length").Int("lineLength") != 80 {
+		t.Errorf("expected default maxLineLength 80, got %d", ruleOptions(t, linter, "max-line-length").Int("lineLength"))
 	}
 }
 
@@ -788,6 +893,68 @@ func TestRun_LinkCheckWithAllowedStatuses(t *testing.T) {
 	}
 }
 
+func TestRun_LinksCheckedAcrossFiles(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	var times []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		times = append(times, time.Now())
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := allOff()
+	cfg.Jobs = 3
+	cfg.Rules["external-link"] = &config.RuleConfig{
+		Enabled:  true,
+		Severity: config.SeverityError,
+		Options:  map[string]interface{}{"perHostIntervalMs": float64(1000)},
+	}
+	dir := t.TempDir()
+	var paths []string
+	for i, link := range []string{"/ok", "/broken", "/other"} {
+		path := filepath.Join(dir, fmt.Sprintf("doc%d.md", i))
+		// Every file cites /broken, and one other URL.
+		content := fmt.Sprintf("[a](%s%s)\n\n[b](%s/broken)\n", ts.URL, link, ts.URL)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	result := mustNew(t, cfg).Run(paths)
+
+	// /broken is reported in every file, on each line citing it.
+	if result.TotalErrors != 4 {
+		t.Errorf("expected 4 errors, got %d: %v", result.TotalErrors, result.Errors)
+	}
+	for _, p := range paths {
+		if errs := result.Errors[p]; len(errs) == 0 || errs[len(errs)-1].Line != 3 || errs[len(errs)-1].Rule != "external-link" {
+			t.Errorf("expected /broken reported on line 3 of %s, got %v", p, errs)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for path, n := range hits {
+		if n != 1 {
+			t.Errorf("expected %s requested once, got %d", path, n)
+		}
+	}
+	// One limiter spaces the requests to the host, whichever file cites them.
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 900*time.Millisecond {
+			t.Errorf("requests %d and %d were %v apart, want at least the host interval", i-1, i, gap)
+		}
+	}
+}
+
 func TestRun_NoTrailingPunctuation_Violation(t *testing.T) {
 	cfg := allOff()
 	cfg.Rules["no-trailing-punctuation"] = &config.RuleConfig{
@@ -813,8 +980,8 @@ func TestRun_NoTrailingPunctuation_NoOptionFallsBackToDefault(t *testing.T) {
 	}
 
 	linter := mustNew(t, cfg)
-	if linter.noTrailingPunctuation() != config.DefaultNoTrailingPunctuation {
-		t.Errorf("expected default punctuation %q, got %q", config.DefaultNoTrailingPunctuation, linter.noTrailingPunctuation())
+	if ruleOptions(t, linter, "no-trailing-punctuation").String("punctuation") != config.DefaultNoTrailingPunctuation {
+		t.Errorf("expected default punctuation %q, got %q", config.DefaultNoTrailingPunctuation, ruleOptions(t, linter, "no-trailing-punctuation").String("punctuation"))
 	}
 }
 
@@ -891,8 +1058,8 @@ func TestRun_ConsistentCodeFence_NoOptionFallsBackToConsistent(t *testing.T) {
 	}
 
 	linter := mustNew(t, cfg)
-	if linter.consistentCodeFenceStyle() != "consistent" {
-		t.Errorf("expected fallback style %q, got %q", "consistent", linter.consistentCodeFenceStyle())
+	if ruleOptions(t, linter, "consistent-code-fence").String("style") != "consistent" {
+		t.Errorf("expected fallback style %q, got %q", "consistent", ruleOptions(t, linter, "consistent-code-fence").String("style"))
 	}
 }
 
@@ -937,8 +1104,8 @@ func TestRun_ConsistentEmphasisStyle_NoOptionFallsBackToConsistent(t *testing.T)
 	}
 
 	linter := mustNew(t, cfg)
-	if linter.consistentEmphasisStyle() != "consistent" {
-		t.Errorf("expected fallback style %q, got %q", "consistent", linter.consistentEmphasisStyle())
+	if ruleOptions(t, linter, "consistent-emphasis-style").String("style") != "consistent" {
+		t.Errorf("expected fallback style %q, got %q", "consistent", ruleOptions(t, linter, "consistent-emphasis-style").String("style"))
 	}
 }
 
@@ -951,8 +1118,8 @@ func TestRun_ConsistentListMarker_NoOptionFallsBackToConsistent(t *testing.T) {
 	}
 
 	linter := mustNew(t, cfg)
-	if linter.consistentListMarkerStyle() != "consistent" {
-		t.Errorf("expected fallback style %q, got %q", "consistent", linter.consistentListMarkerStyle())
+	if ruleOptions(t, linter, "consistent-list-marker").String("style") != "consistent" {
+		t.Errorf("expected fallback style %q, got %q", "consistent", ruleOptions(t, linter, "consistent-list-marker").String("style"))
 	}
 }
 
@@ -1060,7 +1227,7 @@ func TestExternalLink_ConfigurableMaxConcurrency(t *testing.T) {
 		Options:  map[string]interface{}{"maxConcurrency": float64(3)},
 	}
 	l := mustNew(t, cfg)
-	if got := l.externalLinkMaxConcurrency(); got != 3 {
+	if got := l.externalLink.options.Int("maxConcurrency"); got != 3 {
 		t.Errorf("expected maxConcurrency 3, got %d", got)
 	}
 }
@@ -1073,7 +1240,7 @@ func TestExternalLink_ConfigurableMaxRetries(t *testing.T) {
 		Options:  map[string]interface{}{"maxRetries": float64(1)},
 	}
 	l := mustNew(t, cfg)
-	if got := l.externalLinkMaxRetries(); got != 1 {
+	if got := l.externalLink.options.Int("maxRetries"); got != 1 {
 		t.Errorf("expected maxRetries 1, got %d", got)
 	}
 }
@@ -1086,7 +1253,7 @@ func TestExternalLink_MaxRetriesZeroIsValid(t *testing.T) {
 		Options:  map[string]interface{}{"maxRetries": float64(0)},
 	}
 	l := mustNew(t, cfg)
-	if got := l.externalLinkMaxRetries(); got != 0 {
+	if got := l.externalLink.options.Int("maxRetries"); got != 0 {
 		t.Errorf("expected maxRetries 0, got %d", got)
 	}
 }
@@ -1151,7 +1318,7 @@ func TestExternalLink_ConfigurablePerHostConcurrency(t *testing.T) {
 		Options:  map[string]interface{}{"perHostConcurrency": float64(3)},
 	}
 	l := mustNew(t, cfg)
-	if got := l.externalLinkPerHostConcurrency(); got != 3 {
+	if got := l.externalLink.options.Int("perHostConcurrency"); got != 3 {
 		t.Errorf("expected perHostConcurrency 3, got %d", got)
 	}
 }
@@ -1203,7 +1370,7 @@ func TestExternalLink_ConfigurablePerHostIntervalMs(t *testing.T) {
 		Options:  map[string]interface{}{"perHostIntervalMs": float64(1000)},
 	}
 	l := mustNew(t, cfg)
-	if got := l.externalLinkPerHostIntervalMs(); got != 1000 {
+	if got := l.externalLink.options.Int("perHostIntervalMs"); got != 1000 {
 		t.Errorf("expected perHostIntervalMs 1000, got %d", got)
 	}
 }
@@ -1216,7 +1383,7 @@ func TestExternalLink_PerHostIntervalMsZeroIsValid(t *testing.T) {
 		Options:  map[string]interface{}{"perHostIntervalMs": float64(0)},
 	}
 	l := mustNew(t, cfg)
-	if got := l.externalLinkPerHostIntervalMs(); got != 0 {
+	if got := l.externalLink.options.Int("perHostIntervalMs"); got != 0 {
 		t.Errorf("expected perHostIntervalMs 0, got %d", got)
 	}
 }
@@ -1259,3 +1426,330 @@ func TestExternalLink_PerHostIntervalMsWrongTypeReturnsError(t *testing.T) {
 		t.Fatal("expected error for non-integer perHostIntervalMs, got nil")
 	}
 }
+
+func TestRuleNames_CoversDefaultConfig(t *testing.T) {
+	names := map[string]bool{}
+	for _, n := range RuleNames() {
+		if names[n] {
+			t.Errorf("duplicate rule name %q", n)
+		}
+		names[n] = true
+	}
+	for name := range config.Default().Rules {
+		if !names[name] {
+			t.Errorf("rule %q from config.Default() missing from RuleNames()", name)
+		}
+	}
+}
+
+func TestNew_UnknownOptionReturnsError(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["max-line-length"] = &config.RuleConfig{
+		Enabled:  true,
+		Severity: config.SeverityError,
+		Options:  map[string]interface{}{"linelength": float64(100)},
+	}
+	_, err := New(cfg)
+	want := `gomarklint: unknown option "linelength" for max-line-length (valid options: lineLength)`
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error:\ngot:  %v\nwant: %s", err, want)
+	}
+}
+
+// writePlugin writes an executable shell script that discards its request
+// and prints response.
+func writePlugin(t *testing.T, response string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	script := "#!/bin/sh\ncat >/dev/null\necho '" + response + "'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+	return path
+}
+
+func TestLintContent_Plugin(t *testing.T) {
+	script := writePlugin(t, `{"findings":[{"line":3,"column":1,"end_column":5,"message":"avoid \"TODO\""},{"line":6,"message":"second"}]}`)
+	cfg := allOff()
+	cfg.Plugins = []config.PluginConfig{{Name: "house-style", Command: []string{script}}}
+	cfg.Rules["house-style"] = &config.RuleConfig{Enabled: true, Severity: config.SeverityWarning, Options: map[string]interface{}{"x": true}}
+
+	content := "# Title\n\nTODO\n\n<!-- gomarklint-disable-next-line house-style -->\nsecond\n"
+	errs, _, _ := mustNew(t, cfg).LintContent("doc.md", content)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %v", len(errs), errs)
+	}
+	want := rule.LintError{File: "doc.md", Line: 3, Column: 1, EndColumn: 5, Rule: "house-style", Message: `house-style: avoid "TODO"`, Severity: "warning"}
+	if errs[0] != want {
+		t.Errorf("got %+v, want %+v", errs[0], want)
+	}
+}
+
+func TestLintContent_PluginFailureIsReportedAsError(t *testing.T) {
+	cfg := allOff()
+	cfg.Plugins = []config.PluginConfig{{Name: "house-style", Command: []string{writePlugin(t, "oops")}}}
+	cfg.Rules["house-style"] = &config.RuleConfig{Enabled: true, Severity: config.SeverityWarning, Options: map[string]interface{}{}}
+
+	errs, _, _ := mustNew(t, cfg).LintContent("doc.md", "# Title\n")
+	if len(errs) != 1 || errs[0].Severity != "error" || errs[0].Line != 1 {
+		t.Fatalf("expected one error on line 1, got %v", errs)
+	}
+	if want := "house-style: plugin failed: invalid response"; !strings.HasPrefix(errs[0].Message, want) {
+		t.Errorf("unexpected message %q", errs[0].Message)
+	}
+}
+
+func TestLintContent_PluginDisabledByRules(t *testing.T) {
+	cfg := allOff()
+	cfg.Plugins = []config.PluginConfig{{Name: "house-style", Command: []string{writePlugin(t, "oops")}}}
+	cfg.Rules["house-style"] = off()
+
+	if errs, _, _ := mustNew(t, cfg).LintContent("doc.md", "# Title\n"); len(errs) != 0 {
+		t.Errorf("expected a disabled plugin not to run, got %v", errs)
+	}
+}
+
+func TestNew_InvalidPlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []config.PluginConfig
+		want    string
+	}{
+		{"no name", []config.PluginConfig{{Command: []string{"x"}}}, "gomarklint: plugins[0] has no name"},
+		{"no command", []config.PluginConfig{{Name: "p"}}, `gomarklint: plugin "p" has no command`},
+		{"built-in name", []config.PluginConfig{{Name: "single-h1", Command: []string{"x"}}}, `gomarklint: plugin "single-h1" has the name of a built-in rule`},
+		{"duplicate", []config.PluginConfig{{Name: "p", Command: []string{"x"}}, {Name: "p", Command: []string{"y"}}}, `gomarklint: plugin "p" is declared twice`},
+		{"negative timeout", []config.PluginConfig{{Name: "p", Command: []string{"x"}, TimeoutSeconds: -1}}, `gomarklint: plugin "p" timeoutSeconds must be at least 1, got -1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := allOff()
+			cfg.Plugins = tt.plugins
+			_, err := New(cfg)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("unexpected error:\ngot:  %v\nwant: %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintContent_CustomRules(t *testing.T) {
+	cfg := allOff()
+	cfg.CustomRules = map[string]config.CustomRuleConfig{
+		"no-internal-hosts": {Pattern: `corp\.internal`, Message: "use the public hostname", Scope: "link-url"},
+		"no-utilize":        {Pattern: `\butilize\b`},
+	}
+	cfg.Rules["no-utilize"] = &config.RuleConfig{Enabled: true, Severity: config.SeverityWarning, Options: map[string]interface{}{}}
+
+	content := "# Title\n\nWe utilize [docs](https://corp.internal/a).\n\n<!-- gomarklint-disable-next-line no-internal-hosts -->\nSee https://corp.internal/b and `utilize`.\n"
+	errs, _, _ := mustNew(t, cfg).LintContent("doc.md", content)
+	want := []rule.LintError{
+		{File: "doc.md", Line: 3, Column: 4, EndLine: 3, EndColumn: 11, Rule: "no-utilize", Message: `no-utilize: found "utilize"`, Severity: "warning"},
+		{File: "doc.md", Line: 3, Column: 27, EndLine: 3, EndColumn: 40, Rule: "no-internal-hosts", Message: "no-internal-hosts: use the public hostname", Severity: "error"},
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Column < errs[j].Column })
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("error %d: got %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+}
+
+func TestLintContent_CustomRuleDisabledByRules(t *testing.T) {
+	cfg := allOff()
+	cfg.CustomRules = map[string]config.CustomRuleConfig{"no-foo": {Pattern: "foo"}}
+	cfg.Rules["no-foo"] = off()
+
+	if errs, _, _ := mustNew(t, cfg).LintContent("doc.md", "foo\n"); len(errs) != 0 {
+		t.Errorf("expected a disabled custom rule not to run, got %v", errs)
+	}
+}
+
+func TestNew_InvalidCustomRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   map[string]config.CustomRuleConfig
+		plugins []config.PluginConfig
+		options map[string]interface{}
+		want    string
+	}{
+		{name: "built-in name", rules: map[string]config.CustomRuleConfig{"single-h1": {Pattern: "x"}}, want: `gomarklint: custom rule "single-h1" has the name of a built-in rule or plugin`},
+		{name: "plugin name", rules: map[string]config.CustomRuleConfig{"p": {Pattern: "x"}}, plugins: []config.PluginConfig{{Name: "p", Command: []string{"x"}}}, want: `gomarklint: custom rule "p" has the name of a built-in rule or plugin`},
+		{name: "bad pattern", rules: map[string]config.CustomRuleConfig{"c": {Pattern: "("}}, want: "gomarklint: custom rule \"c\": invalid pattern: error parsing regexp: missing closing ): `(`"},
+		{name: "bad scope", rules: map[string]config.CustomRuleConfig{"c": {Pattern: "x", Scope: "all"}}, want: `gomarklint: custom rule "c": invalid scope "all" (valid scopes: text, code, heading, link-url)`},
+		{name: "options", rules: map[string]config.CustomRuleConfig{"c": {Pattern: "x"}}, options: map[string]interface{}{"pattern": "y"}, want: `gomarklint: unknown option "pattern" for c (valid options: none)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := allOff()
+			cfg.CustomRules = tt.rules
+			cfg.Plugins = tt.plugins
+			if tt.options != nil {
+				cfg.Rules["c"] = &config.RuleConfig{Enabled: true, Severity: config.SeverityError, Options: tt.options}
+			}
+			_, err := New(cfg)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("unexpected error:\ngot:  %v\nwant: %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintContent_Cache(t *testing.T) {
+	c, err := cache.Open(t.TempDir(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := allOff()
+	cfg.Rules["heading-level"] = &config.RuleConfig{Enabled: true, Severity: config.SeverityError, Options: map[string]interface{}{"minLevel": float64(2)}}
+	l := mustNew(t, cfg)
+	if err := l.UseCache(c); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "# Title\n\ntext\n"
+	first, _, _ := l.LintContent("doc.md", content)
+	if len(first) != 1 {
+		t.Fatalf("expected 1 finding, got %v", first)
+	}
+
+	// Replace the entry to show the second run reads it instead of
+	// running the rules.
+	fake := []rule.LintError{{File: "doc.md", Line: 3, Rule: "heading-level", Message: "cached", Severity: "error"}}
+	if err := c.Put(c.Key(l.configKey, "doc.md", content), fake); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := l.LintContent("doc.md", content); len(got) != 1 || got[0].Message != "cached" {
+		t.Errorf("expected the cached finding, got %v", got)
+	}
+
+	// A different path, content or config misses the entry.
+	if got, _, _ := l.LintContent("other.md", content); len(got) != 1 || got[0].Message == "cached" {
+		t.Errorf("expected a fresh result for another path, got %v", got)
+	}
+	if got, _, _ := l.LintContent("doc.md", content+"\n"); len(got) != 1 || got[0].Message == "cached" {
+		t.Errorf("expected a fresh result for changed content, got %v", got)
+	}
+	cfg.Rules["heading-level"].Severity = config.SeverityWarning
+	l2 := mustNew(t, cfg)
+	if err := l2.UseCache(c); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := l2.LintContent("doc.md", content); len(got) != 1 || got[0].Message == "cached" || got[0].Severity != "warning" {
+		t.Errorf("expected a fresh result for a changed config, got %v", got)
+	}
+}
+
+func TestLintContent_LinkCache(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	c, err := cache.Open(t.TempDir(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf("[ok](%s/ok)\n[broken](%s/broken)\n", ts.URL, ts.URL)
+
+	// lint runs a linter with the given failureCacheTTL, or the default if
+	// empty, over content and returns the number of findings and which URLs
+	// it requested.
+	lint := func(failureTTL string, refresh bool) (int, map[string]bool) {
+		t.Helper()
+		cfg := allOff()
+		cfg.Rules["external-link"] = &config.RuleConfig{
+			Enabled:  true,
+			Severity: config.SeverityError,
+			Options:  map[string]interface{}{"perHostIntervalMs": float64(0)},
+		}
+		if failureTTL != "" {
+			cfg.Rules["external-link"].Options["failureCacheTTL"] = failureTTL
+		}
+		l := mustNew(t, cfg)
+		l.UseLinkCache(c, refresh)
+		mu.Lock()
+		before := map[string]int{"/ok": hits["/ok"], "/broken": hits["/broken"]}
+		mu.Unlock()
+		errs, _, _ := l.LintContent("doc.md", content)
+		if err := l.SaveLinks(); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs), map[string]bool{"/ok": hits["/ok"] > before["/ok"], "/broken": hits["/broken"] > before["/broken"]}
+	}
+
+	n, requested := lint("1h", false)
+	if n != 1 || !requested["/ok"] || !requested["/broken"] {
+		t.Fatalf("first run: %d findings, requested %v", n, requested)
+	}
+	n, requested = lint("1h", false)
+	if n != 1 || requested["/ok"] || requested["/broken"] {
+		t.Errorf("expected both results from the cache: %d findings, requested %v", n, requested)
+	}
+	n, requested = lint("0", false)
+	if n != 1 || requested["/ok"] || !requested["/broken"] {
+		t.Errorf("expected only the failure to expire: %d findings, requested %v", n, requested)
+	}
+	n, requested = lint("1h", true)
+	if n != 1 || !requested["/ok"] || !requested["/broken"] {
+		t.Errorf("expected a refresh to request both: %d findings, requested %v", n, requested)
+	}
+
+	// By default failures are not saved, as the cache directory is shared
+	// by every project on the machine.
+	lint("", true)
+	links := c.Links()
+	if _, ok := links[ts.URL+"/broken"]; ok || len(links) != 1 {
+		t.Errorf("expected only the working link saved by default, got %v", links)
+	}
+}
+
+func TestNew_InvalidCacheTTL(t *testing.T) {
+	cfg := allOff()
+	cfg.Rules["external-link"] = &config.RuleConfig{Options: map[string]interface{}{"cacheTTL": "1 day"}}
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "must be a duration") {
+		t.Errorf("expected a duration error, got %v", err)
+	}
+}
+
+func TestLintContent_CacheSkipsPlugins(t *testing.T) {
+	c, err := cache.Open(t.TempDir(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "plugin.sh")
+	counter := filepath.Join(dir, "runs")
+	body := "#!/bin/sh\ncat >/dev/null\necho x >> '" + counter + "'\necho '{\"findings\":[]}'\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := allOff()
+	cfg.Plugins = []config.PluginConfig{{Name: "house-style", Command: []string{script}}}
+	l := mustNew(t, cfg)
+	if err := l.UseCache(c); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		l.LintContent("doc.md", "## Title\n")
+	}
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(runs), "x"); n != 2 {
+		t.Errorf("expected the plugin to run on both lints, ran %d times", n)
+	}
+}
@@ -0,0 +1,120 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// request is an incoming request or notification; ID is nil for notifications.
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   responseError   `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// conn reads and writes LSP base-protocol messages: a Content-Length header
+// block followed by a JSON body. Writes are serialized so responses and
+// notifications sent from different goroutines never interleave.
+type conn struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: bufio.NewReader(r), w: w}
+}
+
+// read returns the body of the next message.
+func (c *conn) read() ([]byte, error) {
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid Content-Length %q", value)
+		}
+		length = n
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (c *conn) write(v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+func (c *conn) reply(id json.RawMessage, result interface{}) error {
+	return c.write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (c *conn) replyError(id json.RawMessage, code int, message string) error {
+	return c.write(errorResponse{JSONRPC: "2.0", ID: id, Error: responseError{Code: code, Message: message}})
+}
+
+func (c *conn) notify(method string, params interface{}) error {
+	return c.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
@@ -0,0 +1,47 @@
+package lsp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConn_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	c := newConn(nil, &buf)
+	if err := c.notify("window/showMessage", showMessageParams{Type: 1, Message: "héllo"}); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"jsonrpc":"2.0","method":"window/showMessage","params":{"type":1,"message":"héllo"}}`
+	if !strings.HasPrefix(buf.String(), "Content-Length: 86\r\n\r\n") {
+		t.Errorf("unexpected header: %q", buf.String())
+	}
+
+	got, err := newConn(&buf, nil).read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("read = %s, want %s", got, want)
+	}
+}
+
+func TestConn_ReadHeaders(t *testing.T) {
+	in := "content-length: 2\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{}"
+	got, err := newConn(strings.NewReader(in), nil).read()
+	if err != nil || string(got) != "{}" {
+		t.Errorf("read = %q, %v", got, err)
+	}
+}
+
+func TestConn_ReadErrors(t *testing.T) {
+	for _, in := range []string{
+		"Content-Type: x\r\n\r\n{}",
+		"Content-Length: abc\r\n\r\n{}",
+		"Content-Length: 10\r\n\r\n{}",
+	} {
+		if _, err := newConn(strings.NewReader(in), nil).read(); err == nil {
+			t.Errorf("expected error for %q", in)
+		}
+	}
+}
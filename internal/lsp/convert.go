@@ -0,0 +1,154 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// splitDocument splits text into lines without their "\n"; a trailing "\r"
+// stays on the line, as in the linter.
+func splitDocument(text string) []string {
+	return strings.Split(text, "\n")
+}
+
+// lineEnding returns the line break to use for inserted text.
+func lineEnding(text string) string {
+	if strings.Contains(text, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// position converts a 1-based line and 1-based character column, as used by
+// LintError and rule.Edit, to an LSP position. Column 0 means the start of
+// the line. The character is clamped to the line's content, so a column past
+// a trailing "\r" lands before it.
+func position(lines []string, line, col int) Position {
+	if line < 1 {
+		return Position{}
+	}
+	if line > len(lines) {
+		last := len(lines) - 1
+		return Position{Line: last, Character: utf16Len(strings.TrimSuffix(lines[last], "\r"))}
+	}
+	text := strings.TrimSuffix(lines[line-1], "\r")
+	units := 0
+	for _, r := range text {
+		if col <= 1 {
+			break
+		}
+		units += utf16RuneLen(r)
+		col--
+	}
+	return Position{Line: line - 1, Character: units}
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// byteOffset converts an LSP position to a byte offset in text, clamping
+// positions past the end of a line or of the document.
+func byteOffset(text string, p Position) int {
+	off := 0
+	for line := 0; line < p.Line; line++ {
+		i := strings.IndexByte(text[off:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		off += i + 1
+	}
+	units := 0
+	for off < len(text) && text[off] != '\n' && units < p.Character {
+		r, size := utf8.DecodeRuneInString(text[off:])
+		units += utf16RuneLen(r)
+		off += size
+	}
+	return off
+}
+
+// errorRange returns the range a diagnostic covers. Errors that carry only a
+// line cover that line's content.
+func errorRange(lines []string, e rule.LintError) Range {
+	if e.Column == 0 {
+		start := position(lines, e.Line, 1)
+		end := start
+		if e.Line >= 1 && e.Line <= len(lines) {
+			end.Character = utf16Len(strings.TrimSuffix(lines[e.Line-1], "\r"))
+		}
+		return Range{Start: start, End: end}
+	}
+	endLine, endCol := e.EndLine, e.EndColumn
+	if endLine == 0 {
+		endLine, endCol = e.Line, e.Column
+	}
+	return Range{Start: position(lines, e.Line, e.Column), End: position(lines, endLine, endCol)}
+}
+
+func toDiagnostic(lines []string, e rule.LintError) Diagnostic {
+	sev := severityError
+	if e.Severity == string(config.SeverityWarning) {
+		sev = severityWarning
+	}
+	return Diagnostic{
+		Range:    errorRange(lines, e),
+		Severity: sev,
+		Code:     e.Rule,
+		Source:   "gomarklint",
+		Message:  e.Message,
+	}
+}
+
+func toTextEdits(lines []string, eol string, f *rule.Fix) []TextEdit {
+	edits := make([]TextEdit, 0, len(f.Edits))
+	for _, ed := range f.Edits {
+		edits = append(edits, TextEdit{
+			Range: Range{
+				Start: position(lines, ed.Line, ed.Column),
+				End:   position(lines, ed.EndLine, ed.EndColumn),
+			},
+			NewText: strings.ReplaceAll(ed.NewText, "\n", eol),
+		})
+	}
+	return edits
+}
+
+func overlaps(a, b Range) bool {
+	return !before(a.End, b.Start) && !before(b.End, a.Start)
+}
+
+func before(a, b Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// uriToPath converts a file:// URI to a local path. Other schemes (e.g. an
+// editor's untitled buffers) are returned unchanged so they still get a
+// stable name in diagnostics.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	p := u.Path
+	if runtime.GOOS == "windows" {
+		p = strings.TrimPrefix(p, "/")
+	}
+	return filepath.FromSlash(p)
+}
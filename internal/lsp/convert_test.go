@@ -0,0 +1,88 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func TestPosition(t *testing.T) {
+	lines := splitDocument("a😀b\r\nxyz")
+	tests := []struct {
+		name      string
+		line, col int
+		want      Position
+	}{
+		{"line start", 1, 1, Position{0, 0}},
+		{"column zero", 1, 0, Position{0, 0}},
+		{"after astral rune", 1, 3, Position{0, 3}},
+		{"end of line before CR", 1, 99, Position{0, 4}},
+		{"second line", 2, 3, Position{1, 2}},
+		{"past last line", 5, 1, Position{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := position(lines, tt.line, tt.col); got != tt.want {
+				t.Errorf("position(%d, %d) = %+v, want %+v", tt.line, tt.col, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteOffset(t *testing.T) {
+	text := "a😀b\nxyz"
+	tests := []struct {
+		pos  Position
+		want int
+	}{
+		{Position{0, 0}, 0},
+		{Position{0, 1}, 1},
+		{Position{0, 3}, 5},
+		{Position{0, 10}, 6},
+		{Position{1, 1}, 8},
+		{Position{4, 0}, len(text)},
+	}
+	for _, tt := range tests {
+		if got := byteOffset(text, tt.pos); got != tt.want {
+			t.Errorf("byteOffset(%+v) = %d, want %d", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestErrorRange_LineOnly(t *testing.T) {
+	lines := splitDocument("first\n  😀 second\n")
+	got := errorRange(lines, rule.LintError{Line: 2})
+	want := Range{Start: Position{1, 0}, End: Position{1, 11}}
+	if got != want {
+		t.Errorf("errorRange = %+v, want %+v", got, want)
+	}
+}
+
+func TestDisableNextLineEdit(t *testing.T) {
+	t.Run("inserts comment with indentation", func(t *testing.T) {
+		lines := splitDocument("text\n  - item\n")
+		got := disableNextLineEdit(lines, "\n", rule.LintError{Line: 2, Rule: "no-hard-tabs"})
+		want := TextEdit{Range: Range{Start: Position{1, 0}, End: Position{1, 0}}, NewText: "  <!-- gomarklint-disable-next-line no-hard-tabs -->\n"}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("extends existing comment", func(t *testing.T) {
+		lines := splitDocument("<!-- gomarklint-disable-next-line no-bare-urls -->\nhttp://x\n")
+		got := disableNextLineEdit(lines, "\n", rule.LintError{Line: 2, Rule: "no-hard-tabs"})
+		want := TextEdit{Range: Range{Start: Position{0, 46}, End: Position{0, 46}}, NewText: " no-hard-tabs"}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestURIToPath(t *testing.T) {
+	if got := uriToPath("file:///tmp/my%20docs/a.md"); got != "/tmp/my docs/a.md" {
+		t.Errorf("uriToPath = %q", got)
+	}
+	if got := uriToPath("untitled:Untitled-1"); got != "untitled:Untitled-1" {
+		t.Errorf("uriToPath = %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package lsp
+
+import "encoding/json"
+
+// The subset of the Language Server Protocol 3.17 types the server uses.
+
+// Position is a zero-based line and UTF-16 code unit offset.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+const (
+	severityError   = 1
+	severityWarning = 2
+)
+
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+type CodeAction struct {
+	Title       string        `json:"title"`
+	Kind        string        `json:"kind"`
+	Diagnostics []Diagnostic  `json:"diagnostics,omitempty"`
+	IsPreferred bool          `json:"isPreferred,omitempty"`
+	Edit        WorkspaceEdit `json:"edit"`
+}
+
+const (
+	codeActionQuickFix = "quickfix"
+	codeActionFixAll   = "source.fixAll.gomarklint"
+)
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type initializeParams struct {
+	RootURI               string          `json:"rootUri"`
+	RootPath              string          `json:"rootPath"`
+	InitializationOptions json.RawMessage `json:"initializationOptions"`
+}
+
+// initializationOptions are the gomarklint-specific settings a client may
+// send in the initialize request.
+type initializationOptions struct {
+	// ExternalLink enables the external-link rule, which is off in the
+	// server by default because each check waits on the network.
+	ExternalLink bool `json:"externalLink"`
+}
+
+type didOpenParams struct {
+	TextDocument struct {
+		URI     string `json:"uri"`
+		Version int    `json:"version"`
+		Text    string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument struct {
+		URI     string `json:"uri"`
+		Version int    `json:"version"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Range *Range `json:"range"`
+		Text  string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type codeActionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     int          `json:"version"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type showMessageParams struct {
+	Type    int    `json:"type"`
+	Message string `json:"message"`
+}
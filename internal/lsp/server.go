@@ -0,0 +1,434 @@
+// Package lsp implements a Language Server Protocol server over stdio that
+// lints open Markdown buffers and offers fixes as code actions.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/linter"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// DefaultDebounce is how long the server waits after the last change to a
+// document before linting it again.
+const DefaultDebounce = 300 * time.Millisecond
+
+// Options configures a Server.
+type Options struct {
+	// ConfigPath is the config file to load. When empty, the server uses
+	// .gomarklint.json in the workspace root sent by the client.
+	ConfigPath string
+	Debounce   time.Duration
+	Version    string
+}
+
+// document is the server's copy of one open buffer.
+type document struct {
+	path    string
+	text    string
+	version int
+	timer   *time.Timer
+}
+
+// Server holds per-document state and the linters built from the workspace
+// config. All fields below mu are guarded by it.
+type Server struct {
+	conn *conn
+	opts Options
+
+	mu       sync.Mutex
+	docs     map[string]*document
+	root     string
+	cfg      config.Config
+	linter   *linter.Linter // diagnostics; checks external links only when opted in
+	local    *linter.Linter // fixes and code actions; never checks external links
+	shutdown bool
+}
+
+// NewServer returns a server that reads requests from r and writes to w.
+func NewServer(r io.Reader, w io.Writer, opts Options) *Server {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	s := &Server{
+		conn: newConn(r, w),
+		opts: opts,
+		docs: map[string]*document{},
+	}
+	s.configure("", false)
+	return s
+}
+
+// Run serves requests until the client sends exit or closes the stream. It
+// returns nil when exit follows a shutdown request, as the protocol requires
+// for a zero exit status.
+func (s *Server) Run() error {
+	defer s.stopTimers()
+	for {
+		body, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) && s.isShutdown() {
+				return nil
+			}
+			return fmt.Errorf("lsp: %w", err)
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			_ = s.conn.replyError(json.RawMessage("null"), codeParseError, err.Error())
+			continue
+		}
+		if req.Method == "exit" {
+			if s.isShutdown() {
+				return nil
+			}
+			return errors.New("lsp: exit received before shutdown")
+		}
+		s.handle(&req)
+	}
+}
+
+func (s *Server) handle(req *request) {
+	var id json.RawMessage
+	if req.ID != nil {
+		id = *req.ID
+	}
+	var err error
+	switch req.Method {
+	case "initialize":
+		err = s.initialize(id, req.Params)
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		s.mu.Unlock()
+		err = s.conn.reply(id, nil)
+	case "textDocument/didOpen":
+		err = s.didOpen(req.Params)
+	case "textDocument/didChange":
+		err = s.didChange(req.Params)
+	case "textDocument/didClose":
+		err = s.didClose(req.Params)
+	case "textDocument/codeAction":
+		var p codeActionParams
+		if err = json.Unmarshal(req.Params, &p); err == nil {
+			err = s.conn.reply(id, s.codeActions(p))
+		}
+	default:
+		if req.ID != nil {
+			err = s.conn.replyError(id, codeMethodNotFound, "method not supported: "+req.Method)
+		}
+	}
+	if err != nil && req.ID != nil {
+		_ = s.conn.replyError(id, codeInvalidParams, err.Error())
+	}
+}
+
+func (s *Server) initialize(id json.RawMessage, params json.RawMessage) error {
+	var p initializeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	var opts initializationOptions
+	if len(p.InitializationOptions) > 0 && string(p.InitializationOptions) != "null" {
+		if err := json.Unmarshal(p.InitializationOptions, &opts); err != nil {
+			return fmt.Errorf("invalid initializationOptions: %w", err)
+		}
+	}
+	root := p.RootPath
+	if p.RootURI != "" {
+		root = uriToPath(p.RootURI)
+	}
+	s.configure(root, opts.ExternalLink)
+
+	return s.conn.reply(id, map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    2, // incremental
+			},
+			"codeActionProvider": map[string]interface{}{
+				"codeActionKinds": []string{codeActionQuickFix, codeActionFixAll},
+			},
+		},
+		"serverInfo": map[string]string{"name": "gomarklint", "version": s.opts.Version},
+	})
+}
+
+// configure loads the config for the workspace at root and builds the
+// linters. A broken config is reported to the client and replaced by the
+// defaults so the editor keeps getting diagnostics.
+func (s *Server) configure(root string, externalLink bool) {
+	path := s.opts.ConfigPath
+	if path == "" {
+		path = filepath.Join(root, ".gomarklint.json")
+	}
+	cfg, err := config.LoadOrDefault(path)
+	if err == nil {
+		err = config.Validate(cfg)
+	}
+	var lint, local *linter.Linter
+	if err == nil {
+		if !externalLink {
+			cfg = withoutExternalLink(cfg)
+		}
+		if lint, err = linter.New(cfg); err == nil {
+			local, err = linter.New(withoutExternalLink(cfg))
+		}
+	}
+	if err != nil {
+		_ = s.conn.notify("window/showMessage", showMessageParams{
+			Type:    1,
+			Message: fmt.Sprintf("gomarklint: %v; using the default config", err),
+		})
+		cfg = withoutExternalLink(config.Default())
+		lint, _ = linter.New(cfg)
+		local = lint
+	}
+
+	s.mu.Lock()
+	s.root, s.cfg, s.linter, s.local = root, cfg, lint, local
+	s.mu.Unlock()
+}
+
+// withoutExternalLink returns a copy of cfg with the external-link rule off.
+func withoutExternalLink(cfg config.Config) config.Config {
+	rules := make(map[string]*config.RuleConfig, len(cfg.Rules)+1)
+	for k, v := range cfg.Rules {
+		rules[k] = v
+	}
+	rules["external-link"] = &config.RuleConfig{Enabled: false, Severity: config.SeverityOff, Options: map[string]interface{}{}}
+	cfg.Rules = rules
+	return cfg
+}
+
+func (s *Server) didOpen(params json.RawMessage) error {
+	var p didOpenParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	uri := p.TextDocument.URI
+	s.mu.Lock()
+	if old, ok := s.docs[uri]; ok && old.timer != nil {
+		old.timer.Stop()
+	}
+	s.docs[uri] = &document{path: uriToPath(uri), text: p.TextDocument.Text, version: p.TextDocument.Version}
+	s.mu.Unlock()
+	go s.lint(uri)
+	return nil
+}
+
+func (s *Server) didChange(params json.RawMessage) error {
+	var p didChangeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	uri := p.TextDocument.URI
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, ok := s.docs[uri]
+	if !ok {
+		return nil
+	}
+	for _, c := range p.ContentChanges {
+		if c.Range == nil {
+			doc.text = c.Text
+			continue
+		}
+		start, end := byteOffset(doc.text, c.Range.Start), byteOffset(doc.text, c.Range.End)
+		if end < start {
+			start, end = end, start
+		}
+		doc.text = doc.text[:start] + c.Text + doc.text[end:]
+	}
+	doc.version = p.TextDocument.Version
+	if doc.timer != nil {
+		doc.timer.Stop()
+	}
+	doc.timer = time.AfterFunc(s.opts.Debounce, func() { s.lint(uri) })
+	return nil
+}
+
+func (s *Server) didClose(params json.RawMessage) error {
+	var p didCloseParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	uri := p.TextDocument.URI
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := s.docs[uri]; ok && doc.timer != nil {
+		doc.timer.Stop()
+	}
+	delete(s.docs, uri)
+	return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: []Diagnostic{}})
+}
+
+// lint lints the current text of uri and publishes the diagnostics, unless
+// the document changed or closed while the lint was running.
+func (s *Server) lint(uri string) {
+	s.mu.Lock()
+	doc, ok := s.docs[uri]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	path, text, version := doc.path, doc.text, doc.version
+	lint, cfg, root := s.linter, s.cfg, s.root
+	s.mu.Unlock()
+
+	diags := []Diagnostic{}
+	if !isIgnored(cfg, root, path) {
+		errs, _, _ := lint.LintContent(path, text)
+		lines := splitDocument(text)
+		for _, e := range visible(cfg, errs) {
+			diags = append(diags, toDiagnostic(lines, e))
+		}
+	}
+
+	// Publishing under mu orders it against newer lints of the same document.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur, ok := s.docs[uri]; !ok || cur.version != version || cur.text != text {
+		return
+	}
+	_ = s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Version: version, Diagnostics: diags})
+}
+
+// codeActions offers, for each violation in the requested range, its fix
+// and a comment that disables the rule for that line, plus one action that
+// applies every fix in the document.
+func (s *Server) codeActions(p codeActionParams) []CodeAction {
+	uri := p.TextDocument.URI
+	s.mu.Lock()
+	doc, ok := s.docs[uri]
+	if !ok {
+		s.mu.Unlock()
+		return []CodeAction{}
+	}
+	path, text := doc.path, doc.text
+	local, cfg, root := s.local, s.cfg, s.root
+	s.mu.Unlock()
+
+	actions := []CodeAction{}
+	if isIgnored(cfg, root, path) {
+		return actions
+	}
+	errs, _, _ := local.LintContent(path, text)
+	lines := splitDocument(text)
+	eol := lineEnding(text)
+	disabled := map[string]bool{}
+	for _, e := range visible(cfg, errs) {
+		diag := toDiagnostic(lines, e)
+		if !overlaps(diag.Range, p.Range) {
+			continue
+		}
+		if e.Fix != nil {
+			actions = append(actions, CodeAction{
+				Title:       "Fix: " + e.Message,
+				Kind:        codeActionQuickFix,
+				Diagnostics: []Diagnostic{diag},
+				IsPreferred: true,
+				Edit:        WorkspaceEdit{Changes: map[string][]TextEdit{uri: toTextEdits(lines, eol, e.Fix)}},
+			})
+		}
+		key := fmt.Sprintf("%d/%s", e.Line, e.Rule)
+		if e.Line >= 1 && e.Line <= len(lines) && !disabled[key] {
+			disabled[key] = true
+			actions = append(actions, CodeAction{
+				Title:       "Disable " + e.Rule + " for this line",
+				Kind:        codeActionQuickFix,
+				Diagnostics: []Diagnostic{diag},
+				Edit:        WorkspaceEdit{Changes: map[string][]TextEdit{uri: {disableNextLineEdit(lines, eol, e)}}},
+			})
+		}
+	}
+
+	if fixed, n := local.FixContent(path, text); n > 0 {
+		last := len(lines) - 1
+		actions = append(actions, CodeAction{
+			Title: "Fix all auto-fixable gomarklint issues",
+			Kind:  codeActionFixAll,
+			Edit: WorkspaceEdit{Changes: map[string][]TextEdit{uri: {{
+				Range:   Range{End: Position{Line: last, Character: utf16Len(lines[last])}},
+				NewText: fixed,
+			}}}},
+		})
+	}
+	return actions
+}
+
+// disableNextLineEdit silences e's rule on its line. When the line above is
+// already a gomarklint-disable-next-line comment naming other rules, the rule
+// is added to it; a second comment would leave the first one applying to the
+// new comment line instead.
+func disableNextLineEdit(lines []string, eol string, e rule.LintError) TextEdit {
+	if e.Line >= 2 {
+		prev := lines[e.Line-2]
+		if i := strings.Index(prev, "-->"); i >= 0 && strings.Contains(prev[:i], "gomarklint-disable-next-line ") {
+			insertAt := strings.TrimRight(prev[:i], " ")
+			pos := position(lines, e.Line-1, utf8.RuneCountInString(insertAt)+1)
+			return TextEdit{Range: Range{Start: pos, End: pos}, NewText: " " + e.Rule}
+		}
+	}
+	line := lines[e.Line-1]
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	pos := Position{Line: e.Line - 1}
+	return TextEdit{
+		Range:   Range{Start: pos, End: pos},
+		NewText: indent + "<!-- gomarklint-disable-next-line " + e.Rule + " -->" + eol,
+	}
+}
+
+// visible drops warnings when the config only reports errors.
+func visible(cfg config.Config, errs []rule.LintError) []rule.LintError {
+	if cfg.MinSeverity != config.SeverityError {
+		return errs
+	}
+	kept := errs[:0:0]
+	for _, e := range errs {
+		if e.Severity != string(config.SeverityWarning) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// isIgnored applies the config's ignore patterns to path, relative to the
+// workspace root when there is one, as the CLI does for paths it expands.
+func isIgnored(cfg config.Config, root, path string) bool {
+	if len(cfg.Ignore) == 0 {
+		return false
+	}
+	rel := path
+	if root != "" {
+		if r, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	return file.ShouldIgnore(filepath.ToSlash(rel), cfg.Ignore)
+}
+
+func (s *Server) isShutdown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shutdown
+}
+
+func (s *Server) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range s.docs {
+		if doc.timer != nil {
+			doc.timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDebounce = 20 * time.Millisecond
+
+// incoming is a message the server sent to the client.
+type incoming struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params"`
+	Result json.RawMessage  `json:"result"`
+	Error  *responseError   `json:"error"`
+}
+
+type testClient struct {
+	t      *testing.T
+	w      *io.PipeWriter
+	msgs   chan incoming
+	done   chan error
+	nextID int
+}
+
+// startServer runs a server over in-memory pipes. Unless configPath is set,
+// it loads no config file so tests see the defaults.
+func startServer(t *testing.T, configPath string) *testClient {
+	t.Helper()
+	if configPath == "" {
+		configPath = "/nonexistent/.gomarklint.json"
+	}
+	return startServerWith(t, Options{ConfigPath: configPath, Debounce: testDebounce, Version: "test"})
+}
+
+func startServerWith(t *testing.T, opts Options) *testClient {
+	t.Helper()
+	c2sR, c2sW := io.Pipe()
+	s2cR, s2cW := io.Pipe()
+	c := &testClient{t: t, w: c2sW, msgs: make(chan incoming, 100), done: make(chan error, 1)}
+
+	// The reader starts first: NewServer may already report a broken config.
+	go func() {
+		in := newConn(s2cR, nil)
+		for {
+			body, err := in.read()
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			var m incoming
+			if err := json.Unmarshal(body, &m); err != nil {
+				t.Errorf("server sent invalid JSON: %s", body)
+				continue
+			}
+			c.msgs <- m
+		}
+	}()
+	srv := NewServer(c2sR, s2cW, opts)
+	go func() {
+		c.done <- srv.Run()
+		_ = s2cW.Close()
+	}()
+	t.Cleanup(func() {
+		_ = c2sW.Close()
+		_ = s2cR.Close()
+	})
+	return c
+}
+
+func (c *testClient) send(v interface{}) {
+	c.t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// call sends a request and returns its response, failing on an error reply.
+func (c *testClient) call(method string, params interface{}, result interface{}) {
+	c.t.Helper()
+	c.nextID++
+	id := c.nextID
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	for {
+		m := c.next()
+		if m.ID == nil || string(*m.ID) != strconv.Itoa(id) {
+			continue
+		}
+		if m.Error != nil {
+			c.t.Fatalf("%s failed: %+v", method, m.Error)
+		}
+		if result != nil {
+			if err := json.Unmarshal(m.Result, result); err != nil {
+				c.t.Fatalf("decoding %s result: %v", method, err)
+			}
+		}
+		return
+	}
+}
+
+func (c *testClient) next() incoming {
+	c.t.Helper()
+	select {
+	case m, ok := <-c.msgs:
+		if !ok {
+			c.t.Fatal("server closed the connection")
+		}
+		return m
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timed out waiting for a server message")
+	}
+	return incoming{}
+}
+
+func (c *testClient) diagnostics() publishDiagnosticsParams {
+	c.t.Helper()
+	for {
+		m := c.next()
+		if m.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var p publishDiagnosticsParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			c.t.Fatal(err)
+		}
+		return p
+	}
+}
+
+// expectQuiet fails if the server publishes anything within d.
+func (c *testClient) expectQuiet(d time.Duration) {
+	c.t.Helper()
+	select {
+	case m := <-c.msgs:
+		c.t.Errorf("unexpected message: %s %s", m.Method, m.Params)
+	case <-time.After(d):
+	}
+}
+
+func (c *testClient) initialize(options interface{}) {
+	c.t.Helper()
+	c.call("initialize", map[string]interface{}{"rootUri": nil, "initializationOptions": options}, nil)
+	c.notify("initialized", map[string]interface{}{})
+}
+
+func (c *testClient) open(uri, text string) {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "markdown", "version": 1, "text": text},
+	})
+}
+
+func hasCode(diags []Diagnostic, code string) bool {
+	for _, d := range diags {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestServer_Initialize(t *testing.T) {
+	c := startServer(t, "")
+	var result struct {
+		Capabilities struct {
+			TextDocumentSync struct {
+				OpenClose bool `json:"openClose"`
+				Change    int  `json:"change"`
+			} `json:"textDocumentSync"`
+			CodeActionProvider struct {
+				CodeActionKinds []string `json:"codeActionKinds"`
+			} `json:"codeActionProvider"`
+		} `json:"capabilities"`
+		ServerInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	c.call("initialize", map[string]interface{}{}, &result)
+
+	if !result.Capabilities.TextDocumentSync.OpenClose || result.Capabilities.TextDocumentSync.Change != 2 {
+		t.Errorf("unexpected textDocumentSync: %+v", result.Capabilities.TextDocumentSync)
+	}
+	if len(result.Capabilities.CodeActionProvider.CodeActionKinds) != 2 {
+		t.Errorf("unexpected codeActionKinds: %v", result.Capabilities.CodeActionProvider.CodeActionKinds)
+	}
+	if result.ServerInfo.Name != "gomarklint" || result.ServerInfo.Version != "test" {
+		t.Errorf("unexpected serverInfo: %+v", result.ServerInfo)
+	}
+}
+
+func TestServer_DidOpenPublishesDiagnostics(t *testing.T) {
+	c := startServer(t, "")
+	c.initialize(nil)
+	c.open("file:///tmp/doc.md", "# Title\n\nSee 😀 https://example.com\n")
+
+	p := c.diagnostics()
+	if p.URI != "file:///tmp/doc.md" || p.Version != 1 {
+		t.Errorf("unexpected target: %s v%d", p.URI, p.Version)
+	}
+	var bare *Diagnostic
+	for i := range p.Diagnostics {
+		if p.Diagnostics[i].Code == "no-bare-urls" {
+			bare = &p.Diagnostics[i]
+		}
+	}
+	if bare == nil {
+		t.Fatalf("expected a no-bare-urls diagnostic, got %+v", p.Diagnostics)
+	}
+	// The emoji is two UTF-16 code units, so the URL starts at character 7.
+	want := Range{Start: Position{Line: 2, Character: 7}, End: Position{Line: 2, Character: 26}}
+	if bare.Range != want || bare.Severity != severityError || bare.Source != "gomarklint" {
+		t.Errorf("unexpected diagnostic: %+v", *bare)
+	}
+	if !hasCode(p.Diagnostics, "heading-level") {
+		t.Errorf("expected heading-level diagnostic, got %+v", p.Diagnostics)
+	}
+}
+
+func TestServer_DidChangeIsDebounced(t *testing.T) {
+	c := startServer(t, "")
+	c.initialize(nil)
+	uri := "file:///tmp/doc.md"
+	c.open(uri, "## Title\n")
+	if p := c.diagnostics(); len(p.Diagnostics) != 0 {
+		t.Fatalf("expected clean document, got %+v", p.Diagnostics)
+	}
+
+	for v, text := range []string{"## Title\ntext", "## Title\ntext\n", "## Title\n\n\n\ntext\n"} {
+		c.notify("textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": uri, "version": v + 2},
+			"contentChanges": []map[string]interface{}{{"text": text}},
+		})
+	}
+
+	p := c.diagnostics()
+	if p.Version != 4 {
+		t.Errorf("expected only the last version to be linted, got version %d", p.Version)
+	}
+	if !hasCode(p.Diagnostics, "no-multiple-blank-lines") {
+		t.Errorf("expected diagnostics for the final text, got %+v", p.Diagnostics)
+	}
+	c.expectQuiet(5 * testDebounce)
+}
+
+func TestServer_IncrementalChange(t *testing.T) {
+	c := startServer(t, "")
+	c.initialize(nil)
+	uri := "file:///tmp/doc.md"
+	c.open(uri, "## Title\n\n😀 text\n")
+	c.diagnostics()
+
+	// Replace "text" (after a two-unit emoji and a space) with a bare URL.
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]interface{}{{
+			"range": Range{Start: Position{Line: 2, Character: 3}, End: Position{Line: 2, Character: 7}},
+			"text":  "https://example.com",
+		}},
+	})
+	p := c.diagnostics()
+	if len(p.Diagnostics) != 1 || p.Diagnostics[0].Code != "no-bare-urls" {
+		t.Fatalf("expected one no-bare-urls diagnostic, got %+v", p.Diagnostics)
+	}
+	if p.Diagnostics[0].Range.Start != (Position{Line: 2, Character: 3}) {
+		t.Errorf("unexpected range: %+v", p.Diagnostics[0].Range)
+	}
+}
+
+func TestServer_CodeActions(t *testing.T) {
+	c := startServer(t, "")
+	c.initialize(nil)
+	uri := "file:///tmp/doc.md"
+	c.open(uri, "## Title\ntext\n")
+	c.diagnostics()
+
+	var actions []CodeAction
+	c.call("textDocument/codeAction", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"range":        Range{Start: Position{Line: 0}, End: Position{Line: 0, Character: 3}},
+		"context":      map[string]interface{}{"diagnostics": []Diagnostic{}},
+	}, &actions)
+
+	byTitle := map[string]CodeAction{}
+	for _, a := range actions {
+		byTitle[a.Title] = a
+	}
+
+	fix, ok := byTitle["Fix: blanks-around-headings: heading must be followed by a blank line"]
+	if !ok {
+		t.Fatalf("expected a fix action, got %+v", actions)
+	}
+	want := []TextEdit{{Range: Range{Start: Position{Line: 1}, End: Position{Line: 1}}, NewText: "\n"}}
+	if got := fix.Edit.Changes[uri]; len(got) != 1 || got[0] != want[0] || fix.Kind != codeActionQuickFix {
+		t.Errorf("unexpected fix action: %+v", fix)
+	}
+
+	disable, ok := byTitle["Disable blanks-around-headings for this line"]
+	if !ok {
+		t.Fatalf("expected a disable action, got %+v", actions)
+	}
+	if got := disable.Edit.Changes[uri]; len(got) != 1 || got[0].NewText != "<!-- gomarklint-disable-next-line blanks-around-headings -->\n" || got[0].Range.Start != (Position{}) {
+		t.Errorf("unexpected disable action: %+v", disable)
+	}
+
+	all, ok := byTitle["Fix all auto-fixable gomarklint issues"]
+	if !ok || all.Kind != codeActionFixAll {
+		t.Fatalf("expected a fix-all action, got %+v", actions)
+	}
+	if got := all.Edit.Changes[uri]; len(got) != 1 || got[0].NewText != "## Title\n\ntext\n" {
+		t.Errorf("unexpected fix-all edit: %+v", got)
+	}
+}
+
+func TestServer_DidCloseClearsDiagnostics(t *testing.T) {
+	c := startServer(t, "")
+	c.initialize(nil)
+	uri := "file:///tmp/doc.md"
+	c.open(uri, "# Title")
+	if p := c.diagnostics(); len(p.Diagnostics) == 0 {
+		t.Fatal("expected diagnostics")
+	}
+
+	c.notify("textDocument/didClose", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	if p := c.diagnostics(); p.URI != uri || len(p.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics cleared, got %+v", p)
+	}
+}
+
+func TestServer_UsesWorkspaceConfigAndIgnore(t *testing.T) {
+	root := t.TempDir()
+	cfg := `{"default": true, "rules": {"heading-level": false}, "ignore": ["vendor/**"]}`
+	if err := os.WriteFile(filepath.Join(root, ".gomarklint.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := startServerWith(t, Options{Debounce: testDebounce})
+
+	c.call("initialize", map[string]interface{}{"rootUri": "file://" + filepath.ToSlash(root)}, nil)
+
+	c.open("file://"+filepath.ToSlash(filepath.Join(root, "a.md")), "# Title\n")
+	if p := c.diagnostics(); hasCode(p.Diagnostics, "heading-level") {
+		t.Errorf("expected heading-level disabled by workspace config, got %+v", p.Diagnostics)
+	}
+
+	c.open("file://"+filepath.ToSlash(filepath.Join(root, "vendor", "b.md")), "text")
+	if p := c.diagnostics(); len(p.Diagnostics) != 0 {
+		t.Errorf("expected ignored file to have no diagnostics, got %+v", p.Diagnostics)
+	}
+}
+
+func TestServer_ExternalLinkIsOptIn(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	cfgPath := filepath.Join(t.TempDir(), "cfg.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"default": false, "rules": {"external-link": {"enabled": true, "maxRetries": 0}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	text := "[x](" + ts.URL + "/missing)\n"
+
+	off := startServer(t, cfgPath)
+	off.initialize(nil)
+	off.open("file:///tmp/doc.md", text)
+	if p := off.diagnostics(); len(p.Diagnostics) != 0 {
+		t.Errorf("expected no external-link checks without opt-in, got %+v", p.Diagnostics)
+	}
+
+	on := startServer(t, cfgPath)
+	on.initialize(map[string]interface{}{"externalLink": true})
+	on.open("file:///tmp/doc.md", text)
+	if p := on.diagnostics(); !hasCode(p.Diagnostics, "external-link") {
+		t.Errorf("expected external-link diagnostic with opt-in, got %+v", p.Diagnostics)
+	}
+}
+
+func TestServer_InvalidConfigFallsBackToDefaults(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "cfg.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"rules": {"unknown": 1`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := startServer(t, cfgPath)
+	m := c.next()
+	if m.Method != "window/showMessage" || !strings.Contains(string(m.Params), "using the default config") {
+		t.Errorf("expected a showMessage about the config, got %s %s", m.Method, m.Params)
+	}
+	c.initialize(nil)
+	c.open("file:///tmp/doc.md", "# Title\n")
+	if p := c.diagnostics(); !hasCode(p.Diagnostics, "heading-level") {
+		t.Errorf("expected default rules, got %+v", p.Diagnostics)
+	}
+}
+
+func TestServer_UnknownRequest(t *testing.T) {
+	c := startServer(t, "")
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": 7, "method": "textDocument/hover", "params": map[string]interface{}{}})
+	m := c.next()
+	if m.Error == nil || m.Error.Code != codeMethodNotFound {
+		t.Errorf("expected MethodNotFound, got %+v", m)
+	}
+}
+
+func TestServer_ShutdownAndExit(t *testing.T) {
+	c := startServer(t, "")
+	c.initialize(nil)
+	c.call("shutdown", nil, nil)
+	c.notify("exit", nil)
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("expected clean exit, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	c := startServer(t, "")
+	c.notify("exit", nil)
+	select {
+	case err := <-c.done:
+		if err == nil {
+			t.Error("expected an error for exit without shutdown")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"fmt"
 	"io"
 	"time"
 
@@ -12,12 +13,26 @@ type Formatter interface {
 }
 
 type Result struct {
-	Files        int
-	Lines        int
-	Total        int
-	Warnings     int
+	Files    int
+	Lines    int
+	Total    int
+	Warnings int
+	Fixed    int
+	// Suppressed counts violations left out because a baseline lists them;
+	// Stale counts baseline entries that no longer match any violation.
+	Suppressed   int
+	Stale        int
 	LinksChecked *int
 	Duration     time.Duration
 	Details      map[string][]rule.LintError
 	OrderedPaths []string
+	FailedFiles  map[string]error
+}
+
+// location renders e as file:line, or file:line:col when the rule reported a column.
+func location(e rule.LintError) string {
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	}
+	return fmt.Sprintf("%s:%d", e.File, e.Line)
 }
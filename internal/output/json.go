@@ -19,16 +19,22 @@ func (f *JSONFormatter) Format(w io.Writer, result *Result) error {
 		Lines        int                         `json:"lines"`
 		Total        int                         `json:"total"`
 		Warnings     int                         `json:"warnings"`
+		Fixed        int                         `json:"fixed,omitempty"`
+		Suppressed   int                         `json:"suppressed,omitempty"`
+		Stale        int                         `json:"stale_baseline_entries,omitempty"`
 		LinksChecked *int                        `json:"links_checked,omitempty"`
 		ElapsedMS    int64                       `json:"elapsed_ms"`
 		Details      map[string][]rule.LintError `json:"details"`
 	}{
-		Files:     result.Files,
-		Lines:     result.Lines,
-		Total:     result.Total,
-		Warnings:  result.Warnings,
-		ElapsedMS: result.Duration.Milliseconds(),
-		Details:   result.Details,
+		Files:      result.Files,
+		Lines:      result.Lines,
+		Total:      result.Total,
+		Warnings:   result.Warnings,
+		Fixed:      result.Fixed,
+		Suppressed: result.Suppressed,
+		Stale:      result.Stale,
+		ElapsedMS:  result.Duration.Milliseconds(),
+		Details:    result.Details,
 	}
 
 	if result.LinksChecked != nil {
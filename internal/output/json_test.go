@@ -163,3 +163,80 @@ func assertValidJSON(t *testing.T, data []byte) {
 		}
 	}
 }
+
+func TestJSONFormatter_Positions(t *testing.T) {
+	formatter := NewJSONFormatter()
+	result := &Result{
+		Total: 2,
+		Details: map[string][]rule.LintError{
+			"a.md": {
+				{File: "a.md", Line: 3, Column: 7, EndLine: 3, EndColumn: 12, Message: "with column"},
+				{File: "a.md", Line: 9, Message: "line only"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Details map[string][]map[string]interface{} `json:"details"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	withCol := decoded.Details["a.md"][0]
+	if withCol["column"] != float64(7) || withCol["end_line"] != float64(3) || withCol["end_column"] != float64(12) {
+		t.Errorf("expected column, end_line and end_column, got: %v", withCol)
+	}
+	if _, ok := decoded.Details["a.md"][1]["column"]; ok {
+		t.Errorf("expected column omitted when unset, got: %v", decoded.Details["a.md"][1])
+	}
+}
+
+func TestJSONFormatter_FixedAndEdits(t *testing.T) {
+	formatter := NewJSONFormatter()
+	result := &Result{
+		Total: 1,
+		Fixed: 3,
+		Details: map[string][]rule.LintError{
+			"a.md": {{
+				File: "a.md", Line: 2, Message: "fixable",
+				Fix: &rule.Fix{Edits: []rule.Edit{{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "\n"}}},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"fixed": 3`) {
+		t.Errorf("expected fixed count, got: %s", out)
+	}
+	if !strings.Contains(out, `"new_text": "\n"`) {
+		t.Errorf("expected fix edits, got: %s", out)
+	}
+}
+
+func TestJSONFormatter_Baseline(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewJSONFormatter().Format(&buf, &Result{Suppressed: 5, Stale: 2, Details: map[string][]rule.LintError{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"suppressed": 5`) || !strings.Contains(out, `"stale_baseline_entries": 2`) {
+		t.Errorf("expected baseline counts, got: %s", out)
+	}
+
+	buf.Reset()
+	if err := NewJSONFormatter().Format(&buf, &Result{Details: map[string][]rule.LintError{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "suppressed") {
+		t.Errorf("expected no baseline counts without a baseline, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// JUnitFormatter writes one <testsuite> per linted file and one <testcase>
+// per enabled rule, for CI systems that only render test reports.
+type JUnitFormatter struct {
+	rules []string
+}
+
+// NewJUnitFormatter returns a formatter that emits a test case for each of ruleNames.
+func NewJUnitFormatter(ruleNames []string) *JUnitFormatter {
+	return &JUnitFormatter{rules: ruleNames}
+}
+
+func (f *JUnitFormatter) Format(w io.Writer, result *Result) error {
+	root := junitTestSuites{
+		Name: "gomarklint",
+		Time: fmt.Sprintf("%.3f", result.Duration.Seconds()),
+	}
+
+	for _, path := range result.OrderedPaths {
+		suite := f.fileSuite(path, result.Details[path])
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Suites = append(root.Suites, suite)
+	}
+
+	for _, path := range sortedKeys(result.FailedFiles) {
+		suite := junitTestSuite{
+			Name:   path,
+			Tests:  1,
+			Errors: 1,
+			TestCases: []junitTestCase{{
+				Name:      "read",
+				ClassName: path,
+				Error: &junitFailure{
+					Message: "failed to read file",
+					Type:    "error",
+					Body:    result.FailedFiles[path].Error(),
+				},
+			}},
+		}
+		root.Tests++
+		root.Errors++
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func (f *JUnitFormatter) fileSuite(path string, errs []rule.LintError) junitTestSuite {
+	byRule := make(map[string][]rule.LintError)
+	for _, e := range errs {
+		byRule[e.Rule] = append(byRule[e.Rule], e)
+	}
+
+	suite := junitTestSuite{Name: path}
+	for _, name := range f.rules {
+		tc := junitTestCase{Name: name, ClassName: path}
+		if ruleErrs := byRule[name]; len(ruleErrs) > 0 {
+			tc.Failure = newJUnitFailure(ruleErrs)
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+		delete(byRule, name)
+	}
+	// Errors from rules not in the list still get a failing test case, so
+	// the report counts every violation the text output shows.
+	unlisted := make([]string, 0, len(byRule))
+	for name := range byRule {
+		unlisted = append(unlisted, name)
+	}
+	sort.Strings(unlisted)
+	for _, name := range unlisted {
+		suite.TestCases = append(suite.TestCases, junitTestCase{Name: name, ClassName: path, Failure: newJUnitFailure(byRule[name])})
+		suite.Failures++
+	}
+	suite.Tests = len(suite.TestCases)
+	return suite
+}
+
+func newJUnitFailure(errs []rule.LintError) *junitFailure {
+	var body strings.Builder
+	failType := string(config.SeverityWarning)
+	for _, e := range errs {
+		if e.Severity != string(config.SeverityWarning) {
+			failType = string(config.SeverityError)
+		}
+		fmt.Fprintf(&body, "%s: %s\n", location(e), e.Message)
+	}
+	word := "violations"
+	if len(errs) == 1 {
+		word = "violation"
+	}
+	return &junitFailure{
+		Message: fmt.Sprintf("%d %s", len(errs), word),
+		Type:    failType,
+		Body:    body.String(),
+	}
+}
+
+func sortedKeys(m map[string]error) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
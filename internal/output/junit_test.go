@@ -0,0 +1,162 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func decodeJUnit(t *testing.T, data []byte) junitTestSuites {
+	t.Helper()
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("output is not valid JUnit XML: %v\n%s", err, data)
+	}
+	return suites
+}
+
+func TestJUnitFormatter_NoErrors(t *testing.T) {
+	formatter := NewJUnitFormatter([]string{"final-blank-line", "single-h1"})
+	result := &Result{
+		Duration:     1500 * time.Millisecond,
+		Details:      map[string][]rule.LintError{},
+		OrderedPaths: []string{"a.md", "b.md"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml") {
+		t.Errorf("expected XML header, got: %s", buf.String())
+	}
+
+	suites := decodeJUnit(t, buf.Bytes())
+	if suites.Tests != 4 || suites.Failures != 0 || suites.Errors != 0 {
+		t.Errorf("expected tests=4 failures=0 errors=0, got tests=%d failures=%d errors=%d", suites.Tests, suites.Failures, suites.Errors)
+	}
+	if suites.Time != "1.500" {
+		t.Errorf("expected time 1.500, got %q", suites.Time)
+	}
+	if len(suites.Suites) != 2 || suites.Suites[0].Name != "a.md" {
+		t.Fatalf("expected one suite per file, got %+v", suites.Suites)
+	}
+	for _, tc := range suites.Suites[0].TestCases {
+		if tc.Failure != nil {
+			t.Errorf("expected no failure for %s", tc.Name)
+		}
+	}
+}
+
+func TestJUnitFormatter_WithErrors(t *testing.T) {
+	formatter := NewJUnitFormatter([]string{"final-blank-line", "single-h1"})
+	result := &Result{
+		Details: map[string][]rule.LintError{
+			"a.md": {
+				{File: "a.md", Line: 3, Rule: "single-h1", Severity: "error", Message: "Multiple H1"},
+				{File: "a.md", Line: 7, Rule: "single-h1", Severity: "error", Message: "Multiple H1 again"},
+				{File: "a.md", Line: 9, Rule: "final-blank-line", Severity: "warning", Message: "Missing final blank line"},
+			},
+		},
+		OrderedPaths: []string{"a.md"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	suite := decodeJUnit(t, buf.Bytes()).Suites[0]
+	if suite.Tests != 2 || suite.Failures != 2 {
+		t.Errorf("expected tests=2 failures=2, got tests=%d failures=%d", suite.Tests, suite.Failures)
+	}
+
+	fbl := suite.TestCases[0]
+	if fbl.Failure == nil || fbl.Failure.Type != "warning" || fbl.Failure.Message != "1 violation" {
+		t.Errorf("unexpected final-blank-line failure: %+v", fbl.Failure)
+	}
+
+	h1 := suite.TestCases[1]
+	if h1.Failure == nil || h1.Failure.Type != "error" || h1.Failure.Message != "2 violations" {
+		t.Fatalf("unexpected single-h1 failure: %+v", h1.Failure)
+	}
+	if !strings.Contains(h1.Failure.Body, "a.md:3: Multiple H1") || !strings.Contains(h1.Failure.Body, "a.md:7: Multiple H1 again") {
+		t.Errorf("expected both violations in failure body, got: %q", h1.Failure.Body)
+	}
+}
+
+func TestJUnitFormatter_UnlistedRules(t *testing.T) {
+	formatter := NewJUnitFormatter([]string{"single-h1"})
+	result := &Result{
+		Details: map[string][]rule.LintError{
+			"a.md": {
+				{File: "a.md", Line: 2, Rule: "todo-plugin", Severity: "error", Message: "TODO left"},
+				{File: "a.md", Line: 4, Rule: "custom-rule", Severity: "warning", Message: "custom"},
+			},
+		},
+		OrderedPaths: []string{"a.md"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	suites := decodeJUnit(t, buf.Bytes())
+	if suites.Tests != 3 || suites.Failures != 2 {
+		t.Errorf("expected tests=3 failures=2, got tests=%d failures=%d", suites.Tests, suites.Failures)
+	}
+	cases := suites.Suites[0].TestCases
+	if len(cases) != 3 || cases[1].Name != "custom-rule" || cases[2].Name != "todo-plugin" {
+		t.Fatalf("expected unlisted rules after the listed ones, sorted, got %+v", cases)
+	}
+	if cases[2].Failure == nil || !strings.Contains(cases[2].Failure.Body, "a.md:2: TODO left") {
+		t.Errorf("unexpected todo-plugin failure: %+v", cases[2].Failure)
+	}
+}
+
+func TestJUnitFormatter_FailedFiles(t *testing.T) {
+	formatter := NewJUnitFormatter([]string{"single-h1"})
+	result := &Result{
+		Details:      map[string][]rule.LintError{},
+		OrderedPaths: []string{"ok.md"},
+		FailedFiles: map[string]error{
+			"z.md": errors.New("permission denied"),
+			"y.md": errors.New("is a directory"),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	suites := decodeJUnit(t, buf.Bytes())
+	if suites.Errors != 2 || suites.Tests != 3 {
+		t.Errorf("expected errors=2 tests=3, got errors=%d tests=%d", suites.Errors, suites.Tests)
+	}
+	if len(suites.Suites) != 3 || suites.Suites[1].Name != "y.md" || suites.Suites[2].Name != "z.md" {
+		t.Fatalf("expected failed files sorted after linted files, got %+v", suites.Suites)
+	}
+	tc := suites.Suites[2].TestCases[0]
+	if tc.Error == nil || tc.Error.Body != "permission denied" {
+		t.Errorf("expected <error> with read error, got %+v", tc.Error)
+	}
+}
+
+func TestJUnitFormatter_WriteError(t *testing.T) {
+	formatter := NewJUnitFormatter(nil)
+	result := &Result{Details: map[string][]rule.LintError{}}
+
+	if err := formatter.Format(&errorWriter{}, result); err == nil {
+		t.Error("expected error when writing to errorWriter")
+	}
+	if err := formatter.Format(&limitedErrorWriter{limit: len(xml.Header)}, result); err == nil {
+		t.Error("expected error when writer fails after header")
+	}
+}
@@ -0,0 +1,197 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName  = "gomarklint"
+	sarifToolURI   = "https://github.com/shinagawa-web/gomarklint"
+	sarifRulesHelp = "https://shinagawa-web.github.io/gomarklint/docs/rules/"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	ColumnKind  string            `json:"columnKind"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool                `json:"executionSuccessful"`
+	Notifications       []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string                      `json:"level"`
+	Message   sarifText                   `json:"message"`
+	Locations []sarifNotificationLocation `json:"locations"`
+}
+
+// sarifNotificationLocation points at a whole file: an unreadable file has
+// no region to report.
+type sarifNotificationLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string    `json:"id"`
+	HelpURI string    `json:"helpUri"`
+	Short   sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// SARIFFormatter writes results as a SARIF 2.1.0 log, suitable for GitHub
+// code scanning and other SARIF viewers.
+type SARIFFormatter struct {
+	rules []string
+}
+
+// NewSARIFFormatter returns a formatter whose tool.driver.rules lists ruleNames.
+func NewSARIFFormatter(ruleNames []string) *SARIFFormatter {
+	return &SARIFFormatter{rules: ruleNames}
+}
+
+func (f *SARIFFormatter) Format(w io.Writer, result *Result) error {
+	driver := sarifDriver{
+		Name:           sarifToolName,
+		InformationURI: sarifToolURI,
+		Rules:          make([]sarifRule, 0, len(f.rules)),
+	}
+	ruleIndex := make(map[string]int, len(f.rules))
+	for _, name := range f.rules {
+		ruleIndex[name] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, newSARIFRule(name))
+	}
+
+	results := []sarifResult{}
+	for _, path := range result.OrderedPaths {
+		for _, e := range result.Details[path] {
+			// Errors from rules not in the registered list (should not happen
+			// for built-ins) still get a driver entry so ruleIndex stays valid.
+			idx, ok := ruleIndex[e.Rule]
+			if !ok {
+				idx = len(driver.Rules)
+				ruleIndex[e.Rule] = idx
+				driver.Rules = append(driver.Rules, newSARIFRule(e.Rule))
+			}
+			results = append(results, sarifResult{
+				RuleID:    e.Rule,
+				RuleIndex: idx,
+				Level:     sarifLevel(e.Severity),
+				Message:   sarifText{Text: e.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(e.File)},
+						Region: sarifRegion{
+							StartLine:   max(e.Line, 1),
+							StartColumn: e.Column,
+							EndLine:     e.EndLine,
+							EndColumn:   e.EndColumn,
+						},
+					},
+				}},
+			})
+		}
+	}
+
+	// Files that could not be read have no results; report them as tool
+	// notifications so the run does not look clean.
+	invocation := sarifInvocation{ExecutionSuccessful: len(result.FailedFiles) == 0}
+	for _, path := range sortedKeys(result.FailedFiles) {
+		var loc sarifNotificationLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(path)
+		invocation.Notifications = append(invocation.Notifications, sarifNotification{
+			Level:     "error",
+			Message:   sarifText{Text: "failed to read file: " + result.FailedFiles[path].Error()},
+			Locations: []sarifNotificationLocation{loc},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool:        sarifTool{Driver: driver},
+			Invocations: []sarifInvocation{invocation},
+			// LintError columns count characters, i.e. Unicode code points.
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+func newSARIFRule(name string) sarifRule {
+	return sarifRule{
+		ID:      name,
+		HelpURI: sarifRulesHelp,
+		Short:   sarifText{Text: name},
+	}
+}
+
+// sarifLevel maps a gomarklint severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	if severity == string(config.SeverityWarning) {
+		return "warning"
+	}
+	return "error"
+}
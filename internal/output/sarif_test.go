@@ -0,0 +1,179 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func decodeSARIF(t *testing.T, data []byte) sarifLog {
+	t.Helper()
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("output is not valid SARIF JSON: %v", err)
+	}
+	return log
+}
+
+func TestSARIFFormatter_NoErrors(t *testing.T) {
+	formatter := NewSARIFFormatter([]string{"final-blank-line", "single-h1"})
+	result := &Result{
+		Files:        1,
+		Duration:     10 * time.Millisecond,
+		Details:      map[string][]rule.LintError{},
+		OrderedPaths: []string{"a.md"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	log := decodeSARIF(t, buf.Bytes())
+	if log.Version != "2.1.0" {
+		t.Errorf("expected version 2.1.0, got %q", log.Version)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "gomarklint" {
+		t.Errorf("expected driver name gomarklint, got %q", run.Tool.Driver.Name)
+	}
+	if len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("expected 2 driver rules, got %d", len(run.Tool.Driver.Rules))
+	}
+	if run.Results == nil || len(run.Results) != 0 {
+		t.Errorf("expected empty (non-null) results, got %v", run.Results)
+	}
+	if len(run.Invocations) != 1 || !run.Invocations[0].ExecutionSuccessful {
+		t.Errorf("expected one successful invocation, got %+v", run.Invocations)
+	}
+}
+
+func TestSARIFFormatter_WithErrors(t *testing.T) {
+	formatter := NewSARIFFormatter([]string{"final-blank-line", "single-h1"})
+	result := &Result{
+		Details: map[string][]rule.LintError{
+			"docs/guide.md": {
+				{File: "docs/guide.md", Line: 3, Column: 1, EndLine: 3, EndColumn: 8, Rule: "single-h1", Severity: "error", Message: "Multiple H1"},
+				{File: "docs/guide.md", Line: 9, Rule: "final-blank-line", Severity: "warning", Message: "Missing final blank line"},
+			},
+		},
+		OrderedPaths: []string{"docs/guide.md"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := decodeSARIF(t, buf.Bytes()).Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	first := results[0]
+	if first.RuleID != "single-h1" || first.RuleIndex != 1 {
+		t.Errorf("expected single-h1 at index 1, got %q at %d", first.RuleID, first.RuleIndex)
+	}
+	if first.Level != "error" {
+		t.Errorf("expected level error, got %q", first.Level)
+	}
+	if first.Message.Text != "Multiple H1" {
+		t.Errorf("unexpected message: %q", first.Message.Text)
+	}
+	loc := first.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "docs/guide.md" {
+		t.Errorf("expected uri docs/guide.md, got %q", loc.ArtifactLocation.URI)
+	}
+	if loc.Region != (sarifRegion{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 8}) {
+		t.Errorf("unexpected region: %+v", loc.Region)
+	}
+
+	if results[1].Level != "warning" {
+		t.Errorf("expected level warning, got %q", results[1].Level)
+	}
+}
+
+func TestSARIFFormatter_UnregisteredRuleGetsDriverEntry(t *testing.T) {
+	formatter := NewSARIFFormatter([]string{"single-h1"})
+	result := &Result{
+		Details: map[string][]rule.LintError{
+			"a.md": {{File: "a.md", Line: 0, Rule: "house-style", Severity: "error", Message: "m"}},
+		},
+		OrderedPaths: []string{"a.md"},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	run := decodeSARIF(t, buf.Bytes()).Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[1].ID != "house-style" {
+		t.Errorf("expected house-style appended to driver rules, got %+v", run.Tool.Driver.Rules)
+	}
+	if run.Results[0].RuleIndex != 1 {
+		t.Errorf("expected ruleIndex 1, got %d", run.Results[0].RuleIndex)
+	}
+	// SARIF regions are 1-based; line 0 is clamped.
+	if got := run.Results[0].Locations[0].PhysicalLocation.Region.StartLine; got != 1 {
+		t.Errorf("expected startLine clamped to 1, got %d", got)
+	}
+}
+
+func TestSARIFFormatter_FailedFiles(t *testing.T) {
+	formatter := NewSARIFFormatter([]string{"single-h1"})
+	result := &Result{
+		Details:      map[string][]rule.LintError{},
+		OrderedPaths: []string{"a.md"},
+		FailedFiles: map[string]error{
+			"docs/secret.md": errors.New("permission denied"),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	run := decodeSARIF(t, buf.Bytes()).Runs[0]
+	if len(run.Invocations) != 1 {
+		t.Fatalf("expected 1 invocation, got %d", len(run.Invocations))
+	}
+	inv := run.Invocations[0]
+	if inv.ExecutionSuccessful {
+		t.Error("expected executionSuccessful false when a file could not be read")
+	}
+	if len(inv.Notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %+v", inv.Notifications)
+	}
+	n := inv.Notifications[0]
+	if n.Level != "error" {
+		t.Errorf("expected level error, got %q", n.Level)
+	}
+	if !strings.Contains(n.Message.Text, "permission denied") {
+		t.Errorf("expected message to carry the read error, got %q", n.Message.Text)
+	}
+	if got := n.Locations[0].PhysicalLocation.ArtifactLocation.URI; got != "docs/secret.md" {
+		t.Errorf("expected uri docs/secret.md, got %q", got)
+	}
+	if !strings.Contains(buf.String(), `"toolExecutionNotifications"`) {
+		t.Errorf("expected toolExecutionNotifications in output, got:\n%s", buf.String())
+	}
+}
+
+func TestSARIFFormatter_WriteError(t *testing.T) {
+	formatter := NewSARIFFormatter(nil)
+	result := &Result{Details: map[string][]rule.LintError{}}
+
+	if err := formatter.Format(&errorWriter{}, result); err == nil {
+		t.Error("expected error when writing to errorWriter")
+	}
+}
@@ -32,6 +32,12 @@ func (f *TextFormatter) Format(w io.Writer, result *Result) error {
 	if err := f.formatSummary(w, result); err != nil {
 		return err
 	}
+	if err := f.formatFixed(w, result); err != nil {
+		return err
+	}
+	if err := f.formatBaseline(w, result); err != nil {
+		return err
+	}
 	if err := f.formatStats(w, result); err != nil {
 		return err
 	}
@@ -63,7 +69,7 @@ func (f *TextFormatter) formatErrorDetails(w io.Writer, result *Result) error {
 			if e.Severity == string(config.SeverityWarning) {
 				prefix = "[warning] "
 			}
-			if _, err := fmt.Fprintf(w, "  %s:%d: %s%s\n", e.File, e.Line, prefix, e.Message); err != nil {
+			if _, err := fmt.Fprintf(w, "  %s: %s%s\n", location(e), prefix, e.Message); err != nil {
 				return err
 			}
 		}
@@ -96,6 +102,33 @@ func (f *TextFormatter) formatSummary(w io.Writer, result *Result) error {
 	return nil
 }
 
+func (f *TextFormatter) formatFixed(w io.Writer, result *Result) error {
+	if result.Fixed == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "%s✔%s Fixed %d issue(s)\n", colorGreen, colorReset, result.Fixed)
+	return err
+}
+
+func (f *TextFormatter) formatBaseline(w io.Writer, result *Result) error {
+	if result.Suppressed > 0 {
+		if _, err := fmt.Fprintf(w, "%s✓%s Suppressed %d issue(s) listed in the baseline\n", colorGray, colorReset, result.Suppressed); err != nil {
+			return err
+		}
+	}
+	if result.Stale > 0 {
+		what := "entries no longer match"
+		if result.Stale == 1 {
+			what = "entry no longer matches"
+		}
+		if _, err := fmt.Fprintf(w, "%s⚠%s %d baseline %s; run with --update-baseline to remove them\n",
+			colorYellow, colorReset, result.Stale, what); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f *TextFormatter) formatStats(w io.Writer, result *Result) error {
 	if result.LinksChecked != nil {
 		return f.formatStatsWithLinks(w, result)
@@ -475,3 +475,86 @@ func TestTextFormatter_StatsFormatting(t *testing.T) {
 		}
 	})
 }
+
+func TestTextFormatter_WithColumn(t *testing.T) {
+	formatter := NewTextFormatter()
+	result := &Result{
+		Total: 2,
+		Details: map[string][]rule.LintError{
+			"a.md": {
+				{File: "a.md", Line: 3, Column: 7, EndLine: 3, EndColumn: 12, Message: "with column"},
+				{File: "a.md", Line: 9, Message: "line only"},
+			},
+		},
+		OrderedPaths: []string{"a.md"},
+	}
+
+	output := formatAndGetOutput(t, formatter, result)
+	if !strings.Contains(output, "a.md:3:7: [error] with column") {
+		t.Errorf("expected file:line:col location, got: %s", output)
+	}
+	if !strings.Contains(output, "a.md:9: [error] line only") {
+		t.Errorf("expected file:line location when column is unset, got: %s", output)
+	}
+}
+
+func TestTextFormatter_Fixed(t *testing.T) {
+	formatter := NewTextFormatter()
+	result := &Result{
+		Files:        1,
+		Lines:        3,
+		Fixed:        2,
+		Details:      map[string][]rule.LintError{},
+		OrderedPaths: []string{},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Fixed 2 issue(s)") {
+		t.Errorf("expected fixed count, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	result.Fixed = 0
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Fixed") {
+		t.Errorf("expected no fixed line when nothing was fixed, got: %s", buf.String())
+	}
+}
+
+func TestTextFormatter_Baseline(t *testing.T) {
+	formatter := NewTextFormatter()
+	result := &Result{
+		Files:        1,
+		Suppressed:   4,
+		Stale:        1,
+		Details:      map[string][]rule.LintError{},
+		OrderedPaths: []string{},
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Suppressed 4 issue(s) listed in the baseline") {
+		t.Errorf("expected suppressed count, got: %s", out)
+	}
+	if !strings.Contains(out, "1 baseline entry no longer matches; run with --update-baseline to remove them") {
+		t.Errorf("expected stale count, got: %s", out)
+	}
+
+	buf.Reset()
+	result.Suppressed, result.Stale = 0, 2
+	if err := formatter.Format(&buf, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out = buf.String()
+	if strings.Contains(out, "Suppressed") || !strings.Contains(out, "2 baseline entries no longer match") {
+		t.Errorf("unexpected baseline lines, got: %s", out)
+	}
+}
@@ -0,0 +1,239 @@
+// Package plugin runs lint rules implemented as external executables.
+//
+// For each file, gomarklint starts the plugin's command, writes one Request
+// as JSON to its stdin and reads one Response as JSON from its stdout. A
+// plugin that exits non-zero, times out or writes anything but a valid
+// Response fails that file.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// ProtocolVersion is the version field of every Request. It changes only
+// when a Request or Response field changes meaning.
+const ProtocolVersion = 1
+
+// DefaultTimeout bounds one run of a plugin that sets no timeoutSeconds.
+const DefaultTimeout = 10 * time.Second
+
+// Request is what a plugin reads from stdin.
+type Request struct {
+	Version int    `json:"version"`
+	Path    string `json:"path"`
+	// Content is the whole file, frontmatter included.
+	Content string `json:"content"`
+	// Frontmatter is the text between the leading "---" lines, without
+	// them, or "" when the file has none.
+	Frontmatter string `json:"frontmatter"`
+	// Offset is the number of lines before the body: the frontmatter, its
+	// delimiters and the blank lines after it.
+	Offset int `json:"offset"`
+	// Lines holds the body's lines, classified the way built-in rules see
+	// them.
+	Lines []Line `json:"lines"`
+	// Options holds the plugin's entry in "rules", minus enabled and
+	// severity, as decoded from .gomarklint.json.
+	Options map[string]interface{} `json:"options"`
+}
+
+// Line is one body line of a Request.
+type Line struct {
+	// Line is the 1-based line number in the file.
+	Line int    `json:"line"`
+	Text string `json:"text"`
+	// Sanitized is Text with inline code spans and HTML comments replaced
+	// by spaces, so byte offsets match Text. Lines in code and HTML blocks
+	// are left as they are.
+	Sanitized    string `json:"sanitized"`
+	FencedCode   bool   `json:"fenced_code,omitempty"`
+	IndentedCode bool   `json:"indented_code,omitempty"`
+	HTMLBlock    bool   `json:"html_block,omitempty"`
+	HTMLComment  bool   `json:"html_comment,omitempty"`
+}
+
+// Response is what a plugin writes to stdout.
+type Response struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is one violation reported by a plugin. Positions use the same
+// 1-based line numbers and character columns as built-in rules; Column and
+// the end position are optional.
+type Finding struct {
+	Line      int       `json:"line"`
+	Column    int       `json:"column,omitempty"`
+	EndLine   int       `json:"end_line,omitempty"`
+	EndColumn int       `json:"end_column,omitempty"`
+	Message   string    `json:"message"`
+	Fix       *rule.Fix `json:"fix,omitempty"`
+}
+
+// Rule adapts a plugin to rule.FileRule. Plugins are not registered with
+// rule.Register; the linter builds one Rule per configured plugin.
+type Rule struct {
+	name    string
+	command []string
+	timeout time.Duration
+	// sem bounds how many copies of the plugin run at once.
+	sem chan struct{}
+}
+
+// New returns the Rule for pc.
+func New(pc config.PluginConfig) *Rule {
+	timeout := DefaultTimeout
+	if pc.TimeoutSeconds > 0 {
+		timeout = time.Duration(pc.TimeoutSeconds) * time.Second
+	}
+	return &Rule{
+		name:    pc.Name,
+		command: append([]string(nil), pc.Command...),
+		timeout: timeout,
+		sem:     make(chan struct{}, runtime.NumCPU()),
+	}
+}
+
+func (r *Rule) Name() string { return r.name }
+
+// DefaultSeverity is error: a plugin the config lists no severity for
+// reports errors, like built-in rules.
+func (r *Rule) DefaultSeverity() config.RuleSeverity { return config.SeverityError }
+
+// Options returns nil. A plugin's options are passed through unchecked, so
+// the linter does not resolve them against a declaration.
+func (r *Rule) Options() []rule.Option { return nil }
+
+// Check runs the plugin on the body in ctx. The plugin sees no frontmatter;
+// the linter calls CheckFile, which passes the whole file.
+func (r *Rule) Check(path string, ctx *preprocess.Context, offset int, opts rule.Options) []rule.LintError {
+	errs, _ := r.CheckFile(path, strings.Join(ctx.Lines(), "\n"), ctx, offset, opts)
+	return errs
+}
+
+// CheckFile runs the plugin on one file and returns its findings.
+func (r *Rule) CheckFile(path, content string, ctx *preprocess.Context, offset int, opts rule.Options) ([]rule.LintError, error) {
+	errs, err := r.check(path, content, ctx, offset, opts)
+	if err != nil {
+		return nil, fmt.Errorf("plugin failed: %w", err)
+	}
+	return errs, nil
+}
+
+func (r *Rule) check(path, content string, ctx *preprocess.Context, offset int, opts rule.Options) ([]rule.LintError, error) {
+	req, err := json.Marshal(newRequest(path, content, ctx, offset, opts))
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.run(req)
+	if err != nil {
+		return nil, err
+	}
+	var resp Response
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return r.toLintErrors(path, resp.Findings, offset+ctx.Len())
+}
+
+func newRequest(path, content string, ctx *preprocess.Context, offset int, opts rule.Options) Request {
+	lines := make([]Line, ctx.Len())
+	for i := range lines {
+		lines[i] = Line{
+			Line:         offset + i + 1,
+			Text:         ctx.Line(i),
+			Sanitized:    ctx.Sanitized(i),
+			FencedCode:   ctx.InFencedCode(i),
+			IndentedCode: ctx.InIndentedCode(i),
+			HTMLBlock:    ctx.InHTMLBlock(i),
+			HTMLComment:  ctx.InHTMLComment(i),
+		}
+	}
+	if opts == nil {
+		opts = rule.Options{}
+	}
+	return Request{
+		Version:     ProtocolVersion,
+		Path:        path,
+		Content:     content,
+		Frontmatter: file.Frontmatter(content),
+		Offset:      offset,
+		Lines:       lines,
+		Options:     opts,
+	}
+}
+
+// run starts the plugin, feeds it req and returns its stdout.
+func (r *Rule) run(req []byte) ([]byte, error) {
+	r.sem <- struct{}{}
+	defer func() { <-r.sem }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, r.command[0], r.command[1:]...)
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = bytes.NewReader(req)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %s", r.timeout)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := firstLine(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("%w: %s", err, msg)
+			}
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+// toLintErrors validates findings against a file of lastLine lines and
+// converts them. Messages get the "<rule>: " prefix built-in rules use.
+func (r *Rule) toLintErrors(path string, findings []Finding, lastLine int) ([]rule.LintError, error) {
+	errs := make([]rule.LintError, 0, len(findings))
+	for i, f := range findings {
+		if f.Line < 1 || f.Line > lastLine {
+			return nil, fmt.Errorf("finding %d: line %d is outside the file (1-%d)", i, f.Line, lastLine)
+		}
+		if strings.TrimSpace(f.Message) == "" {
+			return nil, fmt.Errorf("finding %d: empty message", i)
+		}
+		msg := f.Message
+		if !strings.HasPrefix(msg, r.name+": ") {
+			msg = r.name + ": " + msg
+		}
+		errs = append(errs, rule.LintError{
+			File:      path,
+			Line:      f.Line,
+			Column:    f.Column,
+			EndLine:   f.EndLine,
+			EndColumn: f.EndColumn,
+			Message:   msg,
+			Fix:       f.Fix,
+		})
+	}
+	return errs, nil
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// TestHelperProcess is the plugin the other tests run: they re-exec the test
+// binary with GOMARKLINT_PLUGIN_MODE set.
+func TestHelperProcess(t *testing.T) {
+	mode := os.Getenv("GOMARKLINT_PLUGIN_MODE")
+	if mode == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	var req Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintf(os.Stderr, "decode: %v\n", err)
+		os.Exit(2)
+	}
+	switch mode {
+	case "todo":
+		var findings []Finding
+		for _, l := range req.Lines {
+			if col := strings.Index(l.Sanitized, "TODO"); col >= 0 && !l.FencedCode {
+				findings = append(findings, Finding{Line: l.Line, Column: col + 1, EndColumn: col + 5, Message: "TODO left in text"})
+			}
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(Response{Findings: findings})
+	case "echo":
+		_ = json.NewEncoder(os.Stdout).Encode(Response{Findings: []Finding{{Line: 1, Message: mustJSON(req)}}})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "Traceback: something broke")
+		fmt.Fprintln(os.Stderr, "second line")
+		os.Exit(3)
+	case "garbage":
+		fmt.Print("not json")
+	case "out-of-range":
+		fmt.Print(`{"findings":[{"line":99,"message":"too far"}]}`)
+	case "sleep":
+		time.Sleep(10 * time.Second)
+	}
+}
+
+func mustJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func helperRule(t *testing.T, mode string, timeoutSeconds int) *Rule {
+	t.Helper()
+	t.Setenv("GOMARKLINT_PLUGIN_MODE", mode)
+	return New(config.PluginConfig{
+		Name:           "house-style",
+		Command:        []string{os.Args[0], "-test.run=^TestHelperProcess$"},
+		TimeoutSeconds: timeoutSeconds,
+	})
+}
+
+func check(t *testing.T, r *Rule, content string, opts rule.Options) ([]rule.LintError, error) {
+	t.Helper()
+	body, offset := file.StripFrontmatter(content)
+	return r.CheckFile("doc.md", content, preprocess.Scan(strings.Split(body, "\n")), offset, opts)
+}
+
+func TestCheckFile_Findings(t *testing.T) {
+	r := helperRule(t, "todo", 0)
+	content := "---\ntitle: x\n---\n\n# Title\n\nA TODO here and `TODO` in code.\n\n```\nTODO\n```\n"
+	errs, err := check(t, r, content, nil)
+	if err != nil {
+		t.Fatalf("CheckFile: %v", err)
+	}
+	want := []rule.LintError{{File: "doc.md", Line: 7, Column: 3, EndColumn: 7, Message: "house-style: TODO left in text"}}
+	if len(errs) != len(want) || errs[0] != want[0] {
+		t.Errorf("got %+v, want %+v", errs, want)
+	}
+}
+
+func TestCheckFile_Request(t *testing.T) {
+	r := helperRule(t, "echo", 0)
+	content := "---\ntitle: x\n---\n\n# Title\n\n```go\nx := 1\n```\n"
+	errs, err := check(t, r, content, rule.Options{"words": []interface{}{"foo"}})
+	if err != nil {
+		t.Fatalf("CheckFile: %v", err)
+	}
+	var req Request
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(errs[0].Message, "house-style: ")), &req); err != nil {
+		t.Fatalf("decode echoed request: %v", err)
+	}
+	if req.Version != ProtocolVersion || req.Path != "doc.md" || req.Content != content || req.Offset != 4 {
+		t.Errorf("unexpected request header: %+v", req)
+	}
+	if req.Frontmatter != "title: x" {
+		t.Errorf("Frontmatter = %q, want %q", req.Frontmatter, "title: x")
+	}
+	if words, _ := req.Options["words"].([]interface{}); len(words) != 1 || words[0] != "foo" {
+		t.Errorf("Options = %v, want words [foo]", req.Options)
+	}
+	if len(req.Lines) != 6 {
+		t.Fatalf("got %d lines, want 6", len(req.Lines))
+	}
+	wantFirst := Line{Line: 5, Text: "# Title", Sanitized: "# Title"}
+	if req.Lines[0] != wantFirst {
+		t.Errorf("Lines[0] = %+v, want %+v", req.Lines[0], wantFirst)
+	}
+	if !req.Lines[3].FencedCode || req.Lines[3].Line != 8 {
+		t.Errorf("Lines[3] = %+v, want fenced code on line 8", req.Lines[3])
+	}
+}
+
+func TestCheckFile_Failures(t *testing.T) {
+	tests := []struct {
+		mode    string
+		timeout int
+		want    string
+	}{
+		{mode: "crash", want: "plugin failed: exit status 3: Traceback: something broke"},
+		{mode: "garbage", want: "plugin failed: invalid response"},
+		{mode: "out-of-range", want: "plugin failed: finding 0: line 99 is outside the file (1-2)"},
+		{mode: "sleep", timeout: 1, want: "plugin failed: timed out after 1s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			r := helperRule(t, tt.mode, tt.timeout)
+			_, err := check(t, r, "# Title\n", nil)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFile_CommandNotFound(t *testing.T) {
+	r := New(config.PluginConfig{Name: "missing", Command: []string{"./does-not-exist"}})
+	_, err := check(t, r, "# Title\n", nil)
+	if err == nil || !strings.Contains(err.Error(), "plugin failed") {
+		t.Errorf("err = %v, want a plugin failure", err)
+	}
+}
+
+func TestToLintErrors_EmptyMessage(t *testing.T) {
+	r := New(config.PluginConfig{Name: "p", Command: []string{"x"}})
+	_, err := r.toLintErrors("doc.md", []Finding{{Line: 1, Message: " "}}, 1)
+	if err == nil || err.Error() != "finding 0: empty message" {
+		t.Errorf("err = %v", err)
+	}
+	errs, err := r.toLintErrors("doc.md", []Finding{{Line: 1, Message: "p: already prefixed"}}, 1)
+	if err != nil || errs[0].Message != "p: already prefixed" {
+		t.Errorf("got %+v, %v", errs, err)
+	}
+}
+
+func TestNew_Timeout(t *testing.T) {
+	if got := New(config.PluginConfig{Name: "p", Command: []string{"x"}}).timeout; got != DefaultTimeout {
+		t.Errorf("default timeout = %s, want %s", got, DefaultTimeout)
+	}
+	if got := New(config.PluginConfig{Name: "p", Command: []string{"x"}, TimeoutSeconds: 3}).timeout; got != 3*time.Second {
+		t.Errorf("timeout = %s, want 3s", got)
+	}
+}
@@ -7,6 +7,7 @@ type Context struct {
 	flags     []uint8
 	sanitized map[int]string
 	fences    []FenceSpan
+	tables    []TableSpan
 }
 
 // FenceSpan is the line range of one fenced code block.
@@ -24,12 +25,14 @@ const (
 )
 
 func (c *Context) Len() int                  { return len(c.lines) }
+func (c *Context) Lines() []string           { return c.lines }
 func (c *Context) Line(i int) string         { return c.lines[i] }
 func (c *Context) InFencedCode(i int) bool   { return c.flags[i]&flagFencedCode != 0 }
 func (c *Context) InIndentedCode(i int) bool { return c.flags[i]&flagIndentedCode != 0 }
 func (c *Context) InHTMLBlock(i int) bool    { return c.flags[i]&flagHTMLBlock != 0 }
 func (c *Context) InHTMLComment(i int) bool  { return c.flags[i]&flagHTMLComment != 0 }
 func (c *Context) FenceSpans() []FenceSpan   { return c.fences }
+func (c *Context) TableSpans() []TableSpan   { return c.tables }
 
 func (c *Context) Sanitized(i int) string {
 	if s, ok := c.sanitized[i]; ok {
@@ -71,6 +74,7 @@ func Scan(lines []string) *Context {
 	if s.inFence {
 		c.fences = append(c.fences, FenceSpan{Start: fenceStart, End: -1})
 	}
+	c.tables = findTables(c)
 	return c
 }
 
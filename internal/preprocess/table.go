@@ -0,0 +1,111 @@
+package preprocess
+
+import "strings"
+
+// TableSpan is the line range of one GFM pipe table. Start is the header row
+// and Start+1 the delimiter row; End is the last row, inclusive.
+type TableSpan struct {
+	Start int
+	End   int
+}
+
+// findTables returns the pipe tables among lines no other block claims. A
+// table is a row containing a pipe followed by a delimiter row, and runs to
+// the first blank line or line that starts another block.
+//
+// The delimiter row is recognized leniently, as any cells of hyphens, colons
+// and spaces, and the header is not required to have as many cells as the
+// delimiter row. GFM renders such a table as paragraph text; recognizing it
+// lets rules report the mistake instead of ignoring the table.
+func findTables(c *Context) []TableSpan {
+	var tables []TableSpan
+	for i := 1; i < len(c.lines); i++ {
+		if !isDelimiterRow(c.lines[i]) || !hasTablePipe(c.lines[i-1]) || !c.tableStart(i-1) || !c.tableStart(i) {
+			continue
+		}
+		end := i
+		for end+1 < len(c.lines) && c.flags[end+1] == 0 && !endsTable(c.lines[end+1]) {
+			end++
+		}
+		tables = append(tables, TableSpan{Start: i - 1, End: end})
+		i = end + 1
+	}
+	return tables
+}
+
+// tableStart reports whether line i can be the header or delimiter row of a
+// table: outside other blocks, and indented less than code.
+func (c *Context) tableStart(i int) bool {
+	cols, _ := indentColumns(c.lines[i])
+	return c.flags[i] == 0 && cols < 4
+}
+
+// hasTablePipe reports whether line contains a pipe not escaped with a
+// backslash. Pipes inside code spans count, as they do in GFM tables.
+func hasTablePipe(line string) bool {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '|':
+			return true
+		}
+	}
+	return false
+}
+
+// isDelimiterRow reports whether line is made of pipes and cells of hyphens,
+// colons and spaces, each cell with at least one hyphen.
+func isDelimiterRow(line string) bool {
+	if !strings.Contains(line, "|") || !strings.Contains(line, "-") {
+		return false
+	}
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimSuffix(strings.TrimPrefix(trimmed, "|"), "|")
+	for _, cell := range strings.Split(trimmed, "|") {
+		cell = strings.TrimSpace(cell)
+		if !strings.Contains(cell, "-") || strings.Trim(cell, "-: \t") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// endsTable reports whether line ends a table rather than adding a row: a
+// blank line, or the start of a heading, block quote or thematic break.
+func endsTable(line string) bool {
+	cols, first := indentColumns(line)
+	if first == len(line) {
+		return true
+	}
+	if cols >= 4 {
+		return false
+	}
+	rest := line[first:]
+	switch rest[0] {
+	case '>':
+		return true
+	case '#':
+		n := len(rest) - len(strings.TrimLeft(rest, "#"))
+		return n <= 6 && (n == len(rest) || rest[n] == ' ' || rest[n] == '\t')
+	case '-', '*', '_':
+		return isThematicBreakLine(rest)
+	}
+	return false
+}
+
+// isThematicBreakLine reports whether s is three or more of one of '-', '*'
+// or '_', optionally separated by spaces or tabs.
+func isThematicBreakLine(s string) bool {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case s[0]:
+			n++
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
@@ -0,0 +1,91 @@
+package preprocess
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableSpans(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []TableSpan
+	}{
+		{
+			name: "table ends at a blank line",
+			doc:  "| a | b |\n| - | - |\n| 1 | 2 |\n\ntext",
+			want: []TableSpan{{Start: 0, End: 2}},
+		},
+		{
+			name: "no outer pipes, header only",
+			doc:  "a | b\n:-|-:",
+			want: []TableSpan{{Start: 0, End: 1}},
+		},
+		{
+			name: "header after paragraph text",
+			doc:  "Intro\n| a |\n| --- |\n| 1 |",
+			want: []TableSpan{{Start: 1, End: 3}},
+		},
+		{
+			// GFM takes any line up to a blank line or another block as a
+			// row, with or without pipes.
+			name: "rows without pipes and ended by a heading",
+			doc:  "a | b\n--|--\n1 | 2\nloose text\n## Next",
+			want: []TableSpan{{Start: 0, End: 3}},
+		},
+		{
+			name: "ended by a block quote or thematic break",
+			doc:  "a | b\n--|--\n> quote\n\na | b\n--|--\n***",
+			want: []TableSpan{{Start: 0, End: 1}, {Start: 4, End: 5}},
+		},
+		{
+			name: "malformed delimiter cells are recognized",
+			doc:  "a | b | c\n-:-|- -|::-",
+			want: []TableSpan{{Start: 0, End: 1}},
+		},
+		{
+			name: "adjacent tables",
+			doc:  "a | b\n--|--\n\nc | d\n--|--",
+			want: []TableSpan{{Start: 0, End: 1}, {Start: 3, End: 4}},
+		},
+		{
+			name: "setext underline is not a delimiter row",
+			doc:  "Title | x\n---",
+			want: nil,
+		},
+		{
+			name: "escaped pipe is not a table",
+			doc:  "a \\| b\n--|--",
+			want: nil,
+		},
+		{
+			name: "delimiter cell without hyphens",
+			doc:  "a | b\n-- | ::",
+			want: nil,
+		},
+		{
+			name: "table in fenced code",
+			doc:  "```\na | b\n--|--\n```",
+			want: nil,
+		},
+		{
+			name: "indented code",
+			doc:  "    a | b\n    --|--",
+			want: nil,
+		},
+		{
+			name: "fence ends the table",
+			doc:  "a | b\n--|--\n```\ncode | x\n```",
+			want: []TableSpan{{Start: 0, End: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Scan(strings.Split(tt.doc, "\n")).TableSpans()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,21 +17,23 @@ func CheckBlanksAroundFences(filename string, ctx *preprocess.Context, offset in
 			j--
 		}
 		if j >= 0 && firstNonSpaceByte(ctx.Line(j)) != 0 {
-			errs = append(errs, LintError{
+			e := atContent(LintError{
 				File:    filename,
 				Line:    offset + span.Start + 1,
 				Message: "blanks-around-fences: fenced code block must be preceded by a blank line",
-			})
+			}, ctx.Line(span.Start))
+			errs = append(errs, withFix(e, insertBlankLineBefore(offset+span.Start+1)))
 		}
 
 		// Followed by a blank line? Only closed fences have a line after them to
 		// check; an unclosed fence (End == -1) runs to EOF.
 		if span.End >= 0 && span.End+1 < ctx.Len() && firstNonSpaceByte(ctx.Line(span.End+1)) != 0 {
-			errs = append(errs, LintError{
+			e := atContent(LintError{
 				File:    filename,
 				Line:    offset + span.End + 1,
 				Message: "blanks-around-fences: fenced code block must be followed by a blank line",
-			})
+			}, ctx.Line(span.End))
+			errs = append(errs, withFix(e, insertBlankLineBefore(offset+span.End+2)))
 		}
 	}
 
@@ -32,11 +32,12 @@ func CheckBlanksAroundHeadings(filename string, ctx *preprocess.Context, offset
 		// Check "followed by blank" before the block skip so a heading
 		// immediately followed by a code/HTML block opener is still flagged.
 		if prevWasHeading && !isBlank {
-			errs = append(errs, LintError{
+			e := atContent(LintError{
 				File:    filename,
 				Line:    offset + i,
 				Message: "blanks-around-headings: heading must be followed by a blank line",
-			})
+			}, ctx.Line(i-1))
+			errs = append(errs, withFix(e, insertBlankLineBefore(offset+i+1)))
 		}
 
 		if inBlockContext(ctx, i) {
@@ -47,11 +48,12 @@ func CheckBlanksAroundHeadings(filename string, ctx *preprocess.Context, offset
 
 		if isATXHeading(trimmed) {
 			if i > 0 && !prevBlank {
-				errs = append(errs, LintError{
+				e := atContent(LintError{
 					File:    filename,
 					Line:    offset + i + 1,
 					Message: "blanks-around-headings: heading must be preceded by a blank line",
-				})
+				}, ctx.Line(i))
+				errs = append(errs, withFix(e, insertBlankLineBefore(offset+i+1)))
 			}
 			prevWasHeading = true
 		} else {
@@ -57,11 +57,12 @@ func CheckBlanksAroundLists(filename string, ctx *preprocess.Context, offset int
 		// Check "end of block" before the block skip so a list item immediately
 		// followed by a code/HTML block opener is still flagged (lesson from PR-4).
 		if prevWasListItem && !isBlank && !isList {
-			errs = append(errs, LintError{
+			e := atContent(LintError{
 				File:    filename,
 				Line:    offset + prevLineNum + 1,
 				Message: "blanks-around-lists: list must be followed by a blank line",
-			})
+			}, ctx.Line(prevLineNum))
+			errs = append(errs, withFix(e, insertBlankLineBefore(offset+i+1)))
 		}
 
 		if inBlockContext(ctx, i) {
@@ -73,11 +74,12 @@ func CheckBlanksAroundLists(filename string, ctx *preprocess.Context, offset int
 		if isList {
 			// Check "start of block": first item of a block not preceded by blank.
 			if i > 0 && !prevBlank && !prevWasListItem {
-				errs = append(errs, LintError{
+				e := atContent(LintError{
 					File:    filename,
 					Line:    offset + i + 1,
 					Message: "blanks-around-lists: list must be preceded by a blank line",
-				})
+				}, line)
+				errs = append(errs, withFix(e, insertBlankLineBefore(offset+i+1)))
 			}
 			prevWasListItem = true
 			prevLineNum = i + 1
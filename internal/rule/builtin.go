@@ -0,0 +1,258 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// builtinRule adapts one of this package's Check functions to Rule.
+type builtinRule struct {
+	name    string
+	options []Option
+	check   func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError
+}
+
+func (r builtinRule) Name() string                         { return r.name }
+func (r builtinRule) DefaultSeverity() config.RuleSeverity { return config.SeverityError }
+func (r builtinRule) Options() []Option                    { return r.options }
+
+func (r builtinRule) Check(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+	return r.check(path, ctx, offset, opts)
+}
+
+// uncachedRule is a builtinRule whose findings depend on other files, so
+// the linter checks it on every run.
+type uncachedRule struct{ builtinRule }
+
+func (uncachedRule) Uncached() {}
+
+// plain adapts a rule that takes no options.
+func plain(name string, fn func(string, *preprocess.Context, int) []LintError) builtinRule {
+	return builtinRule{name: name, check: func(path string, ctx *preprocess.Context, offset int, _ Options) []LintError {
+		return fn(path, ctx, offset)
+	}}
+}
+
+// slugOptions declares the options of the rules that match fragments against
+// heading slugs.
+func slugOptions() []Option {
+	return []Option{
+		{Name: "slug-algorithm", Type: OptionString, Default: "github"},
+		{Name: "slug-params", Type: OptionObject},
+	}
+}
+
+// styleOption declares the "style" option of the consistent-* rules.
+func styleOption(values ...string) []Option {
+	return []Option{{Name: "style", Type: OptionString, Default: "consistent", Enum: append([]string{"consistent"}, values...)}}
+}
+
+// ExternalLinkName is the rule the linter runs apart from the others: it
+// shares a URL cache across files and is never run while fixing.
+const ExternalLinkName = "external-link"
+
+var externalLinkOptions = append([]Option{
+	{Name: "timeoutSeconds", Type: OptionInt, Default: 5, Validate: IntAtLeast(1)},
+	{Name: "retryDelayMs", Type: OptionInt, Default: DefaultRetryDelayMs, Validate: IntAtLeast(0)},
+	{Name: "maxConcurrency", Type: OptionInt, Default: DefaultMaxConcurrency, Validate: IntRange(1, MaxConcurrencyLimit)},
+	{Name: "maxRetries", Type: OptionInt, Default: DefaultMaxRetries, Validate: IntRange(0, MaxRetriesLimit)},
+	{Name: "maxRetryAfterSeconds", Type: OptionInt, Default: DefaultMaxRetryAfterSeconds, Validate: IntRange(0, MaxRetryAfterSecondsLimit)},
+	{Name: "perHostConcurrency", Type: OptionInt, Default: DefaultPerHostConcurrency, Validate: IntRange(1, MaxPerHostConcurrencyLimit)},
+	{Name: "perHostIntervalMs", Type: OptionInt, Default: DefaultPerHostIntervalMs, Validate: validatePerHostIntervalMs},
+	{Name: "skipPatterns", Type: OptionStringList, Default: []string{}},
+	{Name: "allowedStatuses", Type: OptionIntList, Default: []int{}},
+	{Name: "checkFragments", Type: OptionBool, Default: false},
+	{Name: "cacheTTL", Type: OptionString, Default: DefaultLinkCacheTTL, Validate: validateDuration},
+	{Name: "failureCacheTTL", Type: OptionString, Default: DefaultLinkFailureCacheTTL, Validate: validateDuration},
+}, slugOptions()...)
+
+// validateDuration accepts a non-negative duration such as "24h" or "30m".
+func validateDuration(v interface{}) error {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return fmt.Errorf("must be a duration such as \"24h\" or \"30m\", got %q", v)
+	}
+	if d < 0 {
+		return fmt.Errorf("must not be negative, got %q", v)
+	}
+	return nil
+}
+
+// validatePerHostIntervalMs rejects values between 1 and 999 (too small to be intentional).
+func validatePerHostIntervalMs(v interface{}) error {
+	n := v.(int)
+	if n != 0 && (n < MinPerHostIntervalMs || n > MaxPerHostIntervalMsLimit) {
+		return fmt.Errorf("must be 0 (disabled) or between %d and %d, got %d", MinPerHostIntervalMs, MaxPerHostIntervalMsLimit, n)
+	}
+	return nil
+}
+
+// validateBrSpaces rejects 1, which cannot make a hard line break.
+func validateBrSpaces(v interface{}) error {
+	if n := v.(int); n != 0 && n < 2 {
+		return fmt.Errorf("must be 0 (disabled) or at least 2, got %d", n)
+	}
+	return nil
+}
+
+// CompileSkipPatterns compiles the external-link skipPatterns option. Invalid
+// patterns are left out; each returned error names one of them.
+func CompileSkipPatterns(patterns []string) ([]*regexp.Regexp, []error) {
+	var compiled []*regexp.Regexp
+	var errs []error
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s (error: %w)", p, err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, errs
+}
+
+// builtinRules lists the built-in rules in dispatch order.
+var builtinRules = []builtinRule{
+	{name: "final-blank-line", check: func(path string, ctx *preprocess.Context, offset int, _ Options) []LintError {
+		return CheckFinalBlankLine(path, ctx.Lines(), offset)
+	}},
+	plain("no-bare-urls", CheckNoBareURLs),
+	plain("single-h1", CheckSingleH1),
+	plain("duplicate-heading", CheckDuplicateHeadings),
+	plain("no-setext-headings", CheckNoSetextHeadings),
+	plain("blanks-around-headings", CheckBlanksAroundHeadings),
+	plain("no-emphasis-as-heading", CheckNoEmphasisAsHeading),
+	plain("unclosed-code-block", CheckUnclosedCodeBlocks),
+	plain("fenced-code-language", CheckFencedCodeLanguage),
+	plain("blanks-around-fences", CheckBlanksAroundFences),
+	plain("empty-alt-text", CheckEmptyAltText),
+	plain("no-empty-links", CheckNoEmptyLinks),
+	{
+		name:    "no-undefined-references",
+		options: []Option{{Name: "checkShortcuts", Type: OptionBool, Default: false}},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckNoUndefinedReferences(path, ctx, offset, opts.Bool("checkShortcuts"))
+		},
+	},
+	plain("no-unused-definitions", CheckNoUnusedDefinitions),
+	plain("no-duplicate-definitions", CheckNoDuplicateDefinitions),
+	plain("no-multiple-blank-lines", CheckNoMultipleBlankLines),
+	plain("blanks-around-lists", CheckBlanksAroundLists),
+	plain("no-hard-tabs", CheckNoHardTabs),
+	{
+		name:    "heading-level",
+		options: []Option{{Name: "minLevel", Type: OptionInt, Default: 2}},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckHeadingLevels(path, ctx, offset, opts.Int("minLevel"))
+		},
+	},
+	{
+		name:    "consistent-code-fence",
+		options: styleOption("backtick", "tilde"),
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckConsistentCodeFence(path, ctx, offset, opts.String("style"))
+		},
+	},
+	{
+		name:    "consistent-emphasis-style",
+		options: styleOption("asterisk", "underscore"),
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckConsistentEmphasisStyle(path, ctx, offset, opts.String("style"))
+		},
+	},
+	{
+		name:    "consistent-list-marker",
+		options: styleOption("dash", "asterisk", "plus"),
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckConsistentListMarker(path, ctx, offset, opts.String("style"))
+		},
+	},
+	{
+		name:    "max-line-length",
+		options: []Option{{Name: "lineLength", Type: OptionInt, Default: 80, Validate: IntAtLeast(1)}},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckMaxLineLength(path, ctx, offset, opts.Int("lineLength"))
+		},
+	},
+	{
+		name: "no-trailing-spaces",
+		options: []Option{
+			{Name: "brSpaces", Type: OptionInt, Default: 2, Validate: validateBrSpaces},
+			{Name: "allowInContainers", Type: OptionBool, Default: false},
+		},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckNoTrailingSpaces(path, ctx, offset, opts.Int("brSpaces"), opts.Bool("allowInContainers"))
+		},
+	},
+	{
+		name:    "no-trailing-punctuation",
+		options: []Option{{Name: "punctuation", Type: OptionString, Default: config.DefaultNoTrailingPunctuation}},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckNoTrailingPunctuation(path, ctx, offset, opts.String("punctuation"))
+		},
+	},
+	{
+		name: "table-formatting",
+		options: []Option{
+			{Name: "pipeStyle", Type: OptionString, Default: "consistent", Enum: []string{"consistent", "both", "leading", "trailing", "none"}},
+			{Name: "alignColumns", Type: OptionBool, Default: false},
+		},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckTableFormatting(path, ctx, offset, opts.String("pipeStyle"), opts.Bool("alignColumns"))
+		},
+	},
+	{
+		name:    "link-fragments",
+		options: slugOptions(),
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckLinkFragments(path, ctx, offset, opts)
+		},
+	},
+	{
+		name:    ExternalLinkName,
+		options: externalLinkOptions,
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			patterns, _ := CompileSkipPatterns(opts.Strings("skipPatterns"))
+			sites := ExternalLinkSites(ctx, offset, patterns)
+			c := NewLinkChecker(opts, &sync.Map{})
+			c.Check(DistinctURLs(sites))
+			return c.Errors(path, sites)
+		},
+	},
+}
+
+// uncachedBuiltinRules lists the built-in rules that read other files, in
+// dispatch order after builtinRules.
+var uncachedBuiltinRules = []uncachedRule{
+	{builtinRule{
+		name:    "relative-links",
+		options: slugOptions(),
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckRelativeLinks(path, ctx, offset, opts)
+		},
+	}},
+	{builtinRule{
+		name: "local-assets",
+		options: []Option{
+			{Name: "siteRoot", Type: OptionString, Default: ""},
+			{Name: "maxBytes", Type: OptionInt, Default: 0, Validate: IntAtLeast(0)},
+		},
+		check: func(path string, ctx *preprocess.Context, offset int, opts Options) []LintError {
+			return CheckLocalAssets(path, ctx, offset, opts.String("siteRoot"), opts.Int("maxBytes"))
+		},
+	}},
+}
+
+func init() {
+	for _, r := range builtinRules {
+		Register(r)
+	}
+	for _, r := range uncachedBuiltinRules {
+		Register(r)
+	}
+}
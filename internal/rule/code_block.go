@@ -9,11 +9,11 @@ func CheckUnclosedCodeBlocks(filename string, ctx *preprocess.Context, offset in
 
 	for _, span := range ctx.FenceSpans() {
 		if span.End == -1 {
-			errs = append(errs, LintError{
+			errs = append(errs, atContent(LintError{
 				File:    filename,
 				Line:    span.Start + offset + 1,
 				Message: "Unclosed code block",
-			})
+			}, ctx.Line(span.Start)))
 		}
 	}
 
@@ -1,6 +1,8 @@
 package rule
 
 import (
+	"strings"
+
 	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
 )
 
@@ -9,9 +11,16 @@ func CheckConsistentCodeFence(filename string, ctx *preprocess.Context, offset i
 	var expectedCh byte // 0 until first fence seen (consistent mode)
 
 	for _, span := range ctx.FenceSpans() {
-		ch := firstNonSpaceByte(ctx.Line(span.Start))
+		line := ctx.Line(span.Start)
+		ch := firstNonSpaceByte(line)
 		if err := checkFenceStyle(filename, offset+span.Start+1, ch, style, &expectedCh); err != nil {
-			errs = append(errs, *err)
+			start := strings.IndexByte(line, ch)
+			marker := openingFenceMarker(line[start:])
+			e := atSpan(*err, line, start, start+len(marker))
+			if edits := fenceStyleEdits(ctx, span, offset, wantFenceChar(style, expectedCh)); edits != nil {
+				e = withFix(e, edits...)
+			}
+			errs = append(errs, e)
 		}
 	}
 
@@ -52,6 +61,49 @@ func checkFenceStyle(filename string, line int, ch byte, style string, expectedC
 	return nil
 }
 
+func wantFenceChar(style string, expectedCh byte) byte {
+	switch style {
+	case "backtick":
+		return '`'
+	case "tilde":
+		return '~'
+	}
+	return expectedCh
+}
+
+// fenceStyleEdits rewrites the opening and closing markers of span with want.
+// It returns nil when the rewrite would change the document: a backtick info
+// string may not contain backticks, and a body line that already looks like
+// a want-fence would close the block early.
+func fenceStyleEdits(ctx *preprocess.Context, span preprocess.FenceSpan, offset int, want byte) []Edit {
+	open := ctx.Line(span.Start)
+	start := strings.IndexByte(open, firstNonSpaceByte(open))
+	marker := openingFenceMarker(open[start:])
+	if want == '`' && strings.IndexByte(open[start+len(marker):], '`') >= 0 {
+		return nil
+	}
+
+	end := span.End
+	if end < 0 {
+		end = ctx.Len()
+	}
+	for i := span.Start + 1; i < end; i++ {
+		m := openingFenceMarker(strings.TrimLeft(ctx.Line(i), " "))
+		if m != "" && m[0] == want && len(m) >= len(marker) {
+			return nil
+		}
+	}
+
+	edits := []Edit{replaceBytes(offset+span.Start+1, open, start, start+len(marker), strings.Repeat(string(want), len(marker)))}
+	if span.End >= 0 {
+		closing := ctx.Line(span.End)
+		cs := strings.IndexByte(closing, marker[0])
+		cm := openingFenceMarker(closing[cs:])
+		edits = append(edits, replaceBytes(offset+span.End+1, closing, cs, cs+len(cm), strings.Repeat(string(want), len(cm))))
+	}
+	return edits
+}
+
 func fenceCharName(ch byte) string {
 	if ch == '`' {
 		return "backtick"
@@ -26,13 +26,15 @@ func CheckConsistentEmphasisStyle(filename string, ctx *preprocess.Context, offs
 			scanned = stripLinkURLs(scanned)
 		}
 
-		checkEmphasisLine(scanned, filename, offset+i+1, style, &expectedEmphCh, &expectedStrongCh, &errs)
+		checkEmphasisLine(scanned, ctx.Line(i), filename, offset+i+1, style, &expectedEmphCh, &expectedStrongCh, &errs)
 	}
 
 	return errs
 }
 
-func checkEmphasisLine(s string, filename string, lineNum int, style string, expectedEmphCh *byte, expectedStrongCh *byte, errs *[]LintError) {
+// s is the sanitized line used for scanning; raw is the original line, which
+// has the same byte length and is used only to compute columns.
+func checkEmphasisLine(s, raw string, filename string, lineNum int, style string, expectedEmphCh *byte, expectedStrongCh *byte, errs *[]LintError) {
 	i := 0
 	for i < len(s) {
 		ch := s[i]
@@ -87,7 +89,14 @@ func checkEmphasisLine(s string, filename string, lineNum int, style string, exp
 			kind = "strong"
 		}
 		if err := checkEmphasisStyle(filename, lineNum, ch, style, expected, kind); err != nil {
-			*errs = append(*errs, *err)
+			e := atSpan(*err, raw, i, closerPos+runLen)
+			if want := wantEmphChar(style, *expected); want == '*' || !isEmphIntraword(s, i, closerPos+runLen) {
+				marker := strings.Repeat(string(want), runLen)
+				e = withFix(e,
+					replaceBytes(lineNum, raw, i, afterRun, marker),
+					replaceBytes(lineNum, raw, closerPos, closerPos+runLen, marker))
+			}
+			*errs = append(*errs, e)
 		}
 		i = closerPos + runLen // advance past the entire span
 	}
@@ -159,6 +168,23 @@ func checkEmphasisStyle(filename string, line int, ch byte, style string, expect
 	return nil
 }
 
+func wantEmphChar(style string, expectedCh byte) byte {
+	switch style {
+	case "asterisk":
+		return '*'
+	case "underscore":
+		return '_'
+	}
+	return expectedCh
+}
+
+// isEmphIntraword reports whether the span s[start:end] touches a word
+// character on either side. Underscores do not open or close emphasis there,
+// so such a span cannot be rewritten to underscore style.
+func isEmphIntraword(s string, start, end int) bool {
+	return (start > 0 && isEmphWordChar(s[start-1])) || (end < len(s) && isEmphWordChar(s[end]))
+}
+
 func emphCharName(ch byte) string {
 	if ch == '*' {
 		return "asterisk"
@@ -372,7 +372,7 @@ func TestCheckConsistentEmphasisStyle(t *testing.T) {
 				t.Fatalf("got %d errors, want %d\ngot:  %v\nwant: %v", len(got), len(tt.wantErrs), got, tt.wantErrs)
 			}
 			for i := range got {
-				if got[i] != tt.wantErrs[i] {
+				if withoutSpan(got[i]) != tt.wantErrs[i] {
 					t.Errorf("error[%d]: got %+v, want %+v", i, got[i], tt.wantErrs[i])
 				}
 			}
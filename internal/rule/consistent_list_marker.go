@@ -1,6 +1,8 @@
 package rule
 
 import (
+	"strings"
+
 	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
 )
 
@@ -25,7 +27,14 @@ func CheckConsistentListMarker(filename string, ctx *preprocess.Context, offset
 		}
 
 		if err := checkListMarkerStyle(filename, offset+i+1, ch, style, &expectedCh); err != nil {
-			errs = append(errs, *err)
+			start := strings.IndexByte(line, ch)
+			e := atSpan(*err, line, start, start+1)
+			// "* * *" is a thematic break that listItemMarker also accepts;
+			// rewriting only its first marker would turn it into a list item.
+			if !isThematicBreak(line) {
+				e = withFix(e, replaceBytes(e.Line, line, start, start+1, string(wantListMarker(style, expectedCh))))
+			}
+			errs = append(errs, e)
 		}
 	}
 
@@ -99,6 +108,39 @@ func checkListMarkerStyle(filename string, line int, ch byte, style string, expe
 	return nil
 }
 
+// wantListMarker returns the marker a violating item should be rewritten to.
+func wantListMarker(style string, expectedCh byte) byte {
+	switch style {
+	case "dash":
+		return '-'
+	case "asterisk":
+		return '*'
+	case "plus":
+		return '+'
+	}
+	return expectedCh
+}
+
+// isThematicBreak reports whether line is three or more '-', '*' or '_'
+// characters of one kind, optionally separated by spaces or tabs.
+func isThematicBreak(line string) bool {
+	s := strings.TrimSpace(line)
+	if s == "" || (s[0] != '-' && s[0] != '*' && s[0] != '_') {
+		return false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case s[0]:
+			n++
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
 func listMarkerName(ch byte) string {
 	switch ch {
 	case '-':
@@ -0,0 +1,197 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// Scopes of a pattern rule: the text its pattern is matched against.
+const (
+	ScopeText    = "text"     // prose outside code and HTML blocks, inline code blanked
+	ScopeCode    = "code"     // code block contents and inline code spans
+	ScopeHeading = "heading"  // the text of ATX and setext headings
+	ScopeLinkURL = "link-url" // link and image destinations, autolinks and bare URLs
+)
+
+var (
+	linkDestPattern  = regexp.MustCompile(`\]\([ \t]*(?:<([^<>]*)>|([^\s()]+(?:\([^\s()]*\)[^\s()]*)*))`)
+	refDefURLPattern = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]*(?:<([^<>]*)>|(\S+))`)
+	autolinkPattern  = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+)
+
+// PatternRule reports every match of a regular expression declared under
+// "custom-rules" in the config.
+type PatternRule struct {
+	name    string
+	message string
+	scope   string
+	re      *regexp.Regexp
+}
+
+// NewPatternRule validates c and returns the rule it declares.
+func NewPatternRule(name string, c config.CustomRuleConfig) (*PatternRule, error) {
+	scope := c.Scope
+	if scope == "" {
+		scope = ScopeText
+	}
+	switch scope {
+	case ScopeText, ScopeCode, ScopeHeading, ScopeLinkURL:
+	default:
+		return nil, fmt.Errorf("invalid scope %q (valid scopes: %s, %s, %s, %s)", c.Scope, ScopeText, ScopeCode, ScopeHeading, ScopeLinkURL)
+	}
+	if c.Pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+	re, err := regexp.Compile(c.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return &PatternRule{name: name, message: c.Message, scope: scope, re: re}, nil
+}
+
+func (r *PatternRule) Name() string                         { return r.name }
+func (r *PatternRule) DefaultSeverity() config.RuleSeverity { return config.SeverityError }
+func (r *PatternRule) Options() []Option                    { return nil }
+
+func (r *PatternRule) Check(path string, ctx *preprocess.Context, offset int, _ Options) []LintError {
+	var errs []LintError
+	var fenceLines map[int]bool
+	if r.scope == ScopeCode {
+		fenceLines = fenceDelimiterLines(ctx)
+	}
+	for i := 0; i < ctx.Len(); i++ {
+		line := ctx.Line(i)
+		for _, sp := range r.targets(ctx, i, fenceLines) {
+			for _, m := range r.re.FindAllStringIndex(sp.text, -1) {
+				if m[0] == m[1] {
+					continue
+				}
+				start, end := sp.start+m[0], sp.start+m[1]
+				e := atSpan(LintError{File: path, Line: offset + i + 1}, line, start, end)
+				e.Message = r.name + ": " + r.messageFor(line[start:end])
+				errs = append(errs, e)
+			}
+		}
+	}
+	return errs
+}
+
+func (r *PatternRule) messageFor(match string) string {
+	if r.message != "" {
+		return r.message
+	}
+	return fmt.Sprintf("found %q", match)
+}
+
+// targets returns the parts of line i the pattern is matched against. Their
+// byte offsets are offsets into ctx.Line(i).
+func (r *PatternRule) targets(ctx *preprocess.Context, i int, fenceLines map[int]bool) []textSpan {
+	if r.scope == ScopeCode {
+		return codeTargets(ctx, i, fenceLines)
+	}
+	if inBlockContext(ctx, i) {
+		return nil
+	}
+	line := ctx.Sanitized(i)
+	switch r.scope {
+	case ScopeHeading:
+		return headingTargets(ctx, i, line)
+	case ScopeLinkURL:
+		return linkURLTargets(line)
+	}
+	return []textSpan{{text: line, start: 0, end: len(line)}}
+}
+
+// fenceDelimiterLines returns the opening and closing lines of every fenced
+// code block, which belong to the block but hold no code.
+func fenceDelimiterLines(ctx *preprocess.Context) map[int]bool {
+	lines := map[int]bool{}
+	for _, f := range ctx.FenceSpans() {
+		lines[f.Start] = true
+		if f.End >= 0 {
+			lines[f.End] = true
+		}
+	}
+	return lines
+}
+
+func codeTargets(ctx *preprocess.Context, i int, fenceLines map[int]bool) []textSpan {
+	line := ctx.Line(i)
+	switch {
+	case ctx.InFencedCode(i):
+		if fenceLines[i] {
+			return nil
+		}
+		return []textSpan{{text: line, start: 0, end: len(line)}}
+	case ctx.InIndentedCode(i):
+		return []textSpan{{text: line, start: 0, end: len(line)}}
+	case ctx.InHTMLBlock(i) || ctx.InHTMLComment(i):
+		return nil
+	}
+	var targets []textSpan
+	for _, sp := range inlineCodeSpans(line) {
+		delimLen := (sp.end - sp.start - len(sp.text)) / 2
+		start := sp.start + delimLen
+		targets = append(targets, textSpan{text: sp.text, start: start, end: start + len(sp.text)})
+	}
+	return targets
+}
+
+func headingTargets(ctx *preprocess.Context, i int, line string) []textSpan {
+	if firstNonSpaceByte(line) == '#' {
+		text, ok := atxHeadingText(strings.TrimSpace(line))
+		if !ok || text == "" {
+			return nil
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		start := len(line) - len(trimmed) + atxHeadingLevel(strings.TrimSpace(line))
+		start += len(line[start:]) - len(strings.TrimLeft(line[start:], " \t"))
+		return []textSpan{{text: text, start: start, end: start + len(text)}}
+	}
+	if isSetextText(ctx, i, line) {
+		start, end := contentSpan(line)
+		return []textSpan{{text: line[start:end], start: start, end: end}}
+	}
+	return nil
+}
+
+// isSetextText reports whether line i is the last line of a setext
+// heading's text.
+func isSetextText(ctx *preprocess.Context, i int, line string) bool {
+	if i+1 >= ctx.Len() || inBlockContext(ctx, i+1) || strings.TrimSpace(line) == "" {
+		return false
+	}
+	return setextUnderlineRegex.MatchString(ctx.Line(i+1)) && !setextOtherBlockRegex.MatchString(line)
+}
+
+// linkURLTargets returns the URLs in line, ordered by position.
+func linkURLTargets(line string) []textSpan {
+	seen := map[int]bool{}
+	var targets []textSpan
+	add := func(sp textSpan) {
+		if !seen[sp.start] {
+			seen[sp.start] = true
+			targets = append(targets, sp)
+		}
+	}
+	for _, re := range []*regexp.Regexp{linkDestPattern, refDefURLPattern, autolinkPattern} {
+		for _, m := range re.FindAllStringSubmatchIndex(line, -1) {
+			for g := 2; g+1 < len(m); g += 2 {
+				if m[g] >= 0 && m[g] < m[g+1] {
+					add(textSpan{text: line[m[g]:m[g+1]], start: m[g], end: m[g+1]})
+				}
+			}
+		}
+	}
+	for _, sp := range findBareURLs(line) {
+		add(sp)
+	}
+	sort.Slice(targets, func(a, b int) bool { return targets[a].start < targets[b].start })
+	return targets
+}
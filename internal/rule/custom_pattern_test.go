@@ -0,0 +1,154 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// patternPos is the line and columns of one pattern rule match.
+type patternPos struct {
+	line, column, endColumn int
+}
+
+func TestPatternRule_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		scope   string
+		content string
+		want    []patternPos
+	}{
+		{
+			name:    "text: matches prose and headings",
+			pattern: `corp\.internal`,
+			content: "## See corp.internal\n\nVisit corp.internal now.\n",
+			want:    []patternPos{{1, 8, 21}, {3, 7, 20}},
+		},
+		{
+			name:    "text: skips code blocks, inline code and comments",
+			pattern: `foo`,
+			content: "Use `foo` here. <!-- foo -->\n\n```\nfoo\n```\n\n    foo\n\n<div>\nfoo\n</div>\n",
+			want:    nil,
+		},
+		{
+			name:    "text: every match on a line",
+			pattern: `(?i)todo`,
+			content: "TODO and todo\n",
+			want:    []patternPos{{1, 1, 5}, {1, 10, 14}},
+		},
+		{
+			name:    "code: fenced, indented and inline code only",
+			scope:   "code",
+			pattern: `rm -rf`,
+			content: "Never rm -rf in prose, but `rm -rf /` here.\n\n```sh rm -rf\nrm -rf /tmp\n```\n\n    rm -rf x\n",
+			want:    []patternPos{{1, 29, 35}, {4, 1, 7}, {7, 5, 11}},
+		},
+		{
+			name:    "heading: ATX and setext heading text",
+			scope:   "heading",
+			pattern: `^Intro`,
+			content: "## Intro ##\n\nIntro paragraph.\n\nIntro Two\n---------\n",
+			want:    []patternPos{{1, 4, 9}, {5, 1, 6}},
+		},
+		{
+			name:    "heading: list item above dashes is not a heading",
+			scope:   "heading",
+			pattern: `Intro`,
+			content: "- Intro\n---\n",
+			want:    nil,
+		},
+		{
+			name:    "link-url: destinations, definitions, autolinks and bare URLs",
+			scope:   "link-url",
+			pattern: `corp\.internal`,
+			content: "[a](https://corp.internal/x) ![b](<http://corp.internal>)\n<https://corp.internal> and https://corp.internal/y\n\n[ref]: https://corp.internal/z\n",
+			want:    []patternPos{{1, 13, 26}, {1, 43, 56}, {2, 10, 23}, {2, 37, 50}, {4, 16, 29}},
+		},
+		{
+			name:    "link-url: link text is not a URL",
+			scope:   "link-url",
+			pattern: `corp\.internal`,
+			content: "[corp.internal](https://example.com)\n",
+			want:    nil,
+		},
+		{
+			name:    "empty matches are ignored",
+			pattern: `x*`,
+			content: "abc\n",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewPatternRule("house", config.CustomRuleConfig{Pattern: tt.pattern, Scope: tt.scope})
+			if err != nil {
+				t.Fatalf("NewPatternRule: %v", err)
+			}
+			errs := r.Check("test.md", preprocess.Scan(strings.Split(tt.content, "\n")), 0, nil)
+			var got []patternPos
+			for _, e := range errs {
+				got = append(got, patternPos{e.Line, e.Column, e.EndColumn})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("match %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPatternRule_Message(t *testing.T) {
+	ctx := preprocess.Scan([]string{"intro", "", "see corp.internal"})
+
+	r, _ := NewPatternRule("no-internal-hosts", config.CustomRuleConfig{Pattern: `corp\.\w+`, Message: "use the public hostname"})
+	errs := r.Check("test.md", ctx, 3, nil)
+	if len(errs) != 1 || errs[0].Line != 6 || errs[0].Message != "no-internal-hosts: use the public hostname" {
+		t.Errorf("unexpected errors: %+v", errs)
+	}
+
+	r, _ = NewPatternRule("no-internal-hosts", config.CustomRuleConfig{Pattern: `corp\.\w+`})
+	errs = r.Check("test.md", ctx, 0, nil)
+	if len(errs) != 1 || errs[0].Message != `no-internal-hosts: found "corp.internal"` {
+		t.Errorf("unexpected errors: %+v", errs)
+	}
+}
+
+func TestNewPatternRule_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.CustomRuleConfig
+		want string
+	}{
+		{"missing pattern", config.CustomRuleConfig{}, "pattern is required"},
+		{"bad pattern", config.CustomRuleConfig{Pattern: "("}, "invalid pattern: error parsing regexp: missing closing ): `(`"},
+		{"bad scope", config.CustomRuleConfig{Pattern: "x", Scope: "body"}, `invalid scope "body" (valid scopes: text, code, heading, link-url)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPatternRule("house", tt.cfg)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineCodeSpans(t *testing.T) {
+	got := inlineCodeSpans("a `b` ``c ` d`` `unclosed")
+	want := []textSpan{{text: "b", start: 2, end: 5}, {text: "c ` d", start: 6, end: 15}}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("span %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
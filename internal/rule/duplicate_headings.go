@@ -31,11 +31,11 @@ func CheckDuplicateHeadings(filename string, ctx *preprocess.Context, offset int
 		normalized := strings.ToLower(heading)
 
 		if _, ok := seen[normalized]; ok {
-			errs = append(errs, LintError{
+			errs = append(errs, atContent(LintError{
 				File:    filename,
 				Line:    i + 1 + offset,
 				Message: fmt.Sprintf("duplicate heading: %q", normalized),
-			})
+			}, line))
 		} else {
 			seen[normalized] = struct{}{}
 		}
@@ -20,12 +20,12 @@ func CheckEmptyAltText(filename string, ctx *preprocess.Context, offset int) []L
 		if !strings.Contains(line, "![") {
 			continue
 		}
-		if emptyAltTextRe.MatchString(line) {
-			errs = append(errs, LintError{
+		if loc := emptyAltTextRe.FindStringIndex(line); loc != nil {
+			errs = append(errs, atSpan(LintError{
 				File:    filename,
 				Line:    i + 1 + offset,
 				Message: "image with empty alt text",
-			})
+			}, ctx.Line(i), loc[0], loc[1]))
 		}
 	}
 
@@ -1,10 +1,14 @@
 package rule
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,8 +22,56 @@ var (
 )
 
 type cacheResult struct {
-	status int
-	err    error
+	status    int
+	err       error
+	fragment  string // fragmentFound or fragmentMissing once looked for
+	checkedAt time.Time
+}
+
+// CachedLink is the result of checking one URL, in the form kept between
+// runs.
+type CachedLink struct {
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Fragment  string    `json:"fragment,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Failed reports whether the check got no response or an error status, or
+// did not find the URL's fragment.
+func (c CachedLink) Failed() bool {
+	return c.Error != "" || c.Status >= 400 || c.Fragment == fragmentMissing
+}
+
+// PreloadLinks stores links in urlCache, so a LinkChecker using it reuses
+// them instead of requesting the URLs again.
+func PreloadLinks(urlCache *sync.Map, links map[string]CachedLink) {
+	for url, l := range links {
+		var err error
+		if l.Error != "" {
+			err = errors.New(l.Error)
+		}
+		urlCache.Store(url, cacheResult{status: l.Status, err: err, fragment: l.Fragment, checkedAt: l.CheckedAt})
+	}
+}
+
+// CheckedLinks returns the results held in urlCache, preloaded or checked.
+func CheckedLinks(urlCache *sync.Map) map[string]CachedLink {
+	links := map[string]CachedLink{}
+	urlCache.Range(func(k, v interface{}) bool {
+		url, ok := k.(string)
+		result, ok2 := v.(cacheResult)
+		if !ok || !ok2 {
+			return true
+		}
+		l := CachedLink{Status: result.status, Fragment: result.fragment, CheckedAt: result.checkedAt}
+		if result.err != nil {
+			l.Error = result.err.Error()
+		}
+		links[url] = l
+		return true
+	})
+	return links
 }
 
 type ExtractedLink struct {
@@ -28,21 +80,26 @@ type ExtractedLink struct {
 }
 
 const (
-	DefaultRetryDelayMs        = 1000
-	DefaultMaxConcurrency      = 10
-	MaxConcurrencyLimit        = 15
-	DefaultMaxRetries          = 2
-	MaxRetriesLimit            = 4
-	DefaultPerHostConcurrency  = 2
-	MaxPerHostConcurrencyLimit = 15
-	DefaultPerHostIntervalMs   = 3000
-	MinPerHostIntervalMs       = 1000
-	MaxPerHostIntervalMsLimit  = 60000
+	DefaultRetryDelayMs         = 1000
+	DefaultMaxConcurrency       = 10
+	MaxConcurrencyLimit         = 15
+	DefaultMaxRetries           = 2
+	MaxRetriesLimit             = 4
+	DefaultPerHostConcurrency   = 2
+	MaxPerHostConcurrencyLimit  = 15
+	DefaultPerHostIntervalMs    = 3000
+	MinPerHostIntervalMs        = 1000
+	MaxPerHostIntervalMsLimit   = 60000
+	DefaultMaxRetryAfterSeconds = 60
+	MaxRetryAfterSecondsLimit   = 600
+	DefaultLinkCacheTTL         = "24h"
+	DefaultLinkFailureCacheTTL  = "0"
 
 	userAgent = "gomarklint/v3 (+https://github.com/shinagawa-web/gomarklint)"
 )
 
-// 429 (Too Many Requests) is rate limiting, not a broken link.
+// 429 (Too Many Requests) is rate limiting, not a broken link, when it is
+// still the answer after checkURL has retried and waited as long as asked.
 var defaultAllowedStatuses = []int{http.StatusTooManyRequests}
 
 func isAllowedStatus(status int, extra []int) bool {
@@ -70,24 +127,37 @@ func (h *hostLimiter) acquire() {
 	if h.sem != nil {
 		h.sem <- struct{}{}
 	}
-	if h.interval <= 0 {
-		return
-	}
+	h.turn()
+}
+
+// turn waits until the host may be requested again: until the interval
+// since the last request has passed, and any pause has ended.
+func (h *hostLimiter) turn() {
 	h.mu.Lock()
 	now := time.Now()
-	var wait time.Duration
+	start := now
 	if h.nextAvail.After(now) {
-		wait = h.nextAvail.Sub(now)
-		h.nextAvail = h.nextAvail.Add(h.interval)
-	} else {
-		h.nextAvail = now.Add(h.interval)
+		start = h.nextAvail
+	}
+	if h.interval > 0 {
+		h.nextAvail = start.Add(h.interval)
 	}
 	h.mu.Unlock()
-	if wait > 0 {
+	if wait := start.Sub(now); wait > 0 {
 		time.Sleep(wait)
 	}
 }
 
+// pause holds back every request to the host for d, as a Retry-After
+// header asks.
+func (h *hostLimiter) pause(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if until := time.Now().Add(d); until.After(h.nextAvail) {
+		h.nextAvail = until
+	}
+}
+
 func (h *hostLimiter) release() {
 	if h.sem != nil {
 		<-h.sem
@@ -131,14 +201,29 @@ func extractHost(rawURL string) string {
 	return u.Host
 }
 
+// linkSpan is an ExtractedLink together with its 1-based character columns.
+type linkSpan struct {
+	ExtractedLink
+	column    int
+	endColumn int
+}
+
 func ExtractExternalLinksWithLineNumbers(ctx *preprocess.Context, offset int) []ExtractedLink {
+	var results []ExtractedLink
+	for _, s := range extractExternalLinks(ctx, offset) {
+		results = append(results, s.ExtractedLink)
+	}
+	return results
+}
+
+func extractExternalLinks(ctx *preprocess.Context, offset int) []linkSpan {
 	patterns := []*regexp.Regexp{
 		inlineLinkPattern,
 		imageLinkPattern,
 		bareURLPattern,
 	}
 
-	var results []ExtractedLink
+	var results []linkSpan
 
 	for i := 0; i < ctx.Len(); i++ {
 		// Skip code/HTML block contexts entirely, and scan the inline-sanitized
@@ -150,15 +235,16 @@ func ExtractExternalLinksWithLineNumbers(ctx *preprocess.Context, offset int) []
 		line := ctx.Sanitized(i)
 		seenInLine := make(map[string]bool) // Track URLs found in this line
 		for _, re := range patterns {
-			matches := re.FindAllStringSubmatch(line, -1)
+			matches := re.FindAllStringSubmatchIndex(line, -1)
 			for _, match := range matches {
-				if len(match) > 1 {
-					url := match[1]
+				if len(match) > 3 {
+					url := line[match[2]:match[3]]
 					// Only add if not already seen in this line
 					if !seenInLine[url] {
-						results = append(results, ExtractedLink{
-							URL:  url,
-							Line: i + 1 + offset,
+						results = append(results, linkSpan{
+							ExtractedLink: ExtractedLink{URL: url, Line: i + 1 + offset},
+							column:        charColumn(ctx.Line(i), match[2]),
+							endColumn:     charColumn(ctx.Line(i), match[3]),
 						})
 						seenInLine[url] = true
 					}
@@ -169,108 +255,81 @@ func ExtractExternalLinksWithLineNumbers(ctx *preprocess.Context, offset int) []
 	return results
 }
 
-func CheckExternalLinks(path string, ctx *preprocess.Context, offset int, skipPatterns []*regexp.Regexp, timeoutSeconds int, retryDelayMs int, maxConcurrency int, maxRetries int, allowedStatuses []int, urlCache *sync.Map, perHostConcurrency int, perHostIntervalMs int) ([]LintError, int) {
-	links := ExtractExternalLinksWithLineNumbers(ctx, offset)
+// retryPolicy is how checkURL retries a URL.
+type retryPolicy struct {
+	delayMs         int // the first backoff delay, doubled on each retry
+	maxRetries      int
+	maxRetryAfter   time.Duration // the longest Retry-After wait; 0 ignores the header
+	allowedStatuses []int
+}
 
-	urlToLines := make(map[string][]int)
-	for _, link := range links {
-		if shouldSkipLink(link.URL, skipPatterns) {
-			continue
+// checkURL requests url until it gets a definite answer or runs out of
+// retries. A 429 or 503 response with a Retry-After header pauses lim, the
+// URL's host, for the time asked, up to p.maxRetryAfter, so other requests
+// to the host wait too; without the header, the retry backs off
+// exponentially. A 429 still returned after the last retry is allowed as
+// rate limiting, unless its Retry-After asks for longer than p.maxRetryAfter:
+// the wait would have been cut short, so the link is reported broken. A 429
+// without the header, or with p.maxRetryAfter 0, is allowed once the backoff
+// is spent.
+//
+// sem, when not nil, is the global concurrency slot the caller holds. It is
+// given up while the host is paused and taken again afterwards, so one
+// rate-limited host does not hold up requests to the others.
+func checkURL(client *http.Client, url string, p retryPolicy, lim *hostLimiter, sem chan struct{}) (int, error) {
+	retryDelay := time.Duration(p.delayMs) * time.Millisecond
+
+	for i := 0; ; i++ {
+		status, retryAfter, err := performCheck(client, url)
+		if i == p.maxRetries || !shouldRetry(status, err, p.allowedStatuses) {
+			if status == http.StatusTooManyRequests && p.maxRetryAfter > 0 && retryAfter > p.maxRetryAfter &&
+				!slices.Contains(p.allowedStatuses, status) {
+				return status, fmt.Errorf("rate limited for %v, longer than maxRetryAfterSeconds", retryAfter)
+			}
+			return status, err
 		}
-		urlToLines[link.URL] = append(urlToLines[link.URL], link.Line)
-	}
-
-	var errs []LintError
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	sem := make(chan struct{}, maxConcurrency)
-
-	client := &http.Client{
-		Timeout: time.Duration(timeoutSeconds) * time.Second,
-	}
-
-	hostReg := newHostLimiterRegistry(perHostConcurrency, perHostIntervalMs)
-
-	for u, lines := range urlToLines {
-		wg.Add(1)
-		sem <- struct{}{}
-		go func(url string, lns []int) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			var status int
-			var err error
-
-			needHTTP := true
-			if cached, ok := urlCache.Load(url); ok {
-				if result, ok := cached.(cacheResult); ok {
-					status = result.status
-					err = result.err
-					needHTTP = false
+		wait := retryDelay * time.Duration(1<<uint(i))
+		if retryAfter > 0 && p.maxRetryAfter > 0 {
+			wait = min(retryAfter, p.maxRetryAfter)
+			if lim != nil {
+				lim.pause(wait)
+				if sem != nil {
+					<-sem
 				}
-			}
-
-			if needHTTP {
-				lim := hostReg.get(extractHost(url))
-				lim.acquire()
-				defer lim.release()
-				status, err = checkURL(client, url, retryDelayMs, maxRetries, allowedStatuses)
-				urlCache.Store(url, cacheResult{status: status, err: err})
-			}
-
-			if err != nil || (status >= 400 && !isAllowedStatus(status, allowedStatuses)) {
-				mu.Lock()
-				for _, line := range lns {
-					errs = append(errs, LintError{
-						File:    path,
-						Line:    line,
-						Message: formatLinkError(url),
-					})
+				lim.turn()
+				if sem != nil {
+					sem <- struct{}{}
 				}
-				mu.Unlock()
+				continue
 			}
-		}(u, lines)
+		}
+		time.Sleep(wait)
 	}
-
-	wg.Wait()
-	return errs, len(urlToLines)
 }
 
-func checkURL(client *http.Client, url string, retryDelayMs int, maxRetries int, allowedStatuses []int) (int, error) {
-	retryDelay := time.Duration(retryDelayMs) * time.Millisecond
-
-	var status int
-	var err error
-
-	for i := 0; i <= maxRetries; i++ {
-		if i > 0 {
-			time.Sleep(retryDelay * time.Duration(1<<uint(i-1)))
-		}
-
-		status, err = performCheck(client, url)
-
-		if err == nil && status < 400 {
-			return status, nil
-		}
-		if err == nil && isAllowedStatus(status, allowedStatuses) {
-			return status, nil
-		}
-		if err == nil && (status == http.StatusNotFound || status == http.StatusUnauthorized) {
-			return status, nil
-		}
-		if i == maxRetries {
-			break
-		}
+// shouldRetry reports whether a response may change on a retry: network
+// errors, rate limiting and server errors, but not statuses the link is
+// allowed, or 404 and 401.
+func shouldRetry(status int, err error, allowedStatuses []int) bool {
+	switch {
+	case err != nil:
+		return true
+	case status < 400:
+		return false
+	case status == http.StatusTooManyRequests:
+		return true
+	case isAllowedStatus(status, allowedStatuses):
+		return false
 	}
-
-	return status, err
+	return status != http.StatusNotFound && status != http.StatusUnauthorized
 }
 
-func performCheck(client *http.Client, url string) (int, error) {
+// performCheck requests url and returns the status, and the wait asked for
+// by a Retry-After header on a 429 or 503 response.
+func performCheck(client *http.Client, url string) (int, time.Duration, error) {
 	req, err := http.NewRequest("HEAD", url, nil)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	req.Header.Set("User-Agent", userAgent)
 
@@ -280,7 +339,7 @@ func performCheck(client *http.Client, url string) (int, error) {
 		// Some servers reject HEAD with 405 (Method Not Allowed) or 403 (Forbidden)
 		// but serve GET normally. Fall back to GET in those cases.
 		if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusForbidden {
-			return resp.StatusCode, nil
+			return resp.StatusCode, retryAfter(resp), nil
 		}
 	}
 
@@ -289,18 +348,49 @@ func performCheck(client *http.Client, url string) (int, error) {
 
 	resp, err = client.Do(req)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
-	return resp.StatusCode, nil
+	return resp.StatusCode, retryAfter(resp), nil
+}
+
+// retryAfter returns the wait asked for by the Retry-After header of a 429
+// or 503 response, given in seconds or as an HTTP date, or 0 if there is
+// none.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	return parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+}
+
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
 }
 
 func formatLinkError(url string) string {
 	return fmt.Sprintf("Link unreachable: %s", url)
 }
 
+func formatFragmentError(url string) string {
+	return fmt.Sprintf("Link fragment not found: %s", url)
+}
+
 func shouldSkipLink(url string, skipPatterns []*regexp.Regexp) bool {
 	for _, re := range skipPatterns {
 		if re.MatchString(url) {
@@ -0,0 +1,231 @@
+package rule
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// The outcomes of looking for a URL's fragment with checkFragments.
+// fragmentUnknown is for pages whose anchors cannot be read, such as PDFs
+// or code rendered by scripts, and is not reported.
+const (
+	fragmentFound   = "found"
+	fragmentMissing = "missing"
+	fragmentUnknown = "unknown"
+)
+
+// fragmentBodyLimit is the most of a page read when looking for anchors. A
+// fragment not found in a longer page is not reported.
+const fragmentBodyLimit = 5 << 20
+
+var (
+	reHTMLTag    = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+	reIDAttr     = regexp.MustCompile(`(?i)\sid\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	reNameAttr   = regexp.MustCompile(`(?i)\sname\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	reLineAnchor = regexp.MustCompile(`^L\d+(?:C\d+)?(?:-L?\d+(?:C\d+)?)?$`)
+)
+
+// pageAnchors is the anchors of a page, fetched once however many links
+// point into it. complete is false when the page could not be read in full,
+// or is of a type without anchors gomarklint can read, so a fragment not
+// among anchors may still exist.
+type pageAnchors struct {
+	once     sync.Once
+	anchors  map[string]bool
+	complete bool
+}
+
+// splitFragment splits a URL whose fragment names an anchor into the URL
+// without it and the decoded fragment. Empty fragments, "top", hashbang
+// routes ("#!/path") and text fragments ("#:~:text=") name none.
+func splitFragment(rawURL string) (string, string, bool) {
+	base, fragment, ok := strings.Cut(rawURL, "#")
+	if !ok {
+		return "", "", false
+	}
+	if decoded, err := url.PathUnescape(fragment); err == nil {
+		fragment = decoded
+	}
+	fragment, _, _ = strings.Cut(fragment, ":~:")
+	if fragment == "" || fragment == "top" || strings.HasPrefix(fragment, "!") {
+		return "", "", false
+	}
+	return base, fragment, true
+}
+
+// checkFragment checks base, the URL without its fragment, like any other
+// link, then looks for fragment among the anchors of the page.
+func (c *LinkChecker) checkFragment(base, fragment string) cacheResult {
+	c.check(base)
+	result := cacheResult{fragment: fragmentUnknown}
+	if r := c.result(base); r != nil {
+		result = *r
+		result.fragment = fragmentUnknown
+	}
+	result.checkedAt = time.Now()
+	if result.err != nil || result.status >= 400 {
+		return result
+	}
+	page := c.anchorsOf(base, fragment)
+	switch {
+	case page.anchors[fragment]:
+		result.fragment = fragmentFound
+	case page.complete:
+		result.fragment = fragmentMissing
+	}
+	return result
+}
+
+func (c *LinkChecker) anchorsOf(base, fragment string) *pageAnchors {
+	raw, source := hostedSource(base)
+	if source && (raw == "" || reLineAnchor.MatchString(fragment)) {
+		// Code pages are rendered by scripts, and line anchors are not
+		// headings.
+		return &pageAnchors{}
+	}
+	v, _ := c.pages.LoadOrStore(base, &pageAnchors{})
+	page := v.(*pageAnchors)
+	page.once.Do(func() {
+		if raw != "" {
+			page.anchors, page.complete = c.fetchAnchors(raw, true)
+		} else {
+			page.anchors, page.complete = c.fetchAnchors(base, false)
+		}
+	})
+	return page
+}
+
+// fetchAnchors reads the anchors of the page at target: the headings of
+// Markdown, slugged with the rule's slug-algorithm, or the ids and <a> names
+// of HTML.
+func (c *LinkChecker) fetchAnchors(target string, markdown bool) (map[string]bool, bool) {
+	_, release := c.throttle(target)
+	defer release()
+	body, contentType, complete, err := fetchBody(c.client, target)
+	if err != nil {
+		return nil, false
+	}
+	switch {
+	case markdown:
+		return markdownAnchors(body, c.slugger), complete
+	case isHTML(contentType):
+		return htmlAnchors(body), complete
+	}
+	return nil, false
+}
+
+// hostedSource reports whether rawURL shows a file in a repository on
+// GitHub or GitLab. For a Markdown file, it also returns the URL of the raw
+// file: the pages build their heading anchors with scripts, so the headings
+// are read from the file instead.
+func hostedSource(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	path := u.EscapedPath()
+	markdown := strings.HasSuffix(strings.ToLower(u.Path), ".md") || strings.HasSuffix(strings.ToLower(u.Path), ".markdown")
+	switch {
+	case u.Host == "github.com":
+		// /owner/repo/blob/ref/path
+		parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 4)
+		if len(parts) < 4 || parts[2] != "blob" {
+			return "", false
+		}
+		if !markdown {
+			return "", true
+		}
+		return "https://raw.githubusercontent.com/" + parts[0] + "/" + parts[1] + "/" + parts[3], true
+	case (u.Host == "gitlab.com" || strings.HasPrefix(u.Host, "gitlab.")) && strings.Contains(path, "/-/blob/"):
+		if !markdown {
+			return "", true
+		}
+		raw := *u
+		raw.RawPath = ""
+		raw.Path = strings.Replace(u.Path, "/-/blob/", "/-/raw/", 1)
+		raw.RawQuery = ""
+		raw.Fragment = ""
+		return raw.String(), true
+	}
+	return "", false
+}
+
+// fetchBody GETs target and returns up to fragmentBodyLimit bytes of it,
+// its content type, and whether that is all of it.
+func fetchBody(client *http.Client, target string) ([]byte, string, bool, error) {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode >= 400 {
+		return nil, "", false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, fragmentBodyLimit+1))
+	if err != nil {
+		return nil, "", false, err
+	}
+	if len(body) > fragmentBodyLimit {
+		return body[:fragmentBodyLimit], resp.Header.Get("Content-Type"), false, nil
+	}
+	return body, resp.Header.Get("Content-Type"), true, nil
+}
+
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// htmlAnchors returns the id attributes, and the names of <a> elements, in
+// body.
+func htmlAnchors(body []byte) map[string]bool {
+	anchors := map[string]bool{}
+	add := func(m [][]byte) {
+		v := html.UnescapeString(string(m[1]) + string(m[2]) + string(m[3]))
+		anchors[v] = true
+		// GitHub renders a Markdown heading's anchor as "user-content-<slug>"
+		// and links to it as the slug.
+		if slug, ok := strings.CutPrefix(v, "user-content-"); ok {
+			anchors[slug] = true
+		}
+	}
+	for _, tag := range reHTMLTag.FindAll(body, -1) {
+		for _, m := range reIDAttr.FindAllSubmatch(tag, -1) {
+			add(m)
+		}
+		if len(tag) > 2 && (tag[1] == 'a' || tag[1] == 'A') && (tag[2] == ' ' || tag[2] == '\t' || tag[2] == '\n') {
+			for _, m := range reNameAttr.FindAllSubmatch(tag, -1) {
+				add(m)
+			}
+		}
+	}
+	return anchors
+}
+
+// markdownAnchors returns the heading slugs of a Markdown file, made by
+// slugger, and the anchors of any HTML in it.
+func markdownAnchors(body []byte, slugger func(string) string) map[string]bool {
+	anchors := htmlAnchors(body)
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	for slug := range collectHeadingSlugs(preprocess.Scan(lines), slugger) {
+		anchors[slug] = true
+	}
+	return anchors
+}
@@ -0,0 +1,97 @@
+package rule
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_splitFragment(t *testing.T) {
+	tests := []struct {
+		url      string
+		base     string
+		fragment string
+		ok       bool
+	}{
+		{"https://example.com/docs#install", "https://example.com/docs", "install", true},
+		{"https://example.com/docs#caf%C3%A9", "https://example.com/docs", "café", true},
+		{"https://example.com/docs#install:~:text=go", "https://example.com/docs", "install", true},
+		{"https://example.com/docs", "", "", false},
+		{"https://example.com/docs#", "", "", false},
+		{"https://example.com/docs#top", "", "", false},
+		{"https://example.com/app#!/settings", "", "", false},
+		{"https://example.com/docs#:~:text=go", "", "", false},
+	}
+	for _, tt := range tests {
+		base, fragment, ok := splitFragment(tt.url)
+		if base != tt.base || fragment != tt.fragment || ok != tt.ok {
+			t.Errorf("splitFragment(%q) = %q, %q, %v, want %q, %q, %v", tt.url, base, fragment, ok, tt.base, tt.fragment, tt.ok)
+		}
+	}
+}
+
+func Test_htmlAnchors(t *testing.T) {
+	body := `<html><head><meta name="viewport" content="x"></head><body>
+<h2 id="install">Install</h2>
+<h2 ID='usage'>Usage</h2>
+<div id=plain class="x"></div>
+<a name="legacy"></a>
+<a href="#x" data-id="not-an-anchor">x</a>
+<h3 id="user-content-api-reference">API</h3>
+<span id="a&amp;b"></span>
+</body></html>`
+	got := htmlAnchors([]byte(body))
+	want := map[string]bool{
+		"install": true, "usage": true, "plain": true, "legacy": true,
+		"user-content-api-reference": true, "api-reference": true, "a&b": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func Test_markdownAnchors(t *testing.T) {
+	body := "# Getting Started\r\n\r\n## Install\n\n## Install\n\n```\n# not a heading\n```\n\n<a id=\"custom\"></a>\n"
+	got := markdownAnchors([]byte(body), makeSlugger("github", nil))
+	want := map[string]bool{"getting-started": true, "install": true, "install-1": true, "custom": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNewLinkChecker_SlugAlgorithm(t *testing.T) {
+	// Hosted Markdown is slugged with the configured algorithm, whatever
+	// the host.
+	c := NewLinkChecker(Options{"slug-algorithm": "gitlab"}, &sync.Map{})
+	got := markdownAnchors([]byte("## A  B\n"), c.slugger)
+	if !got["a-b"] || got["a--b"] {
+		t.Errorf("expected the gitlab slug, got %v", got)
+	}
+	c = NewLinkChecker(Options{}, &sync.Map{})
+	if got := markdownAnchors([]byte("## A  B\n"), c.slugger); !got["a--b"] {
+		t.Errorf("expected the github slug by default, got %v", got)
+	}
+}
+
+func Test_hostedSource(t *testing.T) {
+	tests := []struct {
+		url    string
+		raw    string
+		source bool
+	}{
+		{"https://github.com/o/r/blob/main/docs/guide.md", "https://raw.githubusercontent.com/o/r/main/docs/guide.md", true},
+		{"https://github.com/o/r/blob/main/README.markdown", "https://raw.githubusercontent.com/o/r/main/README.markdown", true},
+		{"https://github.com/o/r/blob/main/main.go", "", true},
+		{"https://github.com/o/r", "", false},
+		{"https://github.com/o/r/tree/main/docs", "", false},
+		{"https://gitlab.com/g/sub/p/-/blob/main/docs/a.md?ref_type=heads", "https://gitlab.com/g/sub/p/-/raw/main/docs/a.md", true},
+		{"https://gitlab.example.org/g/p/-/blob/v1/x.py", "", true},
+		{"https://example.com/blob/main/a.md", "", false},
+	}
+	for _, tt := range tests {
+		raw, source := hostedSource(tt.url)
+		if raw != tt.raw || source != tt.source {
+			t.Errorf("hostedSource(%q) = %q, %v, want %q, %v", tt.url, raw, source, tt.raw, tt.source)
+		}
+	}
+}
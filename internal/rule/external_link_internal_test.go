@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -61,7 +62,7 @@ func Test_checkURL(t *testing.T) {
 			}))
 			defer ts.Close()
 
-			status, err := checkURL(ts.Client(), ts.URL, 10, DefaultMaxRetries, nil)
+			status, err := checkURL(ts.Client(), ts.URL, retryPolicy{delayMs: 10, maxRetries: DefaultMaxRetries}, nil, nil)
 			if tt.expectError && err == nil {
 				t.Errorf("expected error but got none")
 			}
@@ -90,7 +91,7 @@ func Test_checkURL_ExponentialBackoff(t *testing.T) {
 	// retryDelayMs=100, maxRetries=3 → delays: 100ms, 200ms, 400ms
 	// linear would give: 100ms, 200ms, 300ms — gap2/gap1 ≈ 1.5×
 	// exponential gives: 100ms, 200ms, 400ms — gap2/gap1 ≈ 2×
-	_, _ = checkURL(ts.Client(), ts.URL, 100, 3, nil)
+	_, _ = checkURL(ts.Client(), ts.URL, retryPolicy{delayMs: 100, maxRetries: 3}, nil, nil)
 
 	mu.Lock()
 	times := requestTimes
@@ -121,7 +122,7 @@ func Test_performCheck(t *testing.T) {
 		}
 
 		for _, invalidURL := range invalidURLs {
-			status, err := performCheck(client, invalidURL)
+			status, _, err := performCheck(client, invalidURL)
 			if err == nil {
 				t.Errorf("expected error for invalid URL %q, but got none", invalidURL)
 			}
@@ -139,7 +140,7 @@ func Test_performCheck(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		status, err := performCheck(ts.Client(), ts.URL)
+		status, _, err := performCheck(ts.Client(), ts.URL)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -165,7 +166,7 @@ func Test_performCheck(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		status, err := performCheck(ts.Client(), ts.URL)
+		status, _, err := performCheck(ts.Client(), ts.URL)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -221,3 +222,182 @@ func Test_formatLinkError(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"120", 120 * time.Second},
+		{" 3 ", 3 * time.Second},
+		{"0", 0},
+		{"-5", 0},
+		{"Fri, 02 Jan 2026 15:04:35 GMT", 30 * time.Second},
+		{"Fri, 02 Jan 2026 15:00:00 GMT", 0},
+		{"soon", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_checkURL_RetryAfter(t *testing.T) {
+	// rateLimited answers status with a Retry-After header the first n times,
+	// then final.
+	rateLimited := func(n int, status int, retryAfter string, final int) *httptest.Server {
+		var mu sync.Mutex
+		calls := 0
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			calls++
+			c := calls
+			mu.Unlock()
+			if c <= n {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(status)
+				return
+			}
+			w.WriteHeader(final)
+		}))
+	}
+	policy := retryPolicy{delayMs: 10, maxRetries: 2, maxRetryAfter: time.Minute}
+
+	t.Run("waits and pauses the host", func(t *testing.T) {
+		ts := rateLimited(1, http.StatusTooManyRequests, "1", http.StatusOK)
+		defer ts.Close()
+		lim := &hostLimiter{}
+		start := time.Now()
+		status, err := checkURL(ts.Client(), ts.URL, policy, lim, nil)
+		if err != nil || status != http.StatusOK {
+			t.Fatalf("got %d, %v", status, err)
+		}
+		if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+			t.Errorf("expected to wait for Retry-After, took %v", elapsed)
+		}
+		if lim.nextAvail.Before(start.Add(900 * time.Millisecond)) {
+			t.Errorf("expected the host to be paused, next request allowed at %v", lim.nextAvail.Sub(start))
+		}
+	})
+
+	t.Run("pause gives up the global slot", func(t *testing.T) {
+		ts := rateLimited(1, http.StatusTooManyRequests, "1", http.StatusOK)
+		defer ts.Close()
+		sem := make(chan struct{}, 1)
+		sem <- struct{}{}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if status, _ := checkURL(ts.Client(), ts.URL, policy, &hostLimiter{}, sem); status != http.StatusOK {
+				t.Errorf("got %d", status)
+			}
+		}()
+		// Another host's request gets the only slot during the pause.
+		select {
+		case sem <- struct{}{}:
+			<-sem
+		case <-time.After(800 * time.Millisecond):
+			t.Error("expected the slot released while the host is paused")
+		}
+		<-done
+		if len(sem) != 1 {
+			t.Errorf("expected the slot taken again after the pause, %d held", len(sem))
+		}
+	})
+
+	t.Run("HTTP date on 503", func(t *testing.T) {
+		ts := rateLimited(1, http.StatusServiceUnavailable, time.Now().Add(2*time.Second).UTC().Format(http.TimeFormat), http.StatusOK)
+		defer ts.Close()
+		start := time.Now()
+		if status, _ := checkURL(ts.Client(), ts.URL, policy, &hostLimiter{}, nil); status != http.StatusOK {
+			t.Fatalf("got %d", status)
+		}
+		// The date has one-second precision.
+		if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+			t.Errorf("expected to wait until the date, took %v", elapsed)
+		}
+	})
+
+	t.Run("wait is capped", func(t *testing.T) {
+		ts := rateLimited(1, http.StatusTooManyRequests, "3600", http.StatusOK)
+		defer ts.Close()
+		capped := policy
+		capped.maxRetryAfter = 50 * time.Millisecond
+		start := time.Now()
+		if status, _ := checkURL(ts.Client(), ts.URL, capped, &hostLimiter{}, nil); status != http.StatusOK {
+			t.Fatalf("got %d", status)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("expected the wait capped, took %v", elapsed)
+		}
+	})
+
+	t.Run("breakage behind rate limiting is reported", func(t *testing.T) {
+		ts := rateLimited(1, http.StatusTooManyRequests, "0", http.StatusNotFound)
+		defer ts.Close()
+		if status, _ := checkURL(ts.Client(), ts.URL, policy, &hostLimiter{}, nil); status != http.StatusNotFound {
+			t.Errorf("expected the 404 behind the 429, got %d", status)
+		}
+	})
+
+	t.Run("still rate limited after the retries", func(t *testing.T) {
+		ts := rateLimited(10, http.StatusTooManyRequests, "0", http.StatusOK)
+		defer ts.Close()
+		status, _ := checkURL(ts.Client(), ts.URL, policy, &hostLimiter{}, nil)
+		if status != http.StatusTooManyRequests || !isAllowedStatus(status, nil) {
+			t.Errorf("expected an allowed 429, got %d", status)
+		}
+	})
+
+	t.Run("rate limited for longer than the cap", func(t *testing.T) {
+		ts := rateLimited(10, http.StatusTooManyRequests, "3600", http.StatusOK)
+		defer ts.Close()
+		capped := policy
+		capped.maxRetryAfter = 10 * time.Millisecond
+		status, err := checkURL(ts.Client(), ts.URL, capped, &hostLimiter{}, nil)
+		if status != http.StatusTooManyRequests || err == nil {
+			t.Errorf("expected a 429 reported broken, got %d, %v", status, err)
+		}
+		// Unless the user allows 429 outright.
+		capped.allowedStatuses = []int{http.StatusTooManyRequests}
+		if _, err := checkURL(ts.Client(), ts.URL, capped, &hostLimiter{}, nil); err != nil {
+			t.Errorf("expected an allowed 429, got %v", err)
+		}
+	})
+
+	t.Run("no Retry-After is allowed after the backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer ts.Close()
+		status, err := checkURL(ts.Client(), ts.URL, policy, &hostLimiter{}, nil)
+		if status != http.StatusTooManyRequests || err != nil || !isAllowedStatus(status, nil) {
+			t.Errorf("expected an allowed 429, got %d, %v", status, err)
+		}
+		if n := calls.Load(); n != int32(policy.maxRetries+1) {
+			t.Errorf("expected %d requests, got %d", policy.maxRetries+1, n)
+		}
+	})
+}
+
+func Test_hostLimiter_pause(t *testing.T) {
+	h := &hostLimiter{}
+	h.pause(200 * time.Millisecond)
+	start := time.Now()
+	h.acquire()
+	h.release()
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected acquire to wait out the pause, took %v", elapsed)
+	}
+	// A shorter pause does not cut a longer one short.
+	h.pause(time.Hour)
+	h.pause(time.Millisecond)
+	if time.Until(h.nextAvail) < 59*time.Minute {
+		t.Errorf("expected the longer pause to stand, next request in %v", time.Until(h.nextAvail))
+	}
+}
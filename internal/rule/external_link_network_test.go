@@ -13,17 +13,17 @@ import (
 	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
 )
 
-// TestCheckExternalLinks_NoRequestsForBlockedContexts is the #337 Phase 4 live
+// TestLinkChecker_NoRequestsForBlockedContexts is the #337 Phase 4 live
 // verification: the static audit claimed external-link no longer issues HTTP
 // requests for URLs that appear inside indented code, HTML blocks, HTML
 // comments, or inline code spans. This confirms it against a real run by
 // recording every path the server is asked for and asserting only the control
 // link (outside any such context) is ever fetched.
 //
-// Unlike the results-based TestCheckExternalLinks_IgnoreCodeBlocks, this asserts
+// Unlike the results-based TestLinkChecker_IgnoreCodeBlocks, this asserts
 // on the actual network traffic, so it cannot be fooled by a blocked URL that
 // happens to return 200 (which would also produce zero lint errors).
-func TestCheckExternalLinks_NoRequestsForBlockedContexts(t *testing.T) {
+func TestLinkChecker_NoRequestsForBlockedContexts(t *testing.T) {
 	var mu sync.Mutex
 	requested := make(map[string]int)
 
@@ -52,12 +52,7 @@ func TestCheckExternalLinks_NoRequestsForBlockedContexts(t *testing.T) {
 `, ts.URL)
 
 	lines, offset := toLines(markdown)
-	results, checked := rule.CheckExternalLinks(
-		"mock.md", preprocess.Scan(lines), offset,
-		[]*regexp.Regexp{}, 10, 10,
-		rule.DefaultMaxConcurrency, rule.DefaultMaxRetries,
-		nil, &sync.Map{}, 0, 0,
-	)
+	results, checked := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	// Only the control URL is a real link, and it returns 200, so no errors.
 	if len(results) != 0 {
@@ -34,7 +34,35 @@ func toLines(markdown string) ([]string, int) {
 	return strings.Split(markdown, "\n"), 0
 }
 
-func TestCheckExternalLinks_BasicSuccessFailure(t *testing.T) {
+// linkOptions returns external-link options with no per-host limits, and
+// extra on top.
+func linkOptions(timeoutSeconds, retryDelayMs, maxConcurrency, maxRetries int, extra ...rule.Options) rule.Options {
+	opts := rule.Options{
+		"timeoutSeconds":       timeoutSeconds,
+		"retryDelayMs":         retryDelayMs,
+		"maxConcurrency":       maxConcurrency,
+		"maxRetries":           maxRetries,
+		"maxRetryAfterSeconds": rule.DefaultMaxRetryAfterSeconds,
+	}
+	for _, e := range extra {
+		for k, v := range e {
+			opts[k] = v
+		}
+	}
+	return opts
+}
+
+// checkLinks checks the external links of one file as the linter does, and
+// returns the broken ones with the number of distinct URLs checked.
+func checkLinks(path string, ctx *preprocess.Context, offset int, skipPatterns []*regexp.Regexp, opts rule.Options, urlCache *sync.Map) ([]rule.LintError, int) {
+	sites := rule.ExternalLinkSites(ctx, offset, skipPatterns)
+	urls := rule.DistinctURLs(sites)
+	c := rule.NewLinkChecker(opts, urlCache)
+	c.Check(urls)
+	return c.Errors(path, sites), len(urls)
+}
+
+func TestLinkChecker_BasicSuccessFailure(t *testing.T) {
 	ts := setupTestServer()
 	defer ts.Close()
 
@@ -44,7 +72,7 @@ func TestCheckExternalLinks_BasicSuccessFailure(t *testing.T) {
 
 	file := "mock.md"
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks(file, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks(file, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 error, got %d", len(results))
@@ -62,7 +90,7 @@ func TestCheckExternalLinks_BasicSuccessFailure(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_SkipPattern(t *testing.T) {
+func TestLinkChecker_SkipPattern(t *testing.T) {
 	markdown := `
 [skip this](http://localhost/skip)
 [check this](https://httpstat.us/404)
@@ -72,7 +100,7 @@ func TestCheckExternalLinks_SkipPattern(t *testing.T) {
 	}
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, skip, 2, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("mock.md", preprocess.Scan(lines), offset, skip, linkOptions(2, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 error (only non-localhost link should be checked), got %d", len(results))
@@ -82,7 +110,7 @@ func TestCheckExternalLinks_SkipPattern(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_IgnoreCodeBlocks(t *testing.T) {
+func TestLinkChecker_IgnoreCodeBlocks(t *testing.T) {
 	ts := setupTestServer()
 	defer ts.Close()
 
@@ -91,7 +119,7 @@ func TestCheckExternalLinks_IgnoreCodeBlocks(t *testing.T) {
 	skip := []*regexp.Regexp{}
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, skip, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("mock.md", preprocess.Scan(lines), offset, skip, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 	if len(results) != 1 {
 		t.Fatalf("expected 1 error (code block link should be ignored), got %d", len(results))
 	}
@@ -103,7 +131,7 @@ func TestCheckExternalLinks_IgnoreCodeBlocks(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_ParallelCheck(t *testing.T) {
+func TestLinkChecker_ParallelCheck(t *testing.T) {
 	ts := setupTestServer()
 	defer ts.Close()
 
@@ -121,7 +149,7 @@ Line 8 [link8](%s/fail8)
 
 	skip := []*regexp.Regexp{}
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, skip, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("mock.md", preprocess.Scan(lines), offset, skip, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 5 {
 		t.Fatalf("expected 5 errors, got %d", len(results))
@@ -140,7 +168,7 @@ Line 8 [link8](%s/fail8)
 	}
 }
 
-func TestCheckExternalLinks_Deduplication(t *testing.T) {
+func TestLinkChecker_Deduplication(t *testing.T) {
 	requestCount := 0
 	customTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestCount++
@@ -153,23 +181,23 @@ func TestCheckExternalLinks_Deduplication(t *testing.T) {
 	lines, offset := toLines(markdown)
 
 	// First call - should trigger HTTP request
-	_, _ = rule.CheckExternalLinks("file1.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, urlCache, 0, 0)
+	_, _ = checkLinks("file1.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), urlCache)
 	// Second call - should use cache
-	_, _ = rule.CheckExternalLinks("file2.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, urlCache, 0, 0)
+	_, _ = checkLinks("file2.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), urlCache)
 
 	if requestCount != 1 {
 		t.Errorf("expected only 1 HTTP request due to caching, but got %d", requestCount)
 	}
 }
 
-func TestCheckExternalLinks_MultipleOccurrences(t *testing.T) {
+func TestLinkChecker_MultipleOccurrences(t *testing.T) {
 	ts := setupTestServer()
 	defer ts.Close()
 
 	markdown := fmt.Sprintf("[fail](%s/fail)\n[fail again](%s/fail)", ts.URL, ts.URL)
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	// Should report errors for each occurrence
 	if len(results) != 2 {
@@ -188,7 +216,7 @@ func TestCheckExternalLinks_MultipleOccurrences(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_AllLinksSucceed(t *testing.T) {
+func TestLinkChecker_AllLinksSucceed(t *testing.T) {
 	ts := setupTestServer()
 	defer ts.Close()
 
@@ -197,14 +225,14 @@ func TestCheckExternalLinks_AllLinksSucceed(t *testing.T) {
 [link3](%s/ok)`, ts.URL, ts.URL, ts.URL)
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected 0 errors for all successful links, got %d", len(results))
 	}
 }
 
-func TestCheckExternalLinks_HTTPStatusBoundary(t *testing.T) {
+func TestLinkChecker_HTTPStatusBoundary(t *testing.T) {
 	boundaryTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/399":
@@ -219,7 +247,7 @@ func TestCheckExternalLinks_HTTPStatusBoundary(t *testing.T) {
 	fileName := "boundary.md"
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 error (only 400), got %d", len(results))
@@ -237,7 +265,7 @@ func TestCheckExternalLinks_HTTPStatusBoundary(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_MultipleSkipPatterns(t *testing.T) {
+func TestLinkChecker_MultipleSkipPatterns(t *testing.T) {
 	markdown := `
 [localhost](http://localhost/skip)
 [example](http://example.com/skip)
@@ -252,7 +280,7 @@ func TestCheckExternalLinks_MultipleSkipPatterns(t *testing.T) {
 	}
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, skip, 2, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks(fileName, preprocess.Scan(lines), offset, skip, linkOptions(2, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 error (only httpstat.us), got %d", len(results))
@@ -270,14 +298,14 @@ func TestCheckExternalLinks_MultipleSkipPatterns(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_NetworkError(t *testing.T) {
+func TestLinkChecker_NetworkError(t *testing.T) {
 	// Use an invalid/unreachable URL that will cause network error
 	markdown := "[unreachable](http://invalid.test.localhost.invalid:9999/path)"
 	fileName := "network.md"
 	unreachableURL := "http://invalid.test.localhost.invalid:9999/path"
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 1, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(1, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 error for network failure, got %d", len(results))
@@ -295,7 +323,7 @@ func TestCheckExternalLinks_NetworkError(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_DifferentHTTPStatusCodes(t *testing.T) {
+func TestLinkChecker_DifferentHTTPStatusCodes(t *testing.T) {
 	statusTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/404":
@@ -311,7 +339,7 @@ func TestCheckExternalLinks_DifferentHTTPStatusCodes(t *testing.T) {
 	markdown := fmt.Sprintf("[not-found](%s/404)\n[server-error](%s/500)\n[unavailable](%s/503)", statusTs.URL, statusTs.URL, statusTs.URL)
 	fileName := "test.md"
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 3 {
 		t.Fatalf("expected 3 errors, got %d", len(results))
@@ -341,7 +369,7 @@ func TestCheckExternalLinks_DifferentHTTPStatusCodes(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_RetrySuccess(t *testing.T) {
+func TestLinkChecker_RetrySuccess(t *testing.T) {
 	requestCount := 0
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestCount++
@@ -356,7 +384,7 @@ func TestCheckExternalLinks_RetrySuccess(t *testing.T) {
 	markdown := fmt.Sprintf("[retry-link](%s/retry)", ts.URL)
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("retry.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("retry.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected 0 errors due to successful retry, but got %d", len(results))
@@ -367,7 +395,7 @@ func TestCheckExternalLinks_RetrySuccess(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_NoRetryFor404(t *testing.T) {
+func TestLinkChecker_NoRetryFor404(t *testing.T) {
 	requestCount := 0
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestCount++
@@ -378,7 +406,7 @@ func TestCheckExternalLinks_NoRetryFor404(t *testing.T) {
 	markdown := fmt.Sprintf("[not-found](%s/404)", ts.URL)
 
 	lines, offset := toLines(markdown)
-	_, _ = rule.CheckExternalLinks("404.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	_, _ = checkLinks("404.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	// For 404, there should be no retry; it should give up after a single request
 	if requestCount != 1 {
@@ -386,7 +414,7 @@ func TestCheckExternalLinks_NoRetryFor404(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_ConcurrencyLimit(t *testing.T) {
+func TestLinkChecker_ConcurrencyLimit(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
@@ -399,14 +427,14 @@ func TestCheckExternalLinks_ConcurrencyLimit(t *testing.T) {
 	markdown := strings.Join(links, "\n")
 
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("heavy.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 5, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("heavy.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(5, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected 0 errors, got %d", len(results))
 	}
 }
 
-func TestCheckExternalLinks_CacheErrorState(t *testing.T) {
+func TestLinkChecker_CacheErrorState(t *testing.T) {
 	// Test that network errors are cached and reported consistently across multiple calls
 	markdown := "[unreachable](http://invalid.test.localhost.invalid:9999/path)"
 	fileName := "cache-error.md"
@@ -414,14 +442,14 @@ func TestCheckExternalLinks_CacheErrorState(t *testing.T) {
 
 	// First call - should trigger network error
 	lines, offset := toLines(markdown)
-	results1, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 1, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, urlCache, 0, 0)
+	results1, _ := checkLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(1, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), urlCache)
 
 	if len(results1) != 1 {
 		t.Fatalf("first call: expected 1 error for network failure, got %d", len(results1))
 	}
 
 	// Second call with same cache - should use cached error and report the same error
-	results2, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 1, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, urlCache, 0, 0)
+	results2, _ := checkLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(1, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), urlCache)
 
 	if len(results2) != 1 {
 		t.Fatalf("second call: expected 1 error from cache, got %d", len(results2))
@@ -433,7 +461,7 @@ func TestCheckExternalLinks_CacheErrorState(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_CacheInvalidType(t *testing.T) {
+func TestLinkChecker_CacheInvalidType(t *testing.T) {
 	// Test that when cache contains an unexpected type, it re-checks the URL
 	ts := setupTestServer()
 	defer ts.Close()
@@ -446,9 +474,9 @@ func TestCheckExternalLinks_CacheInvalidType(t *testing.T) {
 	testURL := ts.URL + "/fail"
 	urlCache.Store(testURL, "invalid type")
 
-	// Call CheckExternalLinks - should detect invalid cache type and re-check
+	// Check the link - should detect invalid cache type and re-check
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, urlCache, 0, 0)
+	results, _ := checkLinks(fileName, preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), urlCache)
 
 	// Should still get error because URL returns 404
 	if len(results) != 1 {
@@ -677,7 +705,7 @@ See (https://example.com/page) for details.
 	}
 }
 
-func TestCheckExternalLinks_GETFallback(t *testing.T) {
+func TestLinkChecker_GETFallback(t *testing.T) {
 	// Server that closes HEAD connections to force a transport error,
 	// then responds 200 to GET — exercises the HEAD→GET fallback in performCheck.
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -697,7 +725,7 @@ func TestCheckExternalLinks_GETFallback(t *testing.T) {
 
 	markdown := fmt.Sprintf("[link](%s/page)\n", ts.URL)
 	lines, offset := toLines(markdown)
-	results, count := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, count := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected no errors (GET fallback should succeed), got: %v", results)
@@ -707,7 +735,7 @@ func TestCheckExternalLinks_GETFallback(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_GETFallback_On405(t *testing.T) {
+func TestLinkChecker_GETFallback_On405(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodHead {
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -719,7 +747,7 @@ func TestCheckExternalLinks_GETFallback_On405(t *testing.T) {
 
 	markdown := fmt.Sprintf("[link](%s/page)\n", ts.URL)
 	lines, offset := toLines(markdown)
-	results, count := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, count := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected no errors (GET fallback on 405 should succeed), got: %v", results)
@@ -729,7 +757,7 @@ func TestCheckExternalLinks_GETFallback_On405(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_GETFallback_On403(t *testing.T) {
+func TestLinkChecker_GETFallback_On403(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodHead {
 			w.WriteHeader(http.StatusForbidden)
@@ -741,7 +769,7 @@ func TestCheckExternalLinks_GETFallback_On403(t *testing.T) {
 
 	markdown := fmt.Sprintf("[link](%s/page)\n", ts.URL)
 	lines, offset := toLines(markdown)
-	results, count := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, count := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected no errors (GET fallback on 403 should succeed), got: %v", results)
@@ -751,7 +779,7 @@ func TestCheckExternalLinks_GETFallback_On403(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_NoGETFallback_On404(t *testing.T) {
+func TestLinkChecker_NoGETFallback_On404(t *testing.T) {
 	var mu sync.Mutex
 	var methods []string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -764,7 +792,7 @@ func TestCheckExternalLinks_NoGETFallback_On404(t *testing.T) {
 
 	markdown := fmt.Sprintf("[link](%s/page)\n", ts.URL)
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("mock.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	mu.Lock()
 	defer mu.Unlock()
@@ -776,7 +804,7 @@ func TestCheckExternalLinks_NoGETFallback_On404(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_429NotFlagged(t *testing.T) {
+func TestLinkChecker_429NotFlagged(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
 	}))
@@ -784,14 +812,14 @@ func TestCheckExternalLinks_429NotFlagged(t *testing.T) {
 
 	markdown := fmt.Sprintf("[rate-limited](%s/429)", ts.URL)
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("test.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("test.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if len(results) != 0 {
 		t.Errorf("expected no violations for 429 (rate limiting), got %d: %v", len(results), results)
 	}
 }
 
-func TestCheckExternalLinks_AllowedStatuses(t *testing.T) {
+func TestLinkChecker_AllowedStatuses(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/403":
@@ -805,7 +833,7 @@ func TestCheckExternalLinks_AllowedStatuses(t *testing.T) {
 	// 403 is in allowedStatuses → no violation; 500 is not → violation
 	markdown := fmt.Sprintf("[forbidden](%s/403)\n[server-error](%s/500)", ts.URL, ts.URL)
 	lines, offset := toLines(markdown)
-	results, _ := rule.CheckExternalLinks("test.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, []int{403}, &sync.Map{}, 0, 0)
+	results, _ := checkLinks("test.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, rule.Options{"allowedStatuses": []int{403}}), &sync.Map{})
 
 	if len(results) != 1 {
 		t.Fatalf("expected 1 violation (500 only), got %d: %v", len(results), results)
@@ -815,7 +843,7 @@ func TestCheckExternalLinks_AllowedStatuses(t *testing.T) {
 	}
 }
 
-func TestCheckExternalLinks_ConfigurableMaxConcurrency(t *testing.T) {
+func TestLinkChecker_ConfigurableMaxConcurrency(t *testing.T) {
 	var mu sync.Mutex
 	concurrent := 0
 	maxSeen := 0
@@ -841,14 +869,14 @@ func TestCheckExternalLinks_ConfigurableMaxConcurrency(t *testing.T) {
 	}
 	lines, offset := toLines(strings.Join(links, "\n"))
 
-	_, _ = rule.CheckExternalLinks("heavy.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 5, 0, 2, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	_, _ = checkLinks("heavy.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(5, 0, 2, rule.DefaultMaxRetries), &sync.Map{})
 
 	if maxSeen > 2 {
 		t.Errorf("expected max concurrency of 2, but saw %d concurrent requests", maxSeen)
 	}
 }
 
-func TestCheckExternalLinks_ConfigurableMaxRetries(t *testing.T) {
+func TestLinkChecker_ConfigurableMaxRetries(t *testing.T) {
 	requestCount := 0
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestCount++
@@ -859,7 +887,7 @@ func TestCheckExternalLinks_ConfigurableMaxRetries(t *testing.T) {
 	markdown := fmt.Sprintf("[retry-link](%s/retry)", ts.URL)
 	lines, offset := toLines(markdown)
 
-	_, _ = rule.CheckExternalLinks("retry.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 5, 0, rule.DefaultMaxConcurrency, 0, nil, &sync.Map{}, 0, 0)
+	_, _ = checkLinks("retry.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(5, 0, rule.DefaultMaxConcurrency, 0), &sync.Map{})
 
 	if requestCount != 1 {
 		t.Errorf("maxRetries=0: expected 1 request, got %d", requestCount)
@@ -876,14 +904,14 @@ func TestPerformCheck_UserAgentHeader(t *testing.T) {
 
 	markdown := fmt.Sprintf("[link](%s/page)", ts.URL)
 	lines, offset := toLines(markdown)
-	_, _ = rule.CheckExternalLinks("test.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries, nil, &sync.Map{}, 0, 0)
+	_, _ = checkLinks("test.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 0, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), &sync.Map{})
 
 	if !strings.Contains(gotUA, "gomarklint") {
 		t.Errorf("expected User-Agent to contain 'gomarklint', got %q", gotUA)
 	}
 }
 
-func TestCheckExternalLinks_PerHostConcurrencyLimit(t *testing.T) {
+func TestLinkChecker_PerHostConcurrencyLimit(t *testing.T) {
 	var mu sync.Mutex
 	concurrent := 0
 	maxSeen := 0
@@ -910,14 +938,14 @@ func TestCheckExternalLinks_PerHostConcurrencyLimit(t *testing.T) {
 	lines, offset := toLines(strings.Join(links, "\n"))
 
 	// global concurrency=8, per-host=2 → max simultaneous to same host should be ≤ 2
-	_, _ = rule.CheckExternalLinks("heavy.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 5, 0, 8, rule.DefaultMaxRetries, nil, &sync.Map{}, 2, 0)
+	_, _ = checkLinks("heavy.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(5, 0, 8, rule.DefaultMaxRetries, rule.Options{"perHostConcurrency": 2}), &sync.Map{})
 
 	if maxSeen > 2 {
 		t.Errorf("expected per-host concurrency ≤ 2, but saw %d concurrent requests", maxSeen)
 	}
 }
 
-func TestCheckExternalLinks_PerHostIntervalMs(t *testing.T) {
+func TestLinkChecker_PerHostIntervalMs(t *testing.T) {
 	var mu sync.Mutex
 	var requestTimes []time.Time
 
@@ -937,7 +965,7 @@ func TestCheckExternalLinks_PerHostIntervalMs(t *testing.T) {
 
 	intervalMs := 1000
 	// perHostConcurrency=1 to serialize requests; interval=1000ms (minimum valid value)
-	_, _ = rule.CheckExternalLinks("interval.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, 5, 0, 10, rule.DefaultMaxRetries, nil, &sync.Map{}, 1, intervalMs)
+	_, _ = checkLinks("interval.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(5, 0, 10, rule.DefaultMaxRetries, rule.Options{"perHostConcurrency": 1, "perHostIntervalMs": intervalMs}), &sync.Map{})
 
 	mu.Lock()
 	times := requestTimes
@@ -953,3 +981,28 @@ func TestCheckExternalLinks_PerHostIntervalMs(t *testing.T) {
 		}
 	}
 }
+
+func TestPreloadLinks(t *testing.T) {
+	ts := setupTestServer()
+	defer ts.Close()
+
+	checkedAt := time.Now().Add(-time.Minute)
+	urlCache := &sync.Map{}
+	// The server answers /fail with 404; the preloaded result is used instead.
+	rule.PreloadLinks(urlCache, map[string]rule.CachedLink{ts.URL + "/fail": {Status: 200, CheckedAt: checkedAt}})
+
+	markdown := fmt.Sprintf("[a](%s/fail)\n[b](%s/ok)\n", ts.URL, ts.URL)
+	lines, offset := toLines(markdown)
+	results, _ := checkLinks("preload.md", preprocess.Scan(lines), offset, []*regexp.Regexp{}, linkOptions(10, 10, rule.DefaultMaxConcurrency, rule.DefaultMaxRetries), urlCache)
+	if len(results) != 0 {
+		t.Errorf("expected the preloaded result to be used, got %v", results)
+	}
+
+	links := rule.CheckedLinks(urlCache)
+	if got := links[ts.URL+"/fail"]; got.Status != 200 || !got.CheckedAt.Equal(checkedAt) {
+		t.Errorf("expected the preloaded result unchanged, got %+v", got)
+	}
+	if got := links[ts.URL+"/ok"]; got.Status != 200 || got.Failed() || time.Since(got.CheckedAt) > time.Minute {
+		t.Errorf("expected a fresh result for /ok, got %+v", got)
+	}
+}
@@ -13,11 +13,11 @@ func CheckFencedCodeLanguage(filename string, ctx *preprocess.Context, offset in
 		trimmed := strings.TrimSpace(ctx.Line(span.Start))
 		marker := openingFenceMarker(trimmed)
 		if strings.TrimSpace(trimmed[len(marker):]) == "" {
-			errs = append(errs, LintError{
+			errs = append(errs, atContent(LintError{
 				File:    filename,
 				Line:    offset + span.Start + 1,
 				Message: "Fenced code block must have a language identifier",
-			})
+			}, ctx.Line(span.Start)))
 		}
 	}
 
@@ -9,9 +9,12 @@ func CheckFinalBlankLine(filename string, lines []string, offset int) []LintErro
 	if len(lines) >= 2 && lines[len(lines)-1] == "" {
 		return nil
 	}
-	return []LintError{{
+	last := lines[len(lines)-1]
+	lineNum := len(lines) + offset
+	e := atSpan(LintError{
 		File:    filename,
-		Line:    len(lines) + offset,
+		Line:    lineNum,
 		Message: "Missing final blank line",
-	}}
+	}, last, len(last), len(last))
+	return []LintError{withFix(e, replaceBytes(lineNum, last, len(last), len(last), "\n"))}
 }
@@ -0,0 +1,71 @@
+package rule
+
+import "strings"
+
+// Edit replaces the text from (Line, Column) up to (EndLine, EndColumn) with
+// NewText. Positions use the same 1-based character columns as LintError;
+// the end is exclusive. A "\n" in NewText is written with the file's own
+// line ending.
+type Edit struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	NewText   string `json:"new_text"`
+}
+
+// Fix is a machine-applicable correction for one LintError. Its edits are
+// applied together or not at all.
+type Fix struct {
+	Edits []Edit `json:"edits"`
+}
+
+// withFix attaches edits to e as a single Fix.
+func withFix(e LintError, edits ...Edit) LintError {
+	e.Fix = &Fix{Edits: edits}
+	return e
+}
+
+// replaceBytes replaces line[start:end] on line number lineNum with text.
+func replaceBytes(lineNum int, line string, start, end int, text string) Edit {
+	return Edit{
+		Line:      lineNum,
+		Column:    charColumn(line, start),
+		EndLine:   lineNum,
+		EndColumn: charColumn(line, end),
+		NewText:   text,
+	}
+}
+
+// insertBlankLineBefore inserts an empty line above line number lineNum.
+// Both "preceded by" and "followed by" blank-line fixes use this form so that
+// two rules asking for the same blank line produce identical, conflicting
+// edits instead of two blank lines.
+func insertBlankLineBefore(lineNum int) Edit {
+	return Edit{Line: lineNum, Column: 1, EndLine: lineNum, EndColumn: 1, NewText: "\n"}
+}
+
+// deleteLineAfter removes line number lineNum together with the line break
+// that precedes it. prev and cur are the raw texts of lines lineNum-1 and lineNum.
+func deleteLineAfter(lineNum int, prev, cur string) Edit {
+	return Edit{
+		Line:      lineNum - 1,
+		Column:    charColumn(prev, len(prev)),
+		EndLine:   lineNum,
+		EndColumn: charColumn(cur, len(cur)),
+	}
+}
+
+// expandTab returns the spaces that replace the tab at line[j], advancing to
+// the next multiple-of-4 column as CommonMark does for indentation.
+func expandTab(line string, j int) string {
+	col := 0
+	for _, r := range line[:j] {
+		if r == '\t' {
+			col += 4 - col%4
+		} else {
+			col++
+		}
+	}
+	return strings.Repeat(" ", 4-col%4)
+}
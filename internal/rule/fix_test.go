@@ -0,0 +1,57 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestExpandTab(t *testing.T) {
+	tests := []struct {
+		line string
+		j    int
+		want int
+	}{
+		{"\tx", 0, 4},
+		{"a\tx", 1, 3},
+		{"abcd\tx", 4, 4},
+		{"\t\tx", 1, 4},
+		{"日本\tx", len("日本"), 2},
+	}
+	for _, tt := range tests {
+		if got := expandTab(tt.line, tt.j); got != strings.Repeat(" ", tt.want) {
+			t.Errorf("expandTab(%q, %d) = %q, want %d spaces", tt.line, tt.j, got, tt.want)
+		}
+	}
+}
+
+func TestIsThematicBreak(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"* * *", true},
+		{"---", true},
+		{"  _ _ _  ", true},
+		{"* *", false},
+		{"* a *", false},
+		{"- * -", false},
+	}
+	for _, tt := range tests {
+		if got := isThematicBreak(tt.line); got != tt.want {
+			t.Errorf("isThematicBreak(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestConsistentListMarker_ThematicBreakHasNoFix(t *testing.T) {
+	lines := []string{"- a", "", "* * *"}
+	errs := CheckConsistentListMarker("test.md", preprocess.Scan(lines), 0, "consistent")
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errs))
+	}
+	if errs[0].Fix != nil {
+		t.Errorf("expected no fix for a thematic break, got %+v", errs[0].Fix)
+	}
+}
@@ -7,12 +7,20 @@ import (
 	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
 )
 
+// LintError is a single violation. Column and EndColumn are 1-based character
+// columns; EndColumn is exclusive. All three position fields are zero when a
+// rule reports only a line. Fix is set when the rule can correct the
+// violation mechanically.
 type LintError struct {
-	File     string `json:"file"`
-	Line     int    `json:"line"`
-	Rule     string `json:"rule,omitempty"`
-	Message  string `json:"message"`
-	Severity string `json:"severity"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	EndColumn int    `json:"end_column,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+	Message   string `json:"message"`
+	Severity  string `json:"severity"`
+	Fix       *Fix   `json:"fix,omitempty"`
 }
 
 func atxHeadingLevel(line string) int {
@@ -57,18 +65,18 @@ func CheckHeadingLevels(filename string, ctx *preprocess.Context, offset int, mi
 
 		if prevLevel == 0 {
 			if currentLevel != minLevel {
-				errs = append(errs, LintError{
+				errs = append(errs, atContent(LintError{
 					File:    filename,
 					Line:    i + 1 + offset,
 					Message: fmt.Sprintf("First heading should be level %d (found level %d)", minLevel, currentLevel),
-				})
+				}, line))
 			}
 		} else if currentLevel > prevLevel+1 {
-			errs = append(errs, LintError{
+			errs = append(errs, atContent(LintError{
 				File:    filename,
 				Line:    i + 1 + offset,
 				Message: fmt.Sprintf("Heading level jumped from %d to %d", prevLevel, currentLevel),
-			})
+			}, line))
 		}
 		prevLevel = currentLevel
 	}
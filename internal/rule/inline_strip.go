@@ -1,7 +1,5 @@
 package rule
 
-import "strings"
-
 // no-hard-tabs strips inline code but NOT inline HTML comments (markdownlint divergence #337 Section B).
 // Other rules use preprocess.Context.Sanitized which blanks both.
 
@@ -14,12 +12,26 @@ func countBacktickRun(s string, start int) int {
 }
 
 func stripInlineCode(s string) string {
-	var b strings.Builder
-	b.Grow(len(s))
+	spans := inlineCodeSpans(s)
+	if len(spans) == 0 {
+		return s
+	}
+	// Replace each entire span (delimiters + content) with spaces.
+	b := []byte(s)
+	for _, sp := range spans {
+		for k := sp.start; k < sp.end; k++ {
+			b[k] = ' '
+		}
+	}
+	return string(b)
+}
 
+// inlineCodeSpans returns the code spans of s. Each span's byte range covers
+// its backtick delimiters; its text is the content between them.
+func inlineCodeSpans(s string) []textSpan {
+	var spans []textSpan
 	for i := 0; i < len(s); {
 		if s[i] != '`' {
-			b.WriteByte(s[i])
 			i++
 			continue
 		}
@@ -41,21 +53,13 @@ func stripInlineCode(s string) string {
 		}
 
 		if closing == -1 {
-			// No matching closing run — emit backticks as-is.
-			for k := 0; k < delimLen; k++ {
-				b.WriteByte('`')
-			}
+			// No matching closing run — the backticks are literal.
 			i += delimLen
 			continue
 		}
 
-		// Replace the entire span (delimiters + content) with spaces.
-		spanLen := (closing + delimLen) - i
-		for k := 0; k < spanLen; k++ {
-			b.WriteByte(' ')
-		}
+		spans = append(spans, textSpan{text: s[i+delimLen : closing], start: i, end: closing + delimLen})
 		i = closing + delimLen
 	}
-
-	return b.String()
+	return spans
 }
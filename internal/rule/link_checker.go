@@ -0,0 +1,219 @@
+package rule
+
+import (
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// LinkSite is an external link cited in a file.
+type LinkSite struct {
+	URL       string
+	Line      int
+	Column    int
+	EndColumn int
+}
+
+// ExternalLinkSites returns the external links in ctx that do not match
+// skipPatterns, in document order.
+func ExternalLinkSites(ctx *preprocess.Context, offset int, skipPatterns []*regexp.Regexp) []LinkSite {
+	var sites []LinkSite
+	for _, link := range extractExternalLinks(ctx, offset) {
+		if shouldSkipLink(link.URL, skipPatterns) {
+			continue
+		}
+		sites = append(sites, LinkSite{URL: link.URL, Line: link.Line, Column: link.column, EndColumn: link.endColumn})
+	}
+	return sites
+}
+
+// DistinctURLs returns the URLs of sites without repeats, in order of first
+// appearance.
+func DistinctURLs(sites []LinkSite) []string {
+	seen := make(map[string]bool, len(sites))
+	var urls []string
+	for _, s := range sites {
+		if !seen[s.URL] {
+			seen[s.URL] = true
+			urls = append(urls, s.URL)
+		}
+	}
+	return urls
+}
+
+// LinkChecker requests external URLs for a whole run. Every request goes
+// through one HTTP client and connection pool, one maxConcurrency limit and
+// one limiter per host, so perHostIntervalMs spaces the requests to a host
+// however many files cite it. Each URL is requested at most once, even when
+// several goroutines ask for it at the same time. It is safe for concurrent
+// use.
+type LinkChecker struct {
+	client   *http.Client
+	sem      chan struct{}
+	hosts    *hostLimiterRegistry
+	retry    retryPolicy
+	urlCache *sync.Map
+
+	// checkFragments looks for the fragment of each URL that has one in
+	// the page, whose anchors are fetched once into pages.
+	checkFragments bool
+	pages          sync.Map // base URL -> *pageAnchors
+	// slugger makes the anchors of hosted Markdown files' headings.
+	slugger func(string) string
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{}
+}
+
+// NewLinkChecker returns a checker configured by the external-link rule's
+// resolved options. Results are stored in urlCache, which may hold results
+// to reuse.
+func NewLinkChecker(opts Options, urlCache *sync.Map) *LinkChecker {
+	retry := retryPolicy{
+		delayMs:         opts.Int("retryDelayMs"),
+		maxRetries:      opts.Int("maxRetries"),
+		maxRetryAfter:   time.Duration(opts.Int("maxRetryAfterSeconds")) * time.Second,
+		allowedStatuses: opts.Ints("allowedStatuses"),
+	}
+	c := newLinkChecker(opts.Int("timeoutSeconds"), opts.Int("maxConcurrency"), retry, urlCache, opts.Int("perHostConcurrency"), opts.Int("perHostIntervalMs"))
+	c.checkFragments = opts.Bool("checkFragments")
+	c.slugger = makeSlugger(parseSlugAlgorithm(opts), opts)
+	return c
+}
+
+func newLinkChecker(timeoutSeconds, maxConcurrency int, retry retryPolicy, urlCache *sync.Map, perHostConcurrency, perHostIntervalMs int) *LinkChecker {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Keep a connection per concurrent request to a host for reuse.
+	transport.MaxIdleConnsPerHost = max(perHostConcurrency, http.DefaultMaxIdleConnsPerHost)
+	return &LinkChecker{
+		client:   &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second, Transport: transport},
+		sem:      make(chan struct{}, maxConcurrency),
+		hosts:    newHostLimiterRegistry(perHostConcurrency, perHostIntervalMs),
+		retry:    retry,
+		urlCache: urlCache,
+		inflight: make(map[string]chan struct{}),
+	}
+}
+
+// Check requests each of urls that has no result yet, and returns once all
+// of them have one.
+func (c *LinkChecker) Check(urls []string) {
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		if c.known(u) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.check(u)
+		}()
+	}
+	wg.Wait()
+}
+
+func (c *LinkChecker) check(url string) {
+	c.mu.Lock()
+	if wait, ok := c.inflight[url]; ok {
+		c.mu.Unlock()
+		<-wait
+		return
+	}
+	if c.known(url) {
+		c.mu.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	c.inflight[url] = done
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, url)
+		c.mu.Unlock()
+		close(done)
+	}()
+
+	if c.checkFragments {
+		if base, fragment, ok := splitFragment(url); ok {
+			c.urlCache.Store(url, c.checkFragment(base, fragment))
+			return
+		}
+	}
+	lim, release := c.throttle(url)
+	defer release()
+	status, err := checkURL(c.client, url, c.retry, lim, c.sem)
+	c.urlCache.Store(url, cacheResult{status: status, err: err, checkedAt: time.Now()})
+}
+
+// throttle waits until url may be requested, and returns its host's limiter
+// and a func to call once the request is done. It waits for the host before
+// taking a global slot, so a host's interval does not hold up requests to
+// other hosts.
+func (c *LinkChecker) throttle(url string) (*hostLimiter, func()) {
+	lim := c.hosts.get(extractHost(url))
+	lim.acquire()
+	c.sem <- struct{}{}
+	return lim, func() {
+		<-c.sem
+		lim.release()
+	}
+}
+
+// known reports whether url has a result that answers what Check asks: one
+// that looked for its fragment, when fragments are checked.
+func (c *LinkChecker) known(url string) bool {
+	r := c.result(url)
+	if r == nil {
+		return false
+	}
+	if c.checkFragments && r.fragment == "" {
+		_, _, hasFragment := splitFragment(url)
+		return !hasFragment
+	}
+	return true
+}
+
+// result returns the stored result for url, or nil when there is none.
+func (c *LinkChecker) result(url string) *cacheResult {
+	v, ok := c.urlCache.Load(url)
+	if !ok {
+		return nil
+	}
+	r, ok := v.(cacheResult)
+	if !ok {
+		return nil
+	}
+	return &r
+}
+
+// Errors returns an error for each of sites whose URL Check found broken,
+// or, when fragments are checked, whose fragment it did not find.
+func (c *LinkChecker) Errors(path string, sites []LinkSite) []LintError {
+	var errs []LintError
+	for _, s := range sites {
+		r := c.result(s.URL)
+		var message string
+		switch {
+		case r == nil:
+			continue
+		case r.err != nil || (r.status >= 400 && !isAllowedStatus(r.status, c.retry.allowedStatuses)):
+			message = formatLinkError(s.URL)
+		case c.checkFragments && r.fragment == fragmentMissing:
+			message = formatFragmentError(s.URL)
+		default:
+			continue
+		}
+		errs = append(errs, LintError{
+			File:      path,
+			Line:      s.Line,
+			Column:    s.Column,
+			EndLine:   s.Line,
+			EndColumn: s.EndColumn,
+			Message:   message,
+		})
+	}
+	return errs
+}
@@ -0,0 +1,151 @@
+package rule_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+func TestLinkChecker_RequestsEachURLOnce(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := rule.NewLinkChecker(externalLinkOptions(t, map[string]interface{}{"perHostIntervalMs": float64(0)}), &sync.Map{})
+
+	// Concurrent checks of one URL share a single request.
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Check([]string{ts.URL + "/gone"})
+		}()
+	}
+	wg.Wait()
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+
+	sites := []rule.LinkSite{
+		{URL: ts.URL + "/gone", Line: 3, Column: 5, EndColumn: 20},
+		{URL: ts.URL + "/gone", Line: 9, Column: 1, EndColumn: 16},
+		{URL: ts.URL + "/unchecked", Line: 10},
+	}
+	errs := c.Errors("doc.md", sites)
+	if len(errs) != 2 || errs[0].Line != 3 || errs[0].Column != 5 || errs[1].Line != 9 {
+		t.Errorf("expected the broken link reported at both sites, got %+v", errs)
+	}
+}
+
+func TestLinkChecker_CheckFragments(t *testing.T) {
+	var pageGets atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			if r.Method == http.MethodGet {
+				pageGets.Add(1)
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<h2 id="install">Install</h2>`))
+		case "/doc.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	sites := []rule.LinkSite{
+		{URL: ts.URL + "/page#install", Line: 1},
+		{URL: ts.URL + "/page#renamed", Line: 2},
+		{URL: ts.URL + "/page#renamed", Line: 3},
+		{URL: ts.URL + "/doc.pdf#page=2", Line: 4},
+		{URL: ts.URL + "/gone#install", Line: 5},
+	}
+	urls := rule.DistinctURLs(sites)
+
+	c := rule.NewLinkChecker(externalLinkOptions(t, map[string]interface{}{"perHostIntervalMs": float64(0), "checkFragments": true}), &sync.Map{})
+	c.Check(urls)
+	var got []string
+	for _, e := range c.Errors("doc.md", sites) {
+		got = append(got, e.Message)
+	}
+	want := []string{
+		"Link fragment not found: " + ts.URL + "/page#renamed",
+		"Link fragment not found: " + ts.URL + "/page#renamed",
+		"Link unreachable: " + ts.URL + "/gone#install",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if n := pageGets.Load(); n != 1 {
+		t.Errorf("expected the page fetched once for its anchors, got %d", n)
+	}
+
+	// Without the option, fragments are not looked for.
+	off := rule.NewLinkChecker(externalLinkOptions(t, map[string]interface{}{"perHostIntervalMs": float64(0)}), &sync.Map{})
+	off.Check(urls)
+	if errs := off.Errors("doc.md", sites); len(errs) != 1 || errs[0].Line != 5 {
+		t.Errorf("expected only the unreachable link, got %+v", errs)
+	}
+}
+
+func TestExternalLinkRule_UsesLinkChecker(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<h2 id="install">Install</h2>`))
+	}))
+	defer ts.Close()
+
+	// Run through the registry, the rule honours checkFragments as the
+	// linter's run-wide check does.
+	r := externalLinkRule(t)
+	opts := externalLinkOptions(t, map[string]interface{}{"perHostIntervalMs": float64(0), "checkFragments": true})
+	lines := []string{"[a](" + ts.URL + "/page#install)", "", "[b](" + ts.URL + "/page#renamed)"}
+	errs := r.Check("doc.md", preprocess.Scan(lines), 0, opts)
+	if len(errs) != 1 || errs[0].Line != 3 || errs[0].Message != "Link fragment not found: "+ts.URL+"/page#renamed" {
+		t.Errorf("expected the missing fragment on line 3, got %+v", errs)
+	}
+}
+
+func TestDistinctURLs(t *testing.T) {
+	sites := []rule.LinkSite{{URL: "b"}, {URL: "a"}, {URL: "b"}}
+	got := rule.DistinctURLs(sites)
+	if len(got) != 2 || got[0] != "b" || got[1] != "a" {
+		t.Errorf("got %v", got)
+	}
+}
+
+// externalLinkRule returns the registered external-link rule.
+func externalLinkRule(t *testing.T) rule.Rule {
+	t.Helper()
+	for _, r := range rule.Registered() {
+		if r.Name() == rule.ExternalLinkName {
+			return r
+		}
+	}
+	t.Fatal("external-link is not registered")
+	return nil
+}
+
+// externalLinkOptions resolves raw options for the external-link rule.
+func externalLinkOptions(t *testing.T, raw map[string]interface{}) rule.Options {
+	t.Helper()
+	opts, err := rule.ResolveOptions(externalLinkRule(t), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
@@ -31,26 +31,16 @@ func extractHeadingText(line string) (string, int) {
 
 var reFragmentLink = regexp.MustCompile(`\[[^\]]*\]\(#([^)]+)\)`)
 var reRefLinkUsage = regexp.MustCompile(`\[[^\]]*\]\[([^\]]+)\]`)
-var reRefDef = regexp.MustCompile(`^\s*\[([^\]]+)\]:\s+#(\S+)`)
 var reStripInlineImages = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
 
+// collectRefDefs maps the labels of the reference definitions in ctx whose
+// destination is a fragment, "#frag", to the fragment.
 func collectRefDefs(ctx *preprocess.Context) map[string]string {
 	defs := make(map[string]string)
-	for i := 0; i < ctx.Len(); i++ {
-		if inBlockContext(ctx, i) {
-			continue
-		}
-		line := ctx.Line(i)
-		if !strings.HasPrefix(strings.TrimLeft(line, " \t"), "[") {
-			continue
+	for label, d := range collectReferences(ctx, 0).firstDefinitions() {
+		if fragment, ok := strings.CutPrefix(d.dest, "#"); ok && fragment != "" {
+			defs[label] = fragment
 		}
-		m := reRefDef.FindStringSubmatch(line)
-		if m == nil {
-			continue
-		}
-		label := strings.ToLower(strings.TrimSpace(m[1]))
-		fragment := strings.TrimSpace(m[2])
-		defs[label] = fragment
 	}
 	return defs
 }
@@ -110,35 +100,36 @@ func parseSlugAlgorithm(options map[string]interface{}) string {
 	return "github"
 }
 
-func checkInlineFragments(filename string, lineNum int, scanned string, slugs map[string]struct{}) []LintError {
+// scanned must have the same byte length as raw; raw is used only for columns.
+func checkInlineFragments(filename string, lineNum int, scanned, raw string, slugs map[string]struct{}) []LintError {
 	var errs []LintError
-	for _, m := range reFragmentLink.FindAllStringSubmatch(scanned, -1) {
-		fragment := m[1]
+	for _, m := range reFragmentLink.FindAllStringSubmatchIndex(scanned, -1) {
+		fragment := scanned[m[2]:m[3]]
 		if _, ok := slugs[fragment]; !ok {
-			errs = append(errs, LintError{
+			errs = append(errs, atSpan(LintError{
 				File:    filename,
 				Line:    lineNum,
 				Message: fmt.Sprintf("link-fragments: fragment #%s not found in this document", fragment),
-			})
+			}, raw, m[0], m[1]))
 		}
 	}
 	return errs
 }
 
-func checkRefFragments(filename string, lineNum int, scanned string, slugs map[string]struct{}, refDefs map[string]string) []LintError {
+func checkRefFragments(filename string, lineNum int, scanned, raw string, slugs map[string]struct{}, refDefs map[string]string) []LintError {
 	var errs []LintError
-	for _, m := range reRefLinkUsage.FindAllStringSubmatch(scanned, -1) {
-		label := strings.ToLower(strings.TrimSpace(m[1]))
+	for _, m := range reRefLinkUsage.FindAllStringSubmatchIndex(scanned, -1) {
+		label := normalizeLabel(scanned[m[2]:m[3]])
 		fragment, ok := refDefs[label]
 		if !ok {
 			continue
 		}
 		if _, found := slugs[fragment]; !found {
-			errs = append(errs, LintError{
+			errs = append(errs, atSpan(LintError{
 				File:    filename,
 				Line:    lineNum,
 				Message: fmt.Sprintf("link-fragments: fragment #%s not found in this document", fragment),
-			})
+			}, raw, m[0], m[1]))
 		}
 	}
 	return errs
@@ -163,11 +154,13 @@ func CheckLinkFragments(filename string, ctx *preprocess.Context, offset int, op
 		}
 
 		// Sanitized blanks inline code spans (and inline comments); inline images
-		// are stripped separately so image fragments like ![alt](#fig) are not
-		// treated as broken links.
+		// are blanked separately so image fragments like ![alt](#fig) are not
+		// treated as broken links. Both keep byte offsets aligned with the raw line.
 		scanned := ctx.Sanitized(i)
 		if strings.ContainsRune(scanned, '!') {
-			scanned = reStripInlineImages.ReplaceAllString(scanned, "")
+			scanned = reStripInlineImages.ReplaceAllStringFunc(scanned, func(m string) string {
+				return strings.Repeat(" ", len(m))
+			})
 		}
 
 		hasInlineLink := strings.Contains(scanned, "(#")
@@ -178,10 +171,10 @@ func CheckLinkFragments(filename string, ctx *preprocess.Context, offset int, op
 
 		lineNum := offset + i + 1
 		if hasInlineLink {
-			errs = append(errs, checkInlineFragments(filename, lineNum, scanned, slugs)...)
+			errs = append(errs, checkInlineFragments(filename, lineNum, scanned, ctx.Line(i), slugs)...)
 		}
 		if hasRefLink {
-			errs = append(errs, checkRefFragments(filename, lineNum, scanned, slugs, refDefs)...)
+			errs = append(errs, checkRefFragments(filename, lineNum, scanned, ctx.Line(i), slugs, refDefs)...)
 		}
 	}
 
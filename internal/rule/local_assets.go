@@ -0,0 +1,169 @@
+package rule
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// reHTMLAssetTag matches the start tag, up to the end of the line, of an
+// HTML element that embeds a file, and reHTMLSrc its src and poster
+// attributes. Group 1, 2 or 3 is the value, by quoting.
+var (
+	reHTMLAssetTag = regexp.MustCompile(`(?i)<(?:img|video|audio|source)\b[^>]*`)
+	reHTMLSrc      = regexp.MustCompile(`(?i)\s(?:src|poster)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// assetChecker resolves the asset paths of one document, listing each
+// directory at most once.
+type assetChecker struct {
+	filename string
+	dir      string
+	siteRoot string
+	maxBytes int
+	listings map[string][]string
+}
+
+// CheckLocalAssets reports images, and the src of HTML img, video, audio and
+// source elements, whose local paths do not exist. Relative paths resolve
+// against the directory of filename, and paths starting with "/" against
+// siteRoot; they are skipped when siteRoot is empty. A path that exists only
+// in a different case is reported too, as it breaks on case-sensitive file
+// systems. With maxBytes above 0, files larger than that are reported as
+// warnings.
+func CheckLocalAssets(filename string, ctx *preprocess.Context, offset int, siteRoot string, maxBytes int) []LintError {
+	c := &assetChecker{
+		filename: filename,
+		dir:      filepath.Dir(filename),
+		siteRoot: siteRoot,
+		maxBytes: maxBytes,
+		listings: map[string][]string{},
+	}
+	var errs []LintError
+	for _, t := range append(localTargets(ctx, offset), htmlAssetTargets(ctx, offset)...) {
+		if !t.image {
+			continue
+		}
+		if e, ok := c.check(t); ok {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// htmlAssetTargets returns the local paths in the src and poster attributes
+// of HTML elements in ctx, outside code and comments. They count as images.
+func htmlAssetTargets(ctx *preprocess.Context, offset int) []localTarget {
+	var targets []localTarget
+	for i := 0; i < ctx.Len(); i++ {
+		if ctx.InFencedCode(i) || ctx.InIndentedCode(i) || ctx.InHTMLComment(i) {
+			continue
+		}
+		line := ctx.Sanitized(i)
+		if !strings.ContainsRune(line, '<') {
+			continue
+		}
+		for _, tag := range reHTMLAssetTag.FindAllStringIndex(line, -1) {
+			for _, m := range reHTMLSrc.FindAllStringSubmatchIndex(line[tag[0]:tag[1]], -1) {
+				var value string
+				for g := 1; g <= 3; g++ {
+					if m[2*g] >= 0 {
+						value = line[tag[0]+m[2*g] : tag[0]+m[2*g+1]]
+					}
+				}
+				if t, ok := newLocalTarget(value, true, i+1+offset, ctx.Line(i), tag[0]+m[0]+1, tag[0]+m[1]); ok {
+					targets = append(targets, t)
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// check returns the finding for t, if any.
+func (c *assetChecker) check(t localTarget) (LintError, bool) {
+	base, rel := c.dir, t.path
+	if strings.HasPrefix(t.path, "/") {
+		if c.siteRoot == "" {
+			return LintError{}, false
+		}
+		base, rel = c.siteRoot, strings.TrimLeft(t.path, "/")
+	}
+	e := LintError{File: c.filename, Line: t.line}
+	actual, found := c.onDisk(base, rel)
+	switch {
+	case !found:
+		e.Message = fmt.Sprintf("local-assets: %s not found", t.path)
+	case actual != path.Clean(rel):
+		e.Message = fmt.Sprintf("local-assets: %s differs in case from %s on disk", t.path, actual)
+	default:
+		info, err := os.Stat(filepath.Join(base, filepath.FromSlash(actual)))
+		switch {
+		case err != nil:
+			e.Message = fmt.Sprintf("local-assets: %s not found", t.path)
+		case info.IsDir():
+			e.Message = fmt.Sprintf("local-assets: %s is a directory", t.path)
+		case c.maxBytes > 0 && info.Size() > int64(c.maxBytes):
+			e.Message = fmt.Sprintf("local-assets: %s is %d bytes, more than maxBytes (%d)", t.path, info.Size(), c.maxBytes)
+			e.Severity = string(config.SeverityWarning)
+		default:
+			return LintError{}, false
+		}
+	}
+	return atSpan(e, t.raw, t.start, t.end), true
+}
+
+// onDisk looks up rel, a slash-separated path, under base one component at
+// a time. It returns the path with each component spelled as on disk,
+// preferring an exact match to one that differs only in case, and whether
+// every component was found.
+func (c *assetChecker) onDisk(base, rel string) (string, bool) {
+	dir := base
+	var parts []string
+	for _, name := range strings.Split(path.Clean(rel), "/") {
+		switch name {
+		case ".":
+			continue
+		case "..":
+			dir = filepath.Join(dir, name)
+			parts = append(parts, name)
+			continue
+		}
+		actual, ok := c.entry(dir, name)
+		if !ok {
+			return "", false
+		}
+		dir = filepath.Join(dir, actual)
+		parts = append(parts, actual)
+	}
+	return strings.Join(parts, "/"), true
+}
+
+// entry returns the name in dir that equals name, or failing that the first
+// that equals it ignoring case.
+func (c *assetChecker) entry(dir, name string) (string, bool) {
+	names, ok := c.listings[dir]
+	if !ok {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		c.listings[dir] = names
+	}
+	folded := ""
+	for _, n := range names {
+		if n == name {
+			return n, true
+		}
+		if folded == "" && strings.EqualFold(n, name) {
+			folded = n
+		}
+	}
+	return folded, folded != ""
+}
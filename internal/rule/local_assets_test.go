@@ -0,0 +1,74 @@
+package rule
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestCheckLocalAssets(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"docs/img/arch.png":     "0123456789",
+		"docs/img/big.png":      strings.Repeat("x", 100),
+		"docs/video/demo.mp4":   "",
+		"shared/logo.svg":       "",
+		"site/static/badge.png": "",
+	})
+	filename := filepath.Join(dir, "docs", "index.md")
+	siteRoot := filepath.Join(dir, "site")
+
+	tests := []struct {
+		name     string
+		content  string
+		siteRoot string
+		maxBytes int
+		want     []string
+	}{
+		{"existing image", "![arch](img/arch.png)\n", "", 0, nil},
+		{"parent directory", "![logo](../shared/logo.svg \"Logo\")\n", "", 0, nil},
+		{"missing image", "![arch](img/missing.png)\n", "", 0, []string{"local-assets: img/missing.png not found"}},
+		{"missing directory", "![arch](./gone/arch.png)\n", "", 0, []string{"local-assets: ./gone/arch.png not found"}},
+		{"case mismatch", "![arch](img/Arch.PNG)\n", "", 0, []string{"local-assets: img/Arch.PNG differs in case from img/arch.png on disk"}},
+		{"case mismatch in a directory", "![arch](IMG/arch.png)\n", "", 0, []string{"local-assets: IMG/arch.png differs in case from img/arch.png on disk"}},
+		{"directory", "![img](img)\n", "", 0, []string{"local-assets: img is a directory"}},
+		{"reference image", "![arch][a]\n\n[a]: img/gone.png\n", "", 0, []string{"local-assets: img/gone.png not found"}},
+		{"badge inside a link", "[![badge](img/gone.png)](https://example.com)\n", "", 0, []string{"local-assets: img/gone.png not found"}},
+		{"html img", "<p align=\"center\">\n  <img src=\"img/gone.png\" alt=\"x\">\n</p>\n", "", 0, []string{"local-assets: img/gone.png not found"}},
+		{"html video and poster", "<video src='video/demo.mp4' poster=img/poster.png></video>\n", "", 0, []string{"local-assets: img/poster.png not found"}},
+		{"absolute without site root", "![b](/static/gone.png)\n", "", 0, nil},
+		{"absolute with site root", "![b](/static/badge.png) ![c](/static/gone.png)\n", siteRoot, 0, []string{"local-assets: /static/gone.png not found"}},
+		{"size limit", "![a](img/arch.png) ![b](img/big.png)\n", "", 50, []string{"local-assets: img/big.png is 100 bytes, more than maxBytes (50)"}},
+		{"links are not assets", "[x](img/gone.png)\n", "", 0, nil},
+		{"skipped destinations", "![a](https://example.com/a.png) ![b](//cdn.example.com/b.png) ![c](data:image/png;base64,AAAA) ![d]({{ .Site.Logo }})\n", "", 0, nil},
+		{"code is skipped", "`![a](gone.png)`\n\n```html\n<img src=\"gone.png\">\n```\n\n<!-- <img src=\"gone.png\"> -->\n", "", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := preprocess.Scan(strings.Split(tt.content, "\n"))
+			var got []string
+			for _, e := range CheckLocalAssets(filename, ctx, 0, tt.siteRoot, tt.maxBytes) {
+				got = append(got, e.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLocalAssets_SeverityAndPosition(t *testing.T) {
+	dir := writeTree(t, map[string]string{"big.png": strings.Repeat("x", 10)})
+	ctx := preprocess.Scan([]string{"Intro", "See ![big](big.png) and ![gone](gone.png)."})
+	errs := CheckLocalAssets(filepath.Join(dir, "a.md"), ctx, 3, "", 5)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if e := errs[0]; e.Line != 5 || e.Column != 5 || e.EndColumn != 20 || e.Severity != "warning" {
+		t.Errorf("oversized: got line %d, columns %d-%d, severity %q", e.Line, e.Column, e.EndColumn, e.Severity)
+	}
+	if e := errs[1]; e.Column != 25 || e.Severity != "" {
+		t.Errorf("missing: got column %d, severity %q", e.Column, e.Severity)
+	}
+}
@@ -0,0 +1,108 @@
+package rule
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// localTarget is a link or image destination in a document that names a
+// local file: relative to the document, or, when path starts with "/", to
+// the root of the site.
+type localTarget struct {
+	dest     string // as written
+	path     string // decoded, without query or fragment; "" for "#f" alone
+	fragment string
+	image    bool
+	line     int    // 1-based line number in the file
+	raw      string // the line, for columns
+	start    int    // byte range of the link or definition in raw
+	end      int
+}
+
+var (
+	reInlineDest = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\]]*\])*)\]\(\s*(<[^<>\n]*>|[^\s()<>]*(?:\([^\s()]*\)[^\s()<>]*)*)(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*\)`)
+	reURLScheme  = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// localTargets returns the destinations in ctx of inline links and images,
+// and of reference definitions, that are local paths: not URLs, bare
+// fragments or template expressions. A definition counts as an image when
+// only images use it.
+func localTargets(ctx *preprocess.Context, offset int) []localTarget {
+	refs := collectReferences(ctx, offset)
+	defLines := make(map[int]bool, len(refs.defs))
+	for _, d := range refs.defs {
+		defLines[d.line] = true
+	}
+
+	var targets []localTarget
+	for i := 0; i < ctx.Len(); i++ {
+		if inBlockContext(ctx, i) || defLines[i+1+offset] {
+			continue
+		}
+		line := ctx.Sanitized(i)
+		if !strings.ContainsRune(line, '[') {
+			continue
+		}
+		targets = append(targets, inlineTargets(line, ctx.Line(i), i+1+offset, 0)...)
+	}
+
+	imageLabels := map[string]bool{}
+	linkLabels := map[string]bool{}
+	for _, u := range refs.uses {
+		if u.image {
+			imageLabels[u.label] = true
+		} else {
+			linkLabels[u.label] = true
+		}
+	}
+	for _, d := range refs.defs {
+		image := imageLabels[d.label] && !linkLabels[d.label]
+		if t, ok := newLocalTarget(d.dest, image, d.line, d.raw, d.start, d.end); ok {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// inlineTargets returns the local destinations of the inline links and
+// images in text, which starts at byte base of raw, including those nested
+// in a link's text as in a linked badge.
+func inlineTargets(text, raw string, line, base int) []localTarget {
+	var targets []localTarget
+	for _, m := range reInlineDest.FindAllStringSubmatchIndex(text, -1) {
+		image := m[3] > m[2]
+		if t, ok := newLocalTarget(text[m[6]:m[7]], image, line, raw, base+m[0], base+m[1]); ok {
+			targets = append(targets, t)
+		}
+		if strings.ContainsRune(text[m[4]:m[5]], '[') {
+			targets = append(targets, inlineTargets(text[m[4]:m[5]], raw, line, base+m[4])...)
+		}
+	}
+	return targets
+}
+
+// newLocalTarget parses dest and reports whether it names a local path.
+// A protocol-relative "//host/..." does not.
+func newLocalTarget(dest string, image bool, line int, raw string, start, end int) (localTarget, bool) {
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "//") ||
+		reURLScheme.MatchString(dest) || strings.Contains(dest, "{{") || strings.Contains(dest, "{%") {
+		return localTarget{}, false
+	}
+	path, fragment, _ := strings.Cut(dest, "#")
+	path, _, _ = strings.Cut(path, "?")
+	if path == "" {
+		return localTarget{}, false
+	}
+	if decoded, err := url.PathUnescape(path); err == nil {
+		path = decoded
+	}
+	return localTarget{
+		dest: dest, path: path, fragment: fragment, image: image,
+		line: line, raw: raw, start: start, end: end,
+	}, true
+}
@@ -44,11 +44,11 @@ func CheckMaxLineLength(filename string, ctx *preprocess.Context, offset int, li
 			continue
 		}
 
-		errs = append(errs, LintError{
+		errs = append(errs, atSpan(LintError{
 			File:    filename,
 			Line:    offset + i + 1,
 			Message: fmt.Sprintf("max-line-length: line exceeds %d bytes (%d)", lineLength, len(line)),
-		})
+		}, line, lineLength, len(line)))
 	}
 
 	return errs
@@ -38,8 +38,8 @@ func scanURLEnd(line string, bodyStart int) int {
 	return end
 }
 
-func findBareURLs(line string) []string {
-	var urls []string
+func findBareURLs(line string) []textSpan {
+	var urls []textSpan
 	pos := 0
 	for pos < len(line) {
 		idx := strings.Index(line[pos:], "http")
@@ -66,7 +66,8 @@ func findBareURLs(line string) []string {
 			continue
 		}
 
-		urls = append(urls, strings.TrimRight(line[start:end], ".,;:!?)"))
+		url := strings.TrimRight(line[start:end], ".,;:!?)")
+		urls = append(urls, textSpan{text: url, start: start, end: start + len(url)})
 		pos = end
 	}
 	return urls
@@ -78,7 +79,7 @@ func isLinkCard(ctx *preprocess.Context, i int, trimmed string) bool {
 		return false
 	}
 	urls := findBareURLs(trimmed)
-	if len(urls) != 1 || trimmed != urls[0] {
+	if len(urls) != 1 || trimmed != urls[0].text {
 		return false
 	}
 	prevBlank := i == 0 || strings.TrimSpace(ctx.Line(i-1)) == ""
@@ -104,11 +105,11 @@ func CheckNoBareURLs(filename string, ctx *preprocess.Context, offset int) []Lin
 		}
 
 		for _, url := range findBareURLs(sanitized) {
-			errs = append(errs, LintError{
+			errs = append(errs, atSpan(LintError{
 				File:    filename,
 				Line:    offset + i + 1,
-				Message: fmt.Sprintf("no-bare-urls: bare URL found, use angle brackets or a Markdown link: %s", url),
-			})
+				Message: fmt.Sprintf("no-bare-urls: bare URL found, use angle brackets or a Markdown link: %s", url.text),
+			}, ctx.Line(i), url.start, url.end))
 		}
 	}
 
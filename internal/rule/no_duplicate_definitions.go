@@ -0,0 +1,34 @@
+package rule
+
+import (
+	"fmt"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// CheckNoDuplicateDefinitions reports link reference definitions whose
+// label, compared as CommonMark normalizes it, an earlier definition
+// already has. Only the first definition takes effect, so a later one is
+// dead and usually a copy-paste mistake.
+func CheckNoDuplicateDefinitions(filename string, ctx *preprocess.Context, offset int) []LintError {
+	refs := collectReferences(ctx, offset)
+	if len(refs.defs) < 2 {
+		return nil
+	}
+	first := make(map[string]int, len(refs.defs))
+
+	var errs []LintError
+	for _, d := range refs.defs {
+		line, dup := first[d.label]
+		if !dup {
+			first[d.label] = d.line
+			continue
+		}
+		errs = append(errs, atSpan(LintError{
+			File:    filename,
+			Line:    d.line,
+			Message: fmt.Sprintf("no-duplicate-definitions: [%s] is already defined on line %d", d.text, line),
+		}, d.raw, d.start, d.end))
+	}
+	return errs
+}
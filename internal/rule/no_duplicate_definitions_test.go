@@ -0,0 +1,35 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestCheckNoDuplicateDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offset  int
+		want    []string
+	}{
+		{"distinct labels", "[a]: https://a.example\n[b]: https://b.example\n", 0, nil},
+		{"same label", "[a]: https://a.example\n[a]: https://b.example\n", 0, []string{"2: no-duplicate-definitions: [a] is already defined on line 1"}},
+		{"labels differing in case and spacing", "[Foo Bar]: https://a.example\n\n[foo   bar]: https://b.example\n", 0, []string{"3: no-duplicate-definitions: [foo   bar] is already defined on line 1"}},
+		{"every later duplicate", "[a]: x\n[a]: y\n[a]: z\n", 4, []string{
+			"6: no-duplicate-definitions: [a] is already defined on line 5",
+			"7: no-duplicate-definitions: [a] is already defined on line 5",
+		}},
+		{"duplicate in code is skipped", "[a]: x\n\n```\n[a]: y\n```\n", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findings(CheckNoDuplicateDefinitions("test.md", preprocess.Scan(strings.Split(tt.content, "\n")), tt.offset))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -92,11 +92,11 @@ func CheckNoEmphasisAsHeading(filename string, ctx *preprocess.Context, offset i
 			continue
 		}
 
-		errs = append(errs, LintError{
+		errs = append(errs, atContent(LintError{
 			File:    filename,
 			Line:    offset + i + 1,
 			Message: fmt.Sprintf("no-emphasis-as-heading: emphasis used as heading, use ATX heading instead: %s", trimmed),
-		})
+		}, line))
 	}
 
 	return errs
@@ -11,8 +11,8 @@ func emptyLinkDest(dest string) bool {
 	return dest == "" || dest == "#" || dest == "<>"
 }
 
-func findEmptyLinks(line string) []string {
-	var results []string
+func findEmptyLinks(line string) []textSpan {
+	var results []textSpan
 	pos := 0
 	for pos < len(line) {
 		// Look for '](' which signals link/image destination.
@@ -43,7 +43,7 @@ func findEmptyLinks(line string) []string {
 					bracketStart--
 				}
 			}
-			results = append(results, line[bracketStart:closeParen+1])
+			results = append(results, textSpan{text: line[bracketStart : closeParen+1], start: bracketStart, end: closeParen + 1})
 		}
 		pos = closeParen + 1
 	}
@@ -64,11 +64,11 @@ func CheckNoEmptyLinks(filename string, ctx *preprocess.Context, offset int) []L
 		}
 
 		for _, match := range findEmptyLinks(line) {
-			errs = append(errs, LintError{
+			errs = append(errs, atSpan(LintError{
 				File:    filename,
 				Line:    offset + i + 1,
-				Message: fmt.Sprintf("no-empty-links: link has empty destination: %s", match),
-			})
+				Message: fmt.Sprintf("no-empty-links: link has empty destination: %s", match.text),
+			}, ctx.Line(i), match.start, match.end))
 		}
 	}
 
@@ -25,16 +25,18 @@ func CheckNoHardTabs(filename string, ctx *preprocess.Context, offset int) []Lin
 			scanned = stripInlineCode(line)
 		}
 
-		col := 0
-		for _, ch := range scanned {
-			col++
-			if ch == '\t' {
-				errs = append(errs, LintError{
-					File:    filename,
-					Line:    offset + i + 1,
-					Message: fmt.Sprintf("no-hard-tabs: hard tab character found at column %d", col),
-				})
+		// stripInlineCode is byte-length preserving, so offsets into scanned
+		// are offsets into line.
+		for j := 0; j < len(scanned); j++ {
+			if scanned[j] != '\t' {
+				continue
 			}
+			err := atSpan(LintError{
+				File: filename,
+				Line: offset + i + 1,
+			}, line, j, j+1)
+			err.Message = fmt.Sprintf("no-hard-tabs: hard tab character found at column %d", err.Column)
+			errs = append(errs, withFix(err, replaceBytes(err.Line, line, j, j+1, expandTab(line, j))))
 		}
 	}
 
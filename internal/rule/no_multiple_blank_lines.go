@@ -18,11 +18,15 @@ func CheckNoMultipleBlankLines(filename string, ctx *preprocess.Context, offset
 		if strings.TrimSpace(ctx.Line(i)) == "" {
 			consecutiveBlankCount++
 			if consecutiveBlankCount > 1 {
-				errs = append(errs, LintError{
+				lineNum := i + 1 + offset
+				e := atSpan(LintError{
 					File:    filename,
-					Line:    i + 1 + offset,
+					Line:    lineNum,
 					Message: "Multiple consecutive blank lines",
-				})
+				}, ctx.Line(i), 0, len(ctx.Line(i)))
+				// Deleting the break before this line keeps the edit inside the
+				// file even when the blank line is the last one.
+				errs = append(errs, withFix(e, deleteLineAfter(lineNum, ctx.Line(i-1), ctx.Line(i))))
 			}
 		} else {
 			consecutiveBlankCount = 0
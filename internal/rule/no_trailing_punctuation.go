@@ -48,12 +48,16 @@ func isPossibleBlockMarker(b byte) bool {
 	return b == '*' || b == '+' || b == '-' || b == '>' || (b >= '0' && b <= '9')
 }
 
-func noTPViolation(filename string, lineNum int, r rune) LintError {
-	return LintError{
+// noTPViolation reports r, the last rune of text, which occurs in line.
+func noTPViolation(filename string, lineNum int, r rune, line, text string) LintError {
+	end := strings.LastIndex(line, text) + len(text)
+	start := end - utf8.RuneLen(r)
+	e := atSpan(LintError{
 		File:    filename,
 		Line:    lineNum,
 		Message: fmt.Sprintf("no-trailing-punctuation: heading ends with %q", string(r)),
-	}
+	}, line, start, end)
+	return withFix(e, replaceBytes(lineNum, line, start, end, ""))
 }
 
 func CheckNoTrailingPunctuation(filename string, ctx *preprocess.Context, offset int, punctuation string) []LintError {
@@ -85,7 +89,7 @@ func CheckNoTrailingPunctuation(filename string, ctx *preprocess.Context, offset
 
 		if text, ok := atxLineText(first, line); ok {
 			if r, ok := lastRuneInSet(text, punctuation); ok {
-				errs = append(errs, noTPViolation(filename, i+1+offset, r))
+				errs = append(errs, noTPViolation(filename, i+1+offset, r, line, text))
 			}
 			prevLine = ""
 			prevIsBlock = true
@@ -94,7 +98,7 @@ func CheckNoTrailingPunctuation(filename string, ctx *preprocess.Context, offset
 
 		if text, ok := setextHeadingText(first, line, prevLine, prevIsBlock); ok {
 			if r, ok := lastRuneInSet(text, punctuation); ok {
-				errs = append(errs, noTPViolation(filename, i+offset, r))
+				errs = append(errs, noTPViolation(filename, i+offset, r, prevLine, text))
 			}
 			prevLine = ""
 			prevIsBlock = true
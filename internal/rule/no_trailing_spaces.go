@@ -0,0 +1,124 @@
+package rule
+
+import (
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// CheckNoTrailingSpaces reports spaces and tabs at the end of lines outside
+// fenced code. With brSpaces above 0, exactly that many spaces before a line
+// that continues the paragraph are a hard line break and allowed. With
+// allowInContainers, lines in list items and block quotes are not checked.
+//
+// The fix removes the whitespace, but never a hard line break: a run of more
+// than brSpaces spaces is trimmed to brSpaces, and any other break made of
+// spaces becomes a backslash.
+func CheckNoTrailingSpaces(filename string, ctx *preprocess.Context, offset int, brSpaces int, allowInContainers bool) []LintError {
+	var errs []LintError
+	tables := tableLines(ctx)
+	inList := false
+	prevBlank := true
+
+	for i := 0; i < ctx.Len(); i++ {
+		line := strings.TrimSuffix(ctx.Line(i), "\r")
+		blank := strings.TrimSpace(line) == ""
+		if !ctx.InFencedCode(i) && !blank {
+			inList = continuesList(line, inList, prevBlank)
+		}
+		prevBlank = blank
+
+		if ctx.InFencedCode(i) {
+			continue
+		}
+		end := len(strings.TrimRight(line, " \t"))
+		if end == len(line) {
+			continue
+		}
+		if allowInContainers && (inList || isBlockQuote(line)) {
+			continue
+		}
+
+		keep, newText := end, ""
+		if !blank && isHardBreak(ctx, tables, i, line, end) {
+			n := len(line) - end
+			switch {
+			case n == brSpaces:
+				continue
+			case brSpaces >= 2 && n > brSpaces:
+				keep = end + brSpaces
+			default:
+				newText = `\`
+			}
+		}
+		e := atSpan(LintError{
+			File:    filename,
+			Line:    offset + i + 1,
+			Message: "no-trailing-spaces: trailing whitespace found",
+		}, line, end, len(line))
+		errs = append(errs, withFix(e, replaceBytes(e.Line, line, keep, len(line), newText)))
+	}
+
+	return errs
+}
+
+// isHardBreak reports whether the whitespace after end on line i makes a
+// CommonMark hard line break: two or more spaces at the end of a paragraph
+// line that the next line continues.
+func isHardBreak(ctx *preprocess.Context, tables map[int]bool, i int, line string, end int) bool {
+	if len(line)-end < 2 || strings.Trim(line[end:], " ") != "" {
+		return false
+	}
+	if !isParagraphLine(ctx, tables, i, line) || i+1 >= ctx.Len() || ctx.InFencedCode(i+1) {
+		return false
+	}
+	next := strings.TrimSuffix(ctx.Line(i+1), "\r")
+	if !isParagraphLine(ctx, tables, i+1, next) || isListItem(next) || isSetextUnderline(next) {
+		return false
+	}
+	return isBlockQuote(next) == isBlockQuote(line)
+}
+
+// isParagraphLine reports whether line i is text that can be part of a
+// paragraph, as opposed to a blank line, heading, thematic break, table row,
+// code or HTML.
+func isParagraphLine(ctx *preprocess.Context, tables map[int]bool, i int, line string) bool {
+	if inBlockContext(ctx, i) || tables[i] {
+		return false
+	}
+	s := strings.TrimLeft(reQuotePrefix.ReplaceAllString(line, ""), " ")
+	return strings.TrimSpace(s) != "" && !isATXHeading(s) && !isThematicBreak(s)
+}
+
+// isSetextUnderline reports whether line would make the line before it a
+// setext heading, whose trailing spaces are not a break.
+func isSetextUnderline(line string) bool {
+	s := strings.TrimSpace(line)
+	return s != "" && (strings.Trim(s, "=") == "" || strings.Trim(s, "-") == "")
+}
+
+// tableLines returns the lines of ctx in tables.
+func tableLines(ctx *preprocess.Context) map[int]bool {
+	lines := map[int]bool{}
+	for _, span := range ctx.TableSpans() {
+		for i := span.Start; i <= span.End; i++ {
+			lines[i] = true
+		}
+	}
+	return lines
+}
+
+// continuesList reports whether the non-blank line is in a list, given
+// whether the previous line was and whether it was blank. After a blank
+// line, only an indented line or a new item stays in the list.
+func continuesList(line string, inList, prevBlank bool) bool {
+	if isListItem(line) {
+		return true
+	}
+	return inList && (!prevBlank || line[0] == ' ' || line[0] == '\t')
+}
+
+// isBlockQuote reports whether line is in a block quote.
+func isBlockQuote(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " \t"), ">")
+}
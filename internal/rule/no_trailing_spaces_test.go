@@ -0,0 +1,60 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestCheckNoTrailingSpaces(t *testing.T) {
+	const found = "no-trailing-spaces: trailing whitespace found"
+	tests := []struct {
+		name              string
+		content           string
+		brSpaces          int
+		allowInContainers bool
+		want              []string
+	}{
+		{"clean", "# Title\n\nText.\n", 0, false, nil},
+		{"spaces and tabs", "# Title \n\nText\t\nmore  \n", 0, false, []string{"1: " + found, "3: " + found, "4: " + found}},
+		{"whitespace-only line", "a\n   \nb\n", 0, false, []string{"2: " + found}},
+		{"CRLF line endings", "a\r\nb  \r\n", 0, false, []string{"2: " + found}},
+		{"fenced code is skipped", "```\ncode  \n```\n", 0, false, nil},
+		{"hard break allowed", "line one  \nline two\n", 2, false, nil},
+		{"hard break disallowed by default", "line one  \nline two\n", 0, false, []string{"1: " + found}},
+		{"hard break needs the exact count", "one   \ntwo \nthree\n", 2, false, []string{"1: " + found, "2: " + found}},
+		{"hard break at paragraph end", "one  \n\ntwo  \n", 2, false, []string{"1: " + found, "3: " + found}},
+		{"hard break with a tab", "one \t\ntwo\n", 2, false, []string{"1: " + found}},
+		{"hard break before a fence", "one  \n```\ncode\n```\n", 2, false, []string{"1: " + found}},
+		{"heading is no break", "# Title  \ntext\n", 2, false, []string{"1: " + found}},
+		{"list item is no continuation", "a  \n- b\n", 2, false, []string{"1: " + found}},
+		{"setext underline is no continuation", "Title  \n---\n", 2, false, []string{"1: " + found}},
+		{"table row is no break", "| a |  \n| - |\n| 1 |\n", 2, false, []string{"1: " + found}},
+		{"break in a block quote", "> one  \n> two\n", 2, false, nil},
+		{"list item", "- item  \n  more \n", 0, false, []string{"1: " + found, "2: " + found}},
+		{"list item allowed", "- item  \n  more \n\n  para \n\nafter \n", 0, true, []string{"6: " + found}},
+		{"block quote allowed", "> quote \n>  \n\nafter \n", 0, true, []string{"4: " + found}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := preprocess.Scan(strings.Split(tt.content, "\n"))
+			got := findings(CheckNoTrailingSpaces("test.md", ctx, 0, tt.brSpaces, tt.allowInContainers))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckNoTrailingSpaces_Span(t *testing.T) {
+	errs := CheckNoTrailingSpaces("test.md", preprocess.Scan([]string{"abc \t"}), 3, 0, false)
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1", len(errs))
+	}
+	e := errs[0]
+	if e.Line != 4 || e.Column != 4 || e.EndColumn != 6 {
+		t.Errorf("got line %d, columns %d-%d, want line 4, columns 4-6", e.Line, e.Column, e.EndColumn)
+	}
+}
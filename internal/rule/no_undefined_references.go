@@ -0,0 +1,33 @@
+package rule
+
+import (
+	"fmt"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// CheckNoUndefinedReferences reports full ("[text][label]") and collapsed
+// ("[label][]") reference links and images whose label has no definition in
+// the document. A shortcut reference ("[label]") with no definition renders
+// as plain bracketed text, which is often intended, so it is reported only
+// with checkShortcuts.
+func CheckNoUndefinedReferences(filename string, ctx *preprocess.Context, offset int, checkShortcuts bool) []LintError {
+	refs := collectReferences(ctx, offset)
+	if len(refs.uses) == 0 {
+		return nil
+	}
+	defs := refs.firstDefinitions()
+
+	var errs []LintError
+	for _, u := range refs.uses {
+		if _, ok := defs[u.label]; ok || (u.kind == refShortcut && !checkShortcuts) {
+			continue
+		}
+		errs = append(errs, atSpan(LintError{
+			File:    filename,
+			Line:    u.line,
+			Message: fmt.Sprintf("no-undefined-references: reference [%s] has no definition", u.text),
+		}, u.raw, u.start, u.end))
+	}
+	return errs
+}
@@ -0,0 +1,52 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestCheckNoUndefinedReferences(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		shortcuts bool
+		want      []string
+	}{
+		{"full reference", "[text][ref]\n\n[ref]: https://example.com\n", false, nil},
+		{"collapsed reference", "[Ref][]\n\n[ref]: https://example.com\n", false, nil},
+		{"label normalization", "[text][Foo  Bar]\n\n[foo bar]: https://example.com\n", false, nil},
+		{"definition before use", "[ref]: https://example.com\n\n[text][ref]\n", false, nil},
+		{"undefined full", "See [text][missing].\n", false, []string{"1: no-undefined-references: reference [missing] has no definition"}},
+		{"undefined collapsed", "See [Missing][].\n", false, []string{"1: no-undefined-references: reference [Missing] has no definition"}},
+		{"undefined image", "![logo][logo]\n", false, []string{"1: no-undefined-references: reference [logo] has no definition"}},
+		{"nested in a link", "[![badge][img]](https://example.com)\n", false, []string{"1: no-undefined-references: reference [img] has no definition"}},
+		{"shortcut ignored by default", "Use array[0] or [this].\n", false, nil},
+		{"shortcut checked", "See [this] and [ref].\n\n[ref]: https://example.com\n", true, []string{"1: no-undefined-references: reference [this] has no definition"}},
+		{"task boxes and alerts are not shortcuts", "- [ ] todo\n- [x] done\n\n> [!NOTE]\n> Text\n", true, nil},
+		{"footnotes are not references", "Text[^1].\n\n[^1]: Note.\n", true, nil},
+		{"inline links are not references", "[text](https://example.com) [a](b)\n", true, nil},
+		{"code is skipped", "`[a][b]`\n\n```\n[a][b]\n```\n", false, nil},
+		{"escaped bracket", "\\[a][b]\n", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findings(CheckNoUndefinedReferences("test.md", preprocess.Scan(strings.Split(tt.content, "\n")), 0, tt.shortcuts))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckNoUndefinedReferences_Position(t *testing.T) {
+	errs := CheckNoUndefinedReferences("test.md", preprocess.Scan([]string{"See [text][gone] here."}), 2, false)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if e := errs[0]; e.Line != 3 || e.Column != 5 || e.EndColumn != 17 {
+		t.Errorf("got line %d, columns %d-%d", e.Line, e.Column, e.EndColumn)
+	}
+}
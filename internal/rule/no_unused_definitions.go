@@ -0,0 +1,36 @@
+package rule
+
+import (
+	"fmt"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// CheckNoUnusedDefinitions reports link reference definitions that no
+// reference in the document uses. Later duplicates of a definition are left
+// to no-duplicate-definitions.
+func CheckNoUnusedDefinitions(filename string, ctx *preprocess.Context, offset int) []LintError {
+	refs := collectReferences(ctx, offset)
+	if len(refs.defs) == 0 {
+		return nil
+	}
+	used := make(map[string]bool, len(refs.uses))
+	for _, u := range refs.uses {
+		used[u.label] = true
+	}
+
+	var errs []LintError
+	seen := make(map[string]bool, len(refs.defs))
+	for _, d := range refs.defs {
+		if used[d.label] || seen[d.label] {
+			continue
+		}
+		seen[d.label] = true
+		errs = append(errs, atSpan(LintError{
+			File:    filename,
+			Line:    d.line,
+			Message: fmt.Sprintf("no-unused-definitions: definition [%s] is never used", d.text),
+		}, d.raw, d.start, d.end))
+	}
+	return errs
+}
@@ -0,0 +1,35 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestCheckNoUnusedDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"used by full reference", "[text][ref]\n\n[ref]: https://example.com\n", nil},
+		{"used by collapsed reference", "[Ref][]\n\n[ref]: https://example.com\n", nil},
+		{"used by shortcut reference", "See [ref].\n\n[ref]: https://example.com\n", nil},
+		{"used by an image", "![logo]\n\n[logo]: ./logo.png\n", nil},
+		{"used inside a link", "[![badge][img]](https://example.com)\n\n[img]: ./badge.svg\n", nil},
+		{"unused", "Text.\n\n[used]: https://a.example\n[Unused]: https://b.example\n\n[used][]\n", []string{"4: no-unused-definitions: definition [Unused] is never used"}},
+		{"duplicates reported once", "Text.\n\n[a]: https://a.example\n[A]: https://b.example\n", []string{"3: no-unused-definitions: definition [a] is never used"}},
+		{"footnote definitions are not references", "Text.\n\n[^1]: Note.\n", nil},
+		{"definitions in code are skipped", "```\n[a]: https://a.example\n```\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findings(CheckNoUnusedDefinitions("test.md", preprocess.Scan(strings.Split(tt.content, "\n")), 0))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
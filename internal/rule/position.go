@@ -0,0 +1,42 @@
+package rule
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// textSpan is a piece of text found at byte range [start, end) within a line.
+type textSpan struct {
+	text       string
+	start, end int
+}
+
+// charColumn converts a byte offset within line to a 1-based character column.
+func charColumn(line string, byteOffset int) int {
+	if byteOffset > len(line) {
+		byteOffset = len(line)
+	}
+	return utf8.RuneCountInString(line[:byteOffset]) + 1
+}
+
+// atSpan records line[start:end] (byte offsets) as the location of e.
+// EndColumn is exclusive: it is the column just past the last character.
+func atSpan(e LintError, line string, start, end int) LintError {
+	e.Column = charColumn(line, start)
+	e.EndLine = e.Line
+	e.EndColumn = charColumn(line, end)
+	return e
+}
+
+// atContent records the non-blank content of line as the location of e.
+// A blank line yields a zero-width span at column 1.
+func atContent(e LintError, line string) LintError {
+	start, end := contentSpan(line)
+	return atSpan(e, line, start, end)
+}
+
+func contentSpan(line string) (int, int) {
+	trimmed := strings.TrimRight(line, " \t\r")
+	start := len(trimmed) - len(strings.TrimLeft(trimmed, " \t"))
+	return start, len(trimmed)
+}
@@ -0,0 +1,214 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// withoutSpan clears the position and fix fields so table tests written
+// before columns and fixes existed can keep comparing whole LintError values.
+func withoutSpan(e LintError) LintError {
+	e.Column, e.EndLine, e.EndColumn = 0, 0, 0
+	e.Fix = nil
+	return e
+}
+
+func TestCharColumn(t *testing.T) {
+	tests := []struct {
+		line   string
+		offset int
+		want   int
+	}{
+		{"abc", 0, 1},
+		{"abc", 2, 3},
+		{"abc", 3, 4},
+		{"abc", 10, 4},
+		{"日本語x", len("日本語"), 4},
+	}
+	for _, tt := range tests {
+		if got := charColumn(tt.line, tt.offset); got != tt.want {
+			t.Errorf("charColumn(%q, %d) = %d, want %d", tt.line, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestContentSpan(t *testing.T) {
+	tests := []struct {
+		line       string
+		start, end int
+	}{
+		{"## Title", 0, 8},
+		{"  ## Title  \r", 2, 10},
+		{"", 0, 0},
+		{"   ", 0, 0},
+	}
+	for _, tt := range tests {
+		start, end := contentSpan(tt.line)
+		if start != tt.start || end != tt.end {
+			t.Errorf("contentSpan(%q) = (%d, %d), want (%d, %d)", tt.line, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+type span struct{ line, col, endLine, endCol int }
+
+func spanOf(e LintError) span {
+	return span{e.Line, e.Column, e.EndLine, e.EndColumn}
+}
+
+func TestRuleSpans(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		check   func(*preprocess.Context, []string) []LintError
+		want    []span
+	}{
+		{
+			name:    "no-bare-urls covers the URL",
+			content: "See https://example.com/x. now\n",
+			check:   func(ctx *preprocess.Context, _ []string) []LintError { return CheckNoBareURLs("f.md", ctx, 0) },
+			want:    []span{{1, 5, 1, 26}},
+		},
+		{
+			name:    "no-bare-urls counts characters, not bytes",
+			content: "日本 https://example.com\n",
+			check:   func(ctx *preprocess.Context, _ []string) []LintError { return CheckNoBareURLs("f.md", ctx, 0) },
+			want:    []span{{1, 4, 1, 23}},
+		},
+		{
+			name:    "no-hard-tabs covers each tab",
+			content: "a\tb `x` \tc\n",
+			check:   func(ctx *preprocess.Context, _ []string) []LintError { return CheckNoHardTabs("f.md", ctx, 0) },
+			want:    []span{{1, 2, 1, 3}, {1, 9, 1, 10}},
+		},
+		{
+			name:    "consistent-emphasis-style covers the emphasis span",
+			content: "*one* and _two_\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckConsistentEmphasisStyle("f.md", ctx, 0, "consistent")
+			},
+			want: []span{{1, 11, 1, 16}},
+		},
+		{
+			name:    "consistent-emphasis-style after code span with multibyte text",
+			content: "`日本` _two_\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckConsistentEmphasisStyle("f.md", ctx, 0, "asterisk")
+			},
+			want: []span{{1, 6, 1, 11}},
+		},
+		{
+			name:    "link-fragments covers the link",
+			content: "## Intro\n\nSee ![x](#a) [here](#missing).\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckLinkFragments("f.md", ctx, 0, nil)
+			},
+			want: []span{{3, 14, 3, 30}},
+		},
+		{
+			name:    "link-fragments covers reference usage",
+			content: "## Intro\n\nSee [here][ref].\n\n[ref]: #missing\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckLinkFragments("f.md", ctx, 0, nil)
+			},
+			want: []span{{3, 5, 3, 16}},
+		},
+		{
+			name:    "no-empty-links covers the link",
+			content: "A [x]() b\n",
+			check:   func(ctx *preprocess.Context, _ []string) []LintError { return CheckNoEmptyLinks("f.md", ctx, 0) },
+			want:    []span{{1, 3, 1, 8}},
+		},
+		{
+			name:    "empty-alt-text covers the image",
+			content: "A ![](a.png) b\n",
+			check:   func(ctx *preprocess.Context, _ []string) []LintError { return CheckEmptyAltText("f.md", ctx, 0) },
+			want:    []span{{1, 3, 1, 13}},
+		},
+		{
+			name:    "consistent-list-marker covers the marker",
+			content: "- a\n  * b\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckConsistentListMarker("f.md", ctx, 0, "consistent")
+			},
+			want: []span{{2, 3, 2, 4}},
+		},
+		{
+			name:    "consistent-code-fence covers the marker run",
+			content: "```go\nx\n```\n\n  ~~~~\ny\n  ~~~~\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckConsistentCodeFence("f.md", ctx, 0, "consistent")
+			},
+			want: []span{{5, 3, 5, 7}},
+		},
+		{
+			name:    "no-trailing-punctuation covers the final rune",
+			content: "## Hello world! ##\n\nSetext。\n-------\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckNoTrailingPunctuation("f.md", ctx, 0, "!。")
+			},
+			want: []span{{1, 15, 1, 16}, {3, 7, 3, 8}},
+		},
+		{
+			name:    "heading-level covers the heading",
+			content: "  # Title\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckHeadingLevels("f.md", ctx, 0, 2)
+			},
+			want: []span{{1, 3, 1, 10}},
+		},
+		{
+			name:    "max-line-length covers the overflow",
+			content: "abcdefghij\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckMaxLineLength("f.md", ctx, 0, 4)
+			},
+			want: []span{{1, 5, 1, 11}},
+		},
+		{
+			name:    "blanks-around-headings following error points at heading",
+			content: "## A\ntext\n",
+			check: func(ctx *preprocess.Context, _ []string) []LintError {
+				return CheckBlanksAroundHeadings("f.md", ctx, 0)
+			},
+			want: []span{{1, 1, 1, 5}},
+		},
+		{
+			name:    "final-blank-line is a zero-width span at end of file",
+			content: "## A\n\ntext",
+			check:   func(_ *preprocess.Context, lines []string) []LintError { return CheckFinalBlankLine("f.md", lines, 0) },
+			want:    []span{{3, 5, 3, 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(tt.content, "\n")
+			got := tt.check(preprocess.Scan(lines), lines)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d errors, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if spanOf(got[i]) != tt.want[i] {
+					t.Errorf("error[%d] span = %+v, want %+v", i, spanOf(got[i]), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExtractExternalLinks_Columns(t *testing.T) {
+	lines := strings.Split("日本 [a](https://a.example/x)\n\nsee https://b.example\n", "\n")
+	got := extractExternalLinks(preprocess.Scan(lines), 2)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 links, got %+v", got)
+	}
+	if got[0].URL != "https://a.example/x" || got[0].Line != 3 || got[0].column != 8 || got[0].endColumn != 27 {
+		t.Errorf("unexpected inline link span: %+v", got[0])
+	}
+	if got[1].URL != "https://b.example" || got[1].Line != 5 || got[1].column != 5 || got[1].endColumn != 22 {
+		t.Errorf("unexpected bare link span: %+v", got[1])
+	}
+}
@@ -0,0 +1,175 @@
+package rule
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// refKind is the form of a reference link or image.
+type refKind int
+
+const (
+	refFull      refKind = iota // [text][label]
+	refCollapsed                // [label][]
+	refShortcut                 // [label]
+)
+
+// refDefinition is a link reference definition, "[label]: destination".
+type refDefinition struct {
+	label string // normalized
+	text  string // as written
+	dest  string // as written, "<...>" included
+	line  int    // 1-based line number in the file
+	raw   string // the line, for columns
+	start int    // byte range of "[label]: destination" in raw
+	end   int
+}
+
+// refUse is a reference link or image.
+type refUse struct {
+	label string // normalized
+	text  string // the label as written
+	kind  refKind
+	image bool
+	line  int
+	raw   string
+	start int
+	end   int
+}
+
+// references are the reference definitions and uses of a document, in
+// document order. Duplicate definitions are included; the first one for a
+// label is the one that counts.
+type references struct {
+	defs []refDefinition
+	uses []refUse
+}
+
+var (
+	// reRefDefinition matches a definition, after any blockquote markers.
+	// The destination may be on the next line.
+	reRefDefinition = regexp.MustCompile(`^ {0,3}\[((?:[^\[\]\\]|\\.)+)\]:[ \t]*(<[^<>]*>|\S+)?`)
+	reRefUse        = regexp.MustCompile(`(!?)\[((?:[^\[\]\\]|\\.|\[(?:[^\[\]\\]|\\.)*\])*)\](?:\[((?:[^\[\]\\]|\\.)*)\])?`)
+	reQuotePrefix   = regexp.MustCompile(`^(?: {0,3}> ?)*`)
+	reTaskItem      = regexp.MustCompile(`^[ \t]*(?:> ?)*[ \t]*(?:[-*+]|\d{1,9}[.)])[ \t]+$`)
+)
+
+// labelFolder folds case as CommonMark specifies for matching labels.
+var labelFolder = cases.Fold()
+
+// normalizeLabel returns the form of label that references and definitions
+// are matched by: case-folded, with runs of whitespace collapsed to one
+// space and leading and trailing whitespace removed.
+func normalizeLabel(label string) string {
+	return labelFolder.String(strings.Join(strings.Fields(label), " "))
+}
+
+// collectReferences returns the reference definitions and the full,
+// collapsed and shortcut references in ctx, outside code, HTML blocks and
+// comments. Footnotes ("[^1]"), GitHub alerts ("[!NOTE]") and task list
+// boxes are not references.
+func collectReferences(ctx *preprocess.Context, offset int) references {
+	var refs references
+	for i := 0; i < ctx.Len(); i++ {
+		if inBlockContext(ctx, i) {
+			continue
+		}
+		line := ctx.Sanitized(i)
+		if !strings.ContainsRune(line, '[') {
+			continue
+		}
+		if d, ok := refDefinitionAt(ctx, i, offset); ok {
+			refs.defs = append(refs.defs, d)
+			continue
+		}
+		refs.uses = appendRefUses(refs.uses, line, ctx.Line(i), i+1+offset, 0)
+	}
+	return refs
+}
+
+// refDefinitionAt parses the definition on line i of ctx, if there is one.
+func refDefinitionAt(ctx *preprocess.Context, i, offset int) (refDefinition, bool) {
+	line := ctx.Sanitized(i)
+	prefix := len(reQuotePrefix.FindString(line))
+	m := reRefDefinition.FindStringSubmatchIndex(line[prefix:])
+	if m == nil {
+		return refDefinition{}, false
+	}
+	text := line[prefix+m[2] : prefix+m[3]]
+	if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "^") {
+		return refDefinition{}, false
+	}
+	d := refDefinition{
+		label: normalizeLabel(text), text: text,
+		line: i + 1 + offset, raw: ctx.Line(i), start: prefix + m[0], end: prefix + m[1],
+	}
+	if m[4] >= 0 {
+		d.dest = line[prefix+m[4] : prefix+m[5]]
+	} else if i+1 < ctx.Len() && !inBlockContext(ctx, i+1) {
+		next := strings.Fields(ctx.Sanitized(i + 1))
+		if len(next) == 0 {
+			return refDefinition{}, false
+		}
+		d.dest = next[0]
+	}
+	return d, true
+}
+
+// appendRefUses appends the references in text, which starts at byte base
+// of raw, including those nested in the text of a link as in a linked
+// badge. Inline links and images are not references, but their text is
+// searched.
+func appendRefUses(uses []refUse, text, raw string, line, base int) []refUse {
+	for _, m := range reRefUse.FindAllStringSubmatchIndex(text, -1) {
+		open := m[0]
+		if m[3] > m[2] {
+			open++
+		}
+		if open > 0 && text[open-1] == '\\' {
+			continue
+		}
+		inner := text[m[4]:m[5]]
+		if strings.ContainsRune(inner, '[') {
+			uses = appendRefUses(uses, inner, raw, line, base+m[4])
+		}
+		if m[1] < len(text) && text[m[1]] == '(' {
+			continue
+		}
+		u := refUse{text: inner, kind: refShortcut, image: m[3] > m[2], line: line, raw: raw, start: base + m[0], end: base + m[1]}
+		switch {
+		case m[6] >= 0 && m[7] > m[6]:
+			u.kind, u.text = refFull, text[m[6]:m[7]]
+		case m[6] >= 0:
+			u.kind = refCollapsed
+		case isTaskBox(inner, raw[:base+m[0]]) || strings.HasPrefix(inner, "!"):
+			continue
+		}
+		if strings.TrimSpace(u.text) == "" || strings.HasPrefix(u.text, "^") {
+			continue
+		}
+		u.label = normalizeLabel(u.text)
+		uses = append(uses, u)
+	}
+	return uses
+}
+
+// isTaskBox reports whether a shortcut "[inner]" after before is the box of
+// a task list item.
+func isTaskBox(inner, before string) bool {
+	return (inner == " " || inner == "x" || inner == "X") && reTaskItem.MatchString(before)
+}
+
+// firstDefinitions maps each label to its first definition.
+func (r references) firstDefinitions() map[string]refDefinition {
+	defs := make(map[string]refDefinition, len(r.defs))
+	for _, d := range r.defs {
+		if _, ok := defs[d.label]; !ok {
+			defs[d.label] = d
+		}
+	}
+	return defs
+}
@@ -0,0 +1,81 @@
+package rule
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// findings formats errs as "line: message" for comparison.
+func findings(errs []LintError) []string {
+	var out []string
+	for _, e := range errs {
+		out = append(out, fmt.Sprintf("%d: %s", e.Line, e.Message))
+	}
+	return out
+}
+
+func TestNormalizeLabel(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Foo", "foo"},
+		{"  foo \t bar\n", "foo bar"},
+		{"ÄÖÜ", "äöü"},
+		{"Straße", "strasse"},
+		{"STRASSE", "strasse"},
+	}
+	for _, tt := range tests {
+		if got := normalizeLabel(tt.in); got != tt.want {
+			t.Errorf("normalizeLabel(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCollectReferences(t *testing.T) {
+	content := strings.Join([]string{
+		"See [the docs][Docs], [Guide][] and [faq].",
+		"[![badge][img]][ci] and [inline](https://example.com) and ![logo][]",
+		"",
+		"- [ ] task",
+		"- [x] done",
+		"> [!NOTE]",
+		"Footnote[^1], escaped \\[not], `[code][x]`.",
+		"",
+		"[docs]: https://example.com/docs \"Docs\"",
+		"> [Guide]: <./guide.md>",
+		"[next line]:",
+		"  https://example.com/next",
+		"[^1]: A footnote.",
+		"```",
+		"[fenced]: https://example.com",
+		"```",
+	}, "\n")
+	refs := collectReferences(preprocess.Scan(strings.Split(content, "\n")), 10)
+
+	var uses []string
+	for _, u := range refs.uses {
+		uses = append(uses, fmt.Sprintf("%d %s %v %v", u.line, u.label, u.kind, u.image))
+	}
+	wantUses := []string{
+		"11 docs 0 false", "11 guide 1 false", "11 faq 2 false",
+		"12 img 0 true", "12 ci 0 false", "12 logo 1 true",
+	}
+	if !reflect.DeepEqual(uses, wantUses) {
+		t.Errorf("uses: got %q, want %q", uses, wantUses)
+	}
+
+	var defs []string
+	for _, d := range refs.defs {
+		defs = append(defs, fmt.Sprintf("%d %s %s", d.line, d.label, d.dest))
+	}
+	wantDefs := []string{
+		"19 docs https://example.com/docs",
+		"20 guide <./guide.md>",
+		"21 next line https://example.com/next",
+	}
+	if !reflect.DeepEqual(defs, wantDefs) {
+		t.Errorf("defs: got %q, want %q", defs, wantDefs)
+	}
+}
@@ -0,0 +1,294 @@
+package rule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// Rule is a lint rule the linter can run. Built-in rules and rules compiled
+// in from other packages alike register themselves with Register.
+type Rule interface {
+	// Name is the rule's key in the config and in reported violations.
+	Name() string
+	// DefaultSeverity is the severity of a rule the config does not list,
+	// when it runs because "default" is true.
+	DefaultSeverity() config.RuleSeverity
+	// Options declares the options the rule accepts. The config is
+	// validated against it before any file is linted.
+	Options() []Option
+	// Check returns the violations in one file. ctx holds the file's lines
+	// after frontmatter, which starts at line offset+1 of the file. opts has
+	// a value for every declared option.
+	Check(path string, ctx *preprocess.Context, offset int, opts Options) []LintError
+}
+
+// FileRule is a Rule that needs the whole file, frontmatter included, and
+// can fail for reasons other than the file's content, such as a plugin
+// process that crashes. The linter calls CheckFile instead of Check.
+type FileRule interface {
+	Rule
+	CheckFile(path, content string, ctx *preprocess.Context, offset int, opts Options) ([]LintError, error)
+}
+
+// UncachedRule is implemented by rules whose findings depend on more than
+// the path and content of the file checked, such as other files on disk.
+// The linter runs them on every file, even when the result cache holds the
+// findings of the other rules. FileRules are never cached either.
+type UncachedRule interface {
+	Rule
+	Uncached()
+}
+
+// OptionType is the JSON type an option accepts.
+type OptionType int
+
+const (
+	OptionString     OptionType = iota // string
+	OptionInt                          // int
+	OptionBool                         // bool
+	OptionStringList                   // []string
+	OptionIntList                      // []int
+	OptionObject                       // map[string]interface{}
+)
+
+var optionTypeNames = [...]string{"string", "integer", "boolean", "array of strings", "array of integers", "object"}
+
+func (t OptionType) String() string {
+	if int(t) < len(optionTypeNames) {
+		return optionTypeNames[t]
+	}
+	return fmt.Sprintf("OptionType(%d)", int(t))
+}
+
+// Option declares one rule option.
+type Option struct {
+	Name string
+	Type OptionType
+	// Default is used when the config omits the option. It has the Go type
+	// listed for Type.
+	Default interface{}
+	// Enum lists the accepted values of a string option. An empty string
+	// selects Default.
+	Enum []string
+	// Validate, when set, checks a value that already has the declared
+	// type. Its error completes the sentence "<rule>.<option> ...", e.g.
+	// "must be between 1 and 15, got 16".
+	Validate func(v interface{}) error
+}
+
+// IntRange returns a Validate function accepting integers in [min, max].
+func IntRange(min, max int) func(interface{}) error {
+	return func(v interface{}) error {
+		if n := v.(int); n < min || n > max {
+			return fmt.Errorf("must be between %d and %d, got %d", min, max, n)
+		}
+		return nil
+	}
+}
+
+// IntAtLeast returns a Validate function accepting integers >= min.
+func IntAtLeast(min int) func(interface{}) error {
+	return func(v interface{}) error {
+		if n := v.(int); n < min {
+			return fmt.Errorf("must be at least %d, got %d", min, n)
+		}
+		return nil
+	}
+}
+
+// Options holds a rule's resolved option values, keyed by option name.
+type Options map[string]interface{}
+
+// String returns the value of a string option.
+func (o Options) String(name string) string { s, _ := o[name].(string); return s }
+
+// Int returns the value of an integer option.
+func (o Options) Int(name string) int { n, _ := o[name].(int); return n }
+
+// Bool returns the value of a boolean option.
+func (o Options) Bool(name string) bool { b, _ := o[name].(bool); return b }
+
+// Strings returns the value of a string list option.
+func (o Options) Strings(name string) []string { s, _ := o[name].([]string); return s }
+
+// Ints returns the value of an integer list option.
+func (o Options) Ints(name string) []int { n, _ := o[name].([]int); return n }
+
+// Object returns the value of an object option.
+func (o Options) Object(name string) map[string]interface{} {
+	m, _ := o[name].(map[string]interface{})
+	return m
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Rule
+	registered = map[string]bool{}
+)
+
+// Register adds r to the rules every linter runs. It is meant to be called
+// from an init function and panics if a rule with the same name exists.
+func Register(r Rule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	name := r.Name()
+	if name == "" {
+		panic("rule: Register called with an empty rule name")
+	}
+	if registered[name] {
+		panic(fmt.Sprintf("rule: Register called twice for rule %q", name))
+	}
+	registered[name] = true
+	registry = append(registry, r)
+}
+
+// Registered returns every registered rule: the built-in rules in dispatch
+// order, then the others in the order they registered.
+func Registered() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Rule(nil), registry...)
+}
+
+// ResolveOptions validates raw, the options from the config, against r's
+// declared options and fills in defaults.
+func ResolveOptions(r Rule, raw map[string]interface{}) (Options, error) {
+	decl := r.Options()
+	opts := make(Options, len(decl))
+	known := make(map[string]bool, len(decl))
+	for _, o := range decl {
+		known[o.Name] = true
+		v, ok := raw[o.Name]
+		if !ok || v == nil {
+			opts[o.Name] = o.Default
+			continue
+		}
+		val, err := resolveOption(r.Name(), o, v)
+		if err != nil {
+			return nil, err
+		}
+		opts[o.Name] = val
+	}
+
+	var unknown []string
+	for k := range raw {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("gomarklint: unknown option %q for %s (valid options: %s)", unknown[0], r.Name(), optionNames(decl))
+	}
+	return opts, nil
+}
+
+func resolveOption(ruleName string, o Option, v interface{}) (interface{}, error) {
+	val, ok := decodeOption(o.Type, v)
+	if !ok {
+		var hint string
+		if len(o.Enum) > 0 {
+			hint = fmt.Sprintf(" (valid values: %s)", strings.Join(o.Enum, ", "))
+		}
+		return nil, fmt.Errorf("gomarklint: invalid value for %s.%s: expected %s, got %T (%#v)%s", ruleName, o.Name, o.Type, v, v, hint)
+	}
+	if len(o.Enum) > 0 {
+		s := val.(string)
+		if s == "" {
+			return o.Default, nil
+		}
+		if !contains(o.Enum, s) {
+			return nil, fmt.Errorf("gomarklint: invalid value %q for %s.%s (valid values: %s)", s, ruleName, o.Name, strings.Join(o.Enum, ", "))
+		}
+	}
+	if o.Validate != nil {
+		if err := o.Validate(val); err != nil {
+			return nil, fmt.Errorf("gomarklint: %s.%s %v", ruleName, o.Name, err)
+		}
+	}
+	return val, nil
+}
+
+// decodeOption converts a value decoded from JSON, or already of the Go
+// type of t, to the Go type of t.
+func decodeOption(t OptionType, v interface{}) (interface{}, bool) {
+	switch t {
+	case OptionString:
+		s, ok := v.(string)
+		return s, ok
+	case OptionInt:
+		switch n := v.(type) {
+		case float64:
+			return int(n), true
+		case int:
+			return n, true
+		}
+		return nil, false
+	case OptionBool:
+		b, ok := v.(bool)
+		return b, ok
+	case OptionStringList:
+		if s, ok := v.([]string); ok {
+			return s, true
+		}
+		arr, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		out := make([]string, 0, len(arr))
+		for _, item := range arr {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	case OptionIntList:
+		if n, ok := v.([]int); ok {
+			return n, true
+		}
+		arr, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		out := make([]int, 0, len(arr))
+		for _, item := range arr {
+			f, ok := item.(float64)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, int(f))
+		}
+		return out, true
+	case OptionObject:
+		m, ok := v.(map[string]interface{})
+		return m, ok
+	}
+	return nil, false
+}
+
+func optionNames(decl []Option) string {
+	if len(decl) == 0 {
+		return "none"
+	}
+	names := make([]string, len(decl))
+	for i, o := range decl {
+		names[i] = o.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+type testRule struct {
+	name    string
+	options []Option
+}
+
+func (r testRule) Name() string                         { return r.name }
+func (r testRule) DefaultSeverity() config.RuleSeverity { return config.SeverityWarning }
+func (r testRule) Options() []Option                    { return r.options }
+func (r testRule) Check(string, *preprocess.Context, int, Options) []LintError {
+	return nil
+}
+
+var optionsRule = testRule{name: "test-options", options: []Option{
+	{Name: "style", Type: OptionString, Default: "consistent", Enum: []string{"consistent", "dash"}},
+	{Name: "limit", Type: OptionInt, Default: 3, Validate: IntRange(1, 5)},
+	{Name: "strict", Type: OptionBool, Default: false},
+	{Name: "skip", Type: OptionStringList, Default: []string{}},
+	{Name: "codes", Type: OptionIntList},
+	{Name: "params", Type: OptionObject},
+}}
+
+func TestResolveOptions(t *testing.T) {
+	t.Run("fills defaults", func(t *testing.T) {
+		got, err := ResolveOptions(optionsRule, map[string]interface{}{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Options{"style": "consistent", "limit": 3, "strict": false, "skip": []string{}, "codes": nil, "params": nil}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("converts JSON values", func(t *testing.T) {
+		got, err := ResolveOptions(optionsRule, map[string]interface{}{
+			"style":  "dash",
+			"limit":  float64(5),
+			"strict": true,
+			"skip":   []interface{}{"a", "b"},
+			"codes":  []interface{}{float64(403)},
+			"params": map[string]interface{}{"k": "v"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String("style") != "dash" || got.Int("limit") != 5 || !got.Bool("strict") ||
+			!reflect.DeepEqual(got.Strings("skip"), []string{"a", "b"}) || !reflect.DeepEqual(got.Ints("codes"), []int{403}) ||
+			got.Object("params")["k"] != "v" {
+			t.Errorf("unexpected options: %#v", got)
+		}
+	})
+
+	t.Run("empty enum value selects default", func(t *testing.T) {
+		got, err := ResolveOptions(optionsRule, map[string]interface{}{"style": ""})
+		if err != nil || got.String("style") != "consistent" {
+			t.Errorf("got %#v, %v", got, err)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		raw  map[string]interface{}
+		want string
+	}{
+		{"enum", map[string]interface{}{"style": "star"}, `gomarklint: invalid value "star" for test-options.style (valid values: consistent, dash)`},
+		{"enum type", map[string]interface{}{"style": float64(1)}, `gomarklint: invalid value for test-options.style: expected string, got float64 (1) (valid values: consistent, dash)`},
+		{"int type", map[string]interface{}{"limit": "five"}, `gomarklint: invalid value for test-options.limit: expected integer, got string ("five")`},
+		{"validate", map[string]interface{}{"limit": float64(9)}, `gomarklint: test-options.limit must be between 1 and 5, got 9`},
+		{"list element", map[string]interface{}{"skip": []interface{}{"a", true}}, `expected array of strings`},
+		{"unknown", map[string]interface{}{"limt": float64(1)}, `gomarklint: unknown option "limt" for test-options (valid options: style, limit, strict, skip, codes, params)`},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveOptions(optionsRule, tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("rule without options", func(t *testing.T) {
+		_, err := ResolveOptions(testRule{name: "bare"}, map[string]interface{}{"x": true})
+		if err == nil || !strings.Contains(err.Error(), "(valid options: none)") {
+			t.Errorf("got %v", err)
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	mustPanic := func(t *testing.T, r Rule) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected Register(%q) to panic", r.Name())
+			}
+		}()
+		Register(r)
+	}
+	mustPanic(t, testRule{name: "no-hard-tabs"})
+	mustPanic(t, testRule{name: ""})
+}
+
+func TestRegistered_BuiltinsFirstInDispatchOrder(t *testing.T) {
+	got := Registered()
+	if len(got) < len(builtinRules) {
+		t.Fatalf("expected at least %d rules, got %d", len(builtinRules), len(got))
+	}
+	for i, r := range builtinRules {
+		if got[i].Name() != r.name {
+			t.Errorf("rule %d: got %q, want %q", i, got[i].Name(), r.name)
+		}
+	}
+}
+
+func TestBuiltinDefaults_MatchDefaultConfig(t *testing.T) {
+	fromJSON, err := config.Parse([]byte(config.DefaultConfigJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for source, cfg := range map[string]config.Config{"config.Default()": config.Default(), "DefaultConfigJSON": fromJSON} {
+		for _, r := range builtinRules {
+			rc, ok := cfg.Rules[r.name]
+			if !ok {
+				t.Errorf("built-in rule %q missing from %s", r.name, source)
+				continue
+			}
+			if _, err := ResolveOptions(r, rc.Options); err != nil {
+				t.Errorf("%s options for %q are invalid: %v", source, r.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package rule
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// markdownExts are the extensions of files whose headings relative-links
+// checks fragments against.
+var markdownExts = []string{".md", ".markdown", ".mdx"}
+
+// CheckRelativeLinks reports links, and reference definitions, to relative
+// paths that do not exist, resolved against the directory of filename. A
+// fragment on a link to a Markdown file must match one of its headings,
+// slugged as options configure, or an HTML anchor in it. Images are left to
+// local-assets.
+func CheckRelativeLinks(filename string, ctx *preprocess.Context, offset int, options map[string]interface{}) []LintError {
+	slugger := makeSlugger(parseSlugAlgorithm(options), options)
+	dir := filepath.Dir(filename)
+	anchors := map[string]map[string]bool{} // by target, read once per file
+
+	var errs []LintError
+	for _, t := range localTargets(ctx, offset) {
+		if t.image || strings.HasPrefix(t.path, "/") {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(t.path))
+		info, err := os.Stat(target)
+		if err != nil {
+			errs = append(errs, atSpan(LintError{
+				File:    filename,
+				Line:    t.line,
+				Message: fmt.Sprintf("relative-links: %s not found", t.path),
+			}, t.raw, t.start, t.end))
+			continue
+		}
+		if t.fragment == "" || info.IsDir() || !isMarkdownFile(target) {
+			continue
+		}
+		set, ok := anchors[target]
+		if !ok {
+			set = markdownFileAnchors(target, slugger)
+			anchors[target] = set
+		}
+		if set != nil && !hasFragment(set, t.fragment) {
+			errs = append(errs, atSpan(LintError{
+				File:    filename,
+				Line:    t.line,
+				Message: fmt.Sprintf("relative-links: fragment #%s not found in %s", t.fragment, t.path),
+			}, t.raw, t.start, t.end))
+		}
+	}
+	return errs
+}
+
+func isMarkdownFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range markdownExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownFileAnchors returns the heading slugs and HTML anchors of the
+// Markdown file at path, or nil if it cannot be read.
+func markdownFileAnchors(path string, slugger func(string) string) map[string]bool {
+	content, err := file.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	body, _ := file.StripFrontmatter(content)
+	anchors := htmlAnchors([]byte(body))
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for slug := range collectHeadingSlugs(preprocess.Scan(lines), slugger) {
+		anchors[slug] = true
+	}
+	return anchors
+}
+
+// hasFragment reports whether fragment, as written or percent-decoded, is
+// among anchors.
+func hasFragment(anchors map[string]bool, fragment string) bool {
+	if anchors[fragment] {
+		return true
+	}
+	decoded, err := url.PathUnescape(fragment)
+	return err == nil && anchors[decoded]
+}
@@ -0,0 +1,95 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// writeTree creates files, keyed by slash-separated path, under a temporary
+// directory and returns it.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheckRelativeLinks(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"guide/setup.md":     "---\ntitle: Setup\n---\n\n# Setup\n\n## Install\n\n## Install\n\n<a id=\"legacy-anchor\"></a>\n\n## Café\n",
+		"guide/notes.txt":    "notes\n",
+		"guide/zenn.md":      "## Hello, World!\n",
+		"img/logo.png":       "",
+		"docs/moved/x.md":    "",
+		"docs/with space.md": "# Title\n",
+	})
+	filename := filepath.Join(dir, "docs", "index.md")
+
+	tests := []struct {
+		name    string
+		content string
+		opts    map[string]interface{}
+		want    []string
+	}{
+		{"existing file", "[setup](../guide/setup.md)\n", nil, nil},
+		{"existing heading", "[install](../guide/setup.md#install)\n", nil, nil},
+		{"duplicate heading suffix", "[again](../guide/setup.md#install-1)\n", nil, nil},
+		{"html anchor", "[old](../guide/setup.md#legacy-anchor)\n", nil, nil},
+		{"encoded fragment", "[café](../guide/setup.md#caf%C3%A9)\n", nil, nil},
+		{"frontmatter is not a heading", "[t](../guide/setup.md#title-setup)\n", nil, []string{"relative-links: fragment #title-setup not found in ../guide/setup.md"}},
+		{"directory", "[moved](moved/)\n", nil, nil},
+		{"angle brackets and spaces", "[s](<with space.md#title>)\n", nil, nil},
+		{"percent-encoded path", "[s](with%20space.md)\n", nil, nil},
+		{"title and query", "[s](../guide/setup.md?plain=1 \"Setup\")\n", nil, nil},
+		{"missing file", "See [setup](../guide/install.md#install).\n", nil, []string{"relative-links: ../guide/install.md not found"}},
+		{"missing heading", "[x](../guide/setup.md#installation)\n", nil, []string{"relative-links: fragment #installation not found in ../guide/setup.md"}},
+		{"fragment on a non-Markdown file", "[n](../guide/notes.txt#L3)\n", nil, nil},
+		{"slug algorithm", "[z](../guide/zenn.md#hello-world)\n", map[string]interface{}{"slug-algorithm": "zenn"}, []string{"relative-links: fragment #hello-world not found in ../guide/zenn.md"}},
+		{"reference definition", "[setup][s]\n\n[s]: ../guide/gone.md\n", nil, []string{"relative-links: ../guide/gone.md not found"}},
+		{"link around a badge", "[![logo](../img/logo.png)](../guide/gone.md)\n", nil, []string{"relative-links: ../guide/gone.md not found"}},
+		{"images are not links", "![logo](../img/missing.png)\n\n![alt][i]\n\n[i]: ../img/missing.png\n", nil, nil},
+		{"skipped destinations", "[a](https://example.com/x.md) [b](/abs.md) [c](#here) [d](mailto:x@example.com) [e]({{< relref \"x.md\" >}})\n", nil, nil},
+		{"code is skipped", "`[a](gone.md)`\n\n```\n[b](gone.md)\n```\n", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			if opts == nil {
+				opts = map[string]interface{}{"slug-algorithm": "github"}
+			}
+			ctx := preprocess.Scan(strings.Split(tt.content, "\n"))
+			var got []string
+			for _, e := range CheckRelativeLinks(filename, ctx, 0, opts) {
+				got = append(got, e.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRelativeLinks_Position(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\ntitle: x\n---\n\nText and [gone](gone.md) here.\n"
+	body := strings.Split(content, "\n")[4:]
+	errs := CheckRelativeLinks(filepath.Join(dir, "a.md"), preprocess.Scan(body), 4, map[string]interface{}{})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if e := errs[0]; e.Line != 5 || e.Column != 10 || e.EndColumn != 25 {
+		t.Errorf("got line %d, columns %d-%d", e.Line, e.Column, e.EndColumn)
+	}
+}
@@ -27,11 +27,15 @@ func CheckNoSetextHeadings(filename string, ctx *preprocess.Context, offset int)
 
 		if !inBlockContext(ctx, i) && setextUnderlineRegex.MatchString(line) &&
 			!isPrevLineEmpty && !isPrevLineOtherBlock && !isInLazyBlockquote {
-			errs = append(errs, LintError{
+			e := atContent(LintError{
 				File:    filename,
 				Line:    i + 1 + offset,
 				Message: "Setext heading found (prefer ATX style instead)",
-			})
+			}, line)
+			if edits := setextToATXEdits(ctx, i, offset); edits != nil {
+				e = withFix(e, edits...)
+			}
+			errs = append(errs, e)
 		}
 
 		if isCurrentLineEmpty {
@@ -50,3 +54,22 @@ func CheckNoSetextHeadings(filename string, ctx *preprocess.Context, offset int)
 
 	return errs
 }
+
+// setextToATXEdits rewrites the setext heading whose underline is line i as an
+// ATX heading. Only single-line heading text is rewritten; a multi-line
+// setext heading has no one-line ATX equivalent.
+func setextToATXEdits(ctx *preprocess.Context, i, offset int) []Edit {
+	if i >= 2 && strings.TrimSpace(ctx.Line(i-2)) != "" {
+		return nil
+	}
+	text, underline := ctx.Line(i-1), ctx.Line(i)
+	marker := "# "
+	if firstNonSpaceByte(underline) == '-' {
+		marker = "## "
+	}
+	start, _ := contentSpan(text)
+	return []Edit{
+		replaceBytes(offset+i, text, start, start, marker),
+		deleteLineAfter(offset+i+1, text, underline),
+	}
+}
@@ -31,11 +31,11 @@ func CheckSingleH1(filename string, ctx *preprocess.Context, offset int) []LintE
 			continue
 		}
 
-		errs = append(errs, LintError{
+		errs = append(errs, atContent(LintError{
 			File:    filename,
 			Line:    offset + i + 1,
 			Message: "Multiple H1 headings found; only one H1 is allowed per file",
-		})
+		}, line))
 	}
 
 	return errs
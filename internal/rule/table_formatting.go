@@ -0,0 +1,367 @@
+package rule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/width"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+// reAlignMarker matches a valid delimiter cell: hyphens with an optional
+// colon at either end.
+var reAlignMarker = regexp.MustCompile(`^:?-+:?$`)
+
+// tableCell is one cell of a table row: its content, trimmed, and the byte
+// range of that content in the line.
+type tableCell struct {
+	text       string
+	start, end int
+}
+
+// tableRow is a table row split into cells at unescaped pipes.
+type tableRow struct {
+	cells    []tableCell
+	leading  bool   // starts with a pipe
+	trailing bool   // ends with a pipe
+	indent   string // whitespace before the row
+	start    int    // byte range of the row, without surrounding whitespace
+	end      int
+}
+
+// parseTableRow splits line into cells. Pipes escaped with a backslash are
+// part of a cell.
+func parseTableRow(line string) tableRow {
+	end := len(strings.TrimRight(line, " \t\r"))
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	if start > end {
+		start = end
+	}
+	r := tableRow{indent: line[:start], start: start, end: end}
+
+	var pipes []int
+	for i := start; i < end; i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '|':
+			pipes = append(pipes, i)
+		}
+	}
+	r.leading = len(pipes) > 0 && pipes[0] == start
+	r.trailing = len(pipes) > 0 && pipes[len(pipes)-1] == end-1 && (len(pipes) > 1 || !r.leading)
+
+	from := start
+	if r.leading {
+		from++
+		pipes = pipes[1:]
+	}
+	if r.trailing {
+		pipes = pipes[:len(pipes)-1]
+	}
+	for _, p := range append(pipes, -1) {
+		to := p
+		if p < 0 {
+			to = end
+			if r.trailing {
+				to = end - 1
+			}
+		}
+		r.cells = append(r.cells, newTableCell(line, from, to))
+		from = p + 1
+	}
+	return r
+}
+
+func newTableCell(line string, from, to int) tableCell {
+	for from < to && (line[from] == ' ' || line[from] == '\t') {
+		from++
+	}
+	for to > from && (line[to-1] == ' ' || line[to-1] == '\t') {
+		to--
+	}
+	return tableCell{text: line[from:to], start: from, end: to}
+}
+
+// pipeStyleOf returns the pipeStyle value that describes r.
+func pipeStyleOf(r tableRow) string {
+	switch {
+	case r.leading && r.trailing:
+		return "both"
+	case r.leading:
+		return "leading"
+	case r.trailing:
+		return "trailing"
+	}
+	return "none"
+}
+
+func pipeStyleName(style string) string {
+	switch style {
+	case "both":
+		return "leading and trailing pipes"
+	case "leading":
+		return "a leading pipe only"
+	case "trailing":
+		return "a trailing pipe only"
+	}
+	return "no leading or trailing pipes"
+}
+
+// CheckTableFormatting checks every pipe table in ctx: each row must have as
+// many cells as the delimiter row, whose cells must be valid alignment
+// markers, and every row must have the outer pipes pipeStyle asks for. In
+// "consistent" style the first table row of the document sets them. With
+// alignColumns, the cells of a well-formed table must also be padded so its
+// pipes line up, and the fix rewrites the table that way.
+func CheckTableFormatting(filename string, ctx *preprocess.Context, offset int, pipeStyle string, alignColumns bool) []LintError {
+	var errs []LintError
+	for _, span := range ctx.TableSpans() {
+		rows := make([]tableRow, span.End-span.Start+1)
+		for k := range rows {
+			rows[k] = parseTableRow(ctx.Line(span.Start + k))
+		}
+		if pipeStyle == "consistent" {
+			pipeStyle = pipeStyleOf(rows[0])
+		}
+		structure := checkTableStructure(filename, ctx, offset, span, rows)
+		errs = append(errs, structure...)
+		pipes := checkTablePipes(filename, ctx, offset, span, rows, pipeStyle)
+		if alignColumns && len(structure) == 0 {
+			if e, ok := checkTableAlignment(filename, ctx, offset, span, rows, pipeStyle); ok {
+				// The aligned table has the right pipes already, and a pipe
+				// fix at the end of a row would land after it.
+				for k := range pipes {
+					pipes[k].Fix = nil
+				}
+				pipes = append(pipes, e)
+			}
+		}
+		errs = append(errs, pipes...)
+	}
+	return errs
+}
+
+// checkTableStructure reports invalid alignment markers and rows whose cell
+// count differs from the delimiter row's.
+func checkTableStructure(filename string, ctx *preprocess.Context, offset int, span preprocess.TableSpan, rows []tableRow) []LintError {
+	var errs []LintError
+	delim := rows[1]
+	for j, c := range delim.cells {
+		if !reAlignMarker.MatchString(c.text) {
+			errs = append(errs, atSpan(LintError{
+				File:    filename,
+				Line:    offset + span.Start + 2,
+				Message: fmt.Sprintf("table-formatting: invalid alignment marker %q in column %d", c.text, j+1),
+			}, ctx.Line(span.Start+1), c.start, c.end))
+		}
+	}
+	for k, r := range rows {
+		if k == 1 || len(r.cells) == len(delim.cells) {
+			continue
+		}
+		msg := fmt.Sprintf("table-formatting: row has %d cells, expected %d", len(r.cells), len(delim.cells))
+		if k == 0 {
+			msg = fmt.Sprintf("table-formatting: header row has %d cells, delimiter row has %d", len(r.cells), len(delim.cells))
+		}
+		errs = append(errs, atSpan(LintError{
+			File:    filename,
+			Line:    offset + span.Start + k + 1,
+			Message: msg,
+		}, ctx.Line(span.Start+k), r.start, r.end))
+	}
+	return errs
+}
+
+// checkTablePipes reports rows whose outer pipes differ from style, with a
+// fix that adds or removes them.
+func checkTablePipes(filename string, ctx *preprocess.Context, offset int, span preprocess.TableSpan, rows []tableRow, style string) []LintError {
+	wantLeading := style == "both" || style == "leading"
+	wantTrailing := style == "both" || style == "trailing"
+
+	var errs []LintError
+	for k, r := range rows {
+		got := pipeStyleOf(r)
+		if got == style {
+			continue
+		}
+		line := ctx.Line(span.Start + k)
+		e := atSpan(LintError{
+			File:    filename,
+			Line:    offset + span.Start + k + 1,
+			Message: fmt.Sprintf("table-formatting: expected %s, got %s", pipeStyleName(style), pipeStyleName(got)),
+		}, line, r.start, r.end)
+		if edits, ok := pipeEdits(e.Line, line, r, wantLeading, wantTrailing); ok {
+			e = withFix(e, edits...)
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// pipeEdits adds or removes the outer pipes of r, on line number lineNum.
+// It declines when that would change the row's cells: removing a pipe next
+// to an empty cell drops the cell, and a row needs at least one pipe to stay
+// in the table.
+func pipeEdits(lineNum int, line string, r tableRow, wantLeading, wantTrailing bool) ([]Edit, bool) {
+	first, last := r.cells[0], r.cells[len(r.cells)-1]
+	if (r.leading && !wantLeading && first.text == "") ||
+		(r.trailing && !wantTrailing && last.text == "") ||
+		(len(r.cells) == 1 && !wantLeading && !wantTrailing) {
+		return nil, false
+	}
+	var edits []Edit
+	switch {
+	case wantLeading && !r.leading:
+		edits = append(edits, replaceBytes(lineNum, line, r.start, r.start, "| "))
+	case !wantLeading && r.leading:
+		edits = append(edits, replaceBytes(lineNum, line, r.start, first.start, ""))
+	}
+	switch {
+	case wantTrailing && !r.trailing:
+		edits = append(edits, replaceBytes(lineNum, line, r.end, r.end, " |"))
+	case !wantTrailing && r.trailing:
+		edits = append(edits, replaceBytes(lineNum, line, last.end, r.end, ""))
+	}
+	return edits, true
+}
+
+// checkTableAlignment reports a table whose rows differ from their aligned
+// form, with a fix that replaces the table with it.
+func checkTableAlignment(filename string, ctx *preprocess.Context, offset int, span preprocess.TableSpan, rows []tableRow, style string) (LintError, bool) {
+	aligned := alignTable(rows, style)
+	same := true
+	for k, want := range aligned {
+		if strings.TrimSuffix(ctx.Line(span.Start+k), "\r") != want {
+			same = false
+			break
+		}
+	}
+	if same {
+		return LintError{}, false
+	}
+	header := ctx.Line(span.Start)
+	last := strings.TrimSuffix(ctx.Line(span.End), "\r")
+	e := atSpan(LintError{
+		File:    filename,
+		Line:    offset + span.Start + 1,
+		Message: "table-formatting: table columns are not aligned",
+	}, header, rows[0].start, rows[0].end)
+	return withFix(e, Edit{
+		Line:      offset + span.Start + 1,
+		Column:    1,
+		EndLine:   offset + span.End + 1,
+		EndColumn: charColumn(last, len(last)),
+		NewText:   strings.Join(aligned, "\n"),
+	}), true
+}
+
+// alignTable renders rows, a well-formed table, with each column padded to
+// its widest cell and the outer pipes style asks for. Cells are padded on
+// the side their alignment marker calls for, and the delimiter row's
+// hyphens fill its columns. A row keeps the outer pipe next to an empty
+// first or last cell, whatever style says, as the cell is lost without it;
+// checkTablePipes reports such rows.
+func alignTable(rows []tableRow, style string) []string {
+	delim := rows[1].cells
+	widths := make([]int, len(delim))
+	for j := range widths {
+		widths[j] = 3
+	}
+	for k, r := range rows {
+		if k == 1 {
+			continue
+		}
+		for j, c := range r.cells {
+			widths[j] = max(widths[j], displayWidth(c.text))
+		}
+	}
+
+	out := make([]string, len(rows))
+	for k, r := range rows {
+		cells := make([]string, len(r.cells))
+		for j, c := range r.cells {
+			if k == 1 {
+				cells[j] = alignMarker(c.text, widths[j])
+			} else {
+				cells[j] = padCell(c.text, widths[j], delim[j].text)
+			}
+		}
+		out[k] = joinTableRow(rows[0].indent, cells, edgeStyle(style, r))
+	}
+	return out
+}
+
+// alignMarker returns a delimiter cell of width w with the colons of marker.
+func alignMarker(marker string, w int) string {
+	left, right := strings.HasPrefix(marker, ":"), strings.HasSuffix(marker, ":")
+	dashes := w
+	if left {
+		dashes--
+	}
+	if right {
+		dashes--
+	}
+	s := strings.Repeat("-", dashes)
+	if left {
+		s = ":" + s
+	}
+	if right {
+		s += ":"
+	}
+	return s
+}
+
+// padCell pads text to width w, on the side marker aligns it to.
+func padCell(text string, w int, marker string) string {
+	pad := w - displayWidth(text)
+	left, right := strings.HasPrefix(marker, ":"), strings.HasSuffix(marker, ":")
+	switch {
+	case left && right:
+		return strings.Repeat(" ", pad/2) + text + strings.Repeat(" ", pad-pad/2)
+	case right:
+		return strings.Repeat(" ", pad) + text
+	}
+	return text + strings.Repeat(" ", pad)
+}
+
+// edgeStyle returns style with the outer pipes added that r needs to keep
+// an empty first or last cell.
+func edgeStyle(style string, r tableRow) string {
+	return pipeStyleOf(tableRow{
+		leading:  style == "both" || style == "leading" || r.cells[0].text == "",
+		trailing: style == "both" || style == "trailing" || r.cells[len(r.cells)-1].text == "",
+	})
+}
+
+func joinTableRow(indent string, cells []string, style string) string {
+	s := strings.Join(cells, " | ")
+	switch style {
+	case "both":
+		s = "| " + s + " |"
+	case "leading":
+		s = strings.TrimRight("| "+s, " ")
+	case "trailing":
+		s += " |"
+	default:
+		s = strings.TrimRight(s, " ")
+	}
+	return indent + s
+}
+
+// displayWidth returns the number of columns s takes in a monospaced font:
+// two for East Asian wide and fullwidth characters, one for others.
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+package rule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+)
+
+func TestParseTableRow(t *testing.T) {
+	tests := []struct {
+		line              string
+		cells             []string
+		leading, trailing bool
+	}{
+		{"| a | b |", []string{"a", "b"}, true, true},
+		{"a | b", []string{"a", "b"}, false, false},
+		{"  | a | b", []string{"a", "b"}, true, false},
+		{"a | b |\r", []string{"a", "b"}, false, true},
+		{"| a \\| b | `c|d` |", []string{"a \\| b", "`c", "d`"}, true, true},
+		{"| | x |", []string{"", "x"}, true, true},
+		{"|", []string{""}, true, false},
+		{"plain", []string{"plain"}, false, false},
+	}
+	for _, tt := range tests {
+		r := parseTableRow(tt.line)
+		var cells []string
+		for _, c := range r.cells {
+			cells = append(cells, c.text)
+			if tt.line[c.start:c.end] != c.text {
+				t.Errorf("%q: cell %q has range %d-%d", tt.line, c.text, c.start, c.end)
+			}
+		}
+		if !reflect.DeepEqual(cells, tt.cells) || r.leading != tt.leading || r.trailing != tt.trailing {
+			t.Errorf("parseTableRow(%q) = %q, leading %v, trailing %v", tt.line, cells, r.leading, r.trailing)
+		}
+	}
+}
+
+func TestCheckTableFormatting(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		pipeStyle string
+		align     bool
+		want      []string
+	}{
+		{"well formed", "| a | b |\n| :- | -: |\n| 1 | 2 |\n", "consistent", false, nil},
+		{"missing cell", "| a | b |\n| - | - |\n| 1 |\n| 2 | 3 | 4 |\n", "consistent", false, []string{
+			"3: table-formatting: row has 1 cells, expected 2",
+			"4: table-formatting: row has 3 cells, expected 2",
+		}},
+		{"header and delimiter differ", "| a | b | c |\n| - | - |\n", "consistent", false, []string{
+			"1: table-formatting: header row has 3 cells, delimiter row has 2",
+		}},
+		{"invalid markers", "a | b | c\n-:- | - - | ::-\n", "consistent", false, []string{
+			`2: table-formatting: invalid alignment marker "-:-" in column 1`,
+			`2: table-formatting: invalid alignment marker "- -" in column 2`,
+			`2: table-formatting: invalid alignment marker "::-" in column 3`,
+		}},
+		{"escaped pipe is content", "| a | b |\n| - | - |\n| x \\| y | z |\n", "consistent", false, nil},
+		{"consistent pipes follow the first table", "a | b\n--|--\n| 1 | 2 |\n\n| c |\n| - |\n", "consistent", false, []string{
+			"3: table-formatting: expected no leading or trailing pipes, got leading and trailing pipes",
+			"5: table-formatting: expected no leading or trailing pipes, got leading and trailing pipes",
+			"6: table-formatting: expected no leading or trailing pipes, got leading and trailing pipes",
+		}},
+		{"required pipe style", "| a | b\n|---|---\n", "both", false, []string{
+			"1: table-formatting: expected leading and trailing pipes, got a leading pipe only",
+			"2: table-formatting: expected leading and trailing pipes, got a leading pipe only",
+		}},
+		{"aligned", "| a   |  bb |\n| --- | --: |\n| 1   |   2 |\n", "consistent", true, nil},
+		{"not aligned", "| a | bb |\n| --- | --: |\n| 1 | 2 |\n", "consistent", true, []string{
+			"1: table-formatting: table columns are not aligned",
+		}},
+		{"malformed table is not aligned", "| a | b |\n| - | - |\n| 1 |\n", "consistent", true, []string{
+			"3: table-formatting: row has 1 cells, expected 2",
+		}},
+		{"table in code", "```\n| a | b |\n| - | - |\n| 1 |\n```\n", "consistent", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := preprocess.Scan(strings.Split(tt.content, "\n"))
+			got := findings(CheckTableFormatting("test.md", ctx, 0, tt.pipeStyle, tt.align))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTableFormatting_AlignFix(t *testing.T) {
+	lines := []string{"Intro", "  |a|b|\r", "  |:-:|--|\r", "  |long cell|x|\r", ""}
+	errs := CheckTableFormatting("test.md", preprocess.Scan(lines), 10, "consistent", true)
+	if len(errs) != 1 || errs[0].Fix == nil {
+		t.Fatalf("expected one fixable error, got %+v", errs)
+	}
+	want := Edit{
+		Line: 12, Column: 1, EndLine: 14, EndColumn: 16,
+		NewText: "  |     a     | b   |\n  | :-------: | --- |\n  | long cell | x   |",
+	}
+	if got := errs[0].Fix.Edits; !reflect.DeepEqual(got, []Edit{want}) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAlignTable_KeepsEmptyEdgeCells(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		style string
+		want  []string
+	}{
+		{"none, empty first cell", []string{"a | b", "--|--", "| | b |"}, "none", []string{"a   | b", "--- | ---", "|     | b"}},
+		{"none, empty last cell", []string{"a | b", "--|--", "| c | |"}, "none", []string{"a   | b", "--- | ---", "c   |     |"}},
+		{"leading, empty last cell", []string{"| a | |", "| - | - |"}, "leading", []string{"| a   |     |", "| --- | ---"}},
+		{"trailing, empty first cell", []string{"| | a |", "| - | - |"}, "trailing", []string{"|     | a   |", "--- | --- |"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([]tableRow, len(tt.lines))
+			for k, line := range tt.lines {
+				rows[k] = parseTableRow(line)
+			}
+			got := alignTable(rows, tt.style)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			for k, line := range got {
+				if n := len(parseTableRow(line).cells); n != len(rows[k].cells) {
+					t.Errorf("row %d has %d cells after aligning, want %d", k+1, n, len(rows[k].cells))
+				}
+			}
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{"日本語", 6},
+		{"ｱ", 1},
+		{"Ａ", 2},
+		{"é", 1},
+	}
+	for _, tt := range tests {
+		if got := displayWidth(tt.s); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
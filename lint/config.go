@@ -0,0 +1,170 @@
+package lint
+
+import (
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/linter"
+)
+
+// Severity is the level a rule reports violations at.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+// RuleConfig configures one rule.
+type RuleConfig struct {
+	Enabled  bool
+	Severity Severity
+	// Options holds rule-specific options in their decoded JSON form:
+	// numbers are float64 and arrays are []interface{}, as in
+	// .gomarklint.json.
+	Options map[string]interface{}
+}
+
+// Plugin declares a rule implemented by an external executable. See the
+// plugins section of the configuration docs for the protocol.
+type Plugin struct {
+	// Name is the rule name findings are reported under and the plugin's
+	// key in Rules, which can disable it or pass it options.
+	Name string
+	// Command is the executable and its arguments.
+	Command []string
+	// TimeoutSeconds bounds one run on one file. Zero means 10 seconds.
+	TimeoutSeconds int
+}
+
+// CustomRule declares a rule that reports every match of a regular
+// expression. See the custom-rules section of the configuration docs.
+type CustomRule struct {
+	// Pattern is an RE2 regular expression.
+	Pattern string
+	// Message is reported for each match. Empty reports the matched text.
+	Message string
+	// Scope is "text" (the default), "code", "heading" or "link-url".
+	Scope string
+}
+
+// Config selects and configures the rules a Linter runs. The zero value
+// enables nothing; start from DefaultConfig, LoadConfig or ParseConfig.
+type Config struct {
+	// Default enables rules that have no entry in Rules.
+	Default bool
+	Rules   map[string]RuleConfig
+	// Plugins run for every file in addition to the built-in rules.
+	Plugins []Plugin
+	// CustomRules maps rule names to pattern rules, which are configured in
+	// Rules like any other rule.
+	CustomRules map[string]CustomRule
+	// Ignore lists doublestar patterns, such as "vendor/**", for files
+	// LintFS skips.
+	Ignore []string
+	// MinSeverity drops warnings when set to SeverityError. Empty means
+	// SeverityWarning, which reports everything.
+	MinSeverity Severity
+}
+
+// DefaultConfig returns the configuration gomarklint uses when no
+// .gomarklint.json exists.
+func DefaultConfig() Config {
+	return fromInternal(config.Default())
+}
+
+// LoadConfig reads a .gomarklint.json file. The CLI-only settings include
+// and output are ignored.
+func LoadConfig(path string) (Config, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return fromInternal(cfg), nil
+}
+
+// ParseConfig decodes a config in the .gomarklint.json format.
+func ParseConfig(data []byte) (Config, error) {
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return Config{}, err
+	}
+	return fromInternal(cfg), nil
+}
+
+// RuleNames returns the name of every built-in rule.
+func RuleNames() []string {
+	return linter.RuleNames()
+}
+
+func fromInternal(cfg config.Config) Config {
+	rules := make(map[string]RuleConfig, len(cfg.Rules))
+	for name, rc := range cfg.Rules {
+		if rc == nil {
+			continue
+		}
+		rules[name] = RuleConfig{Enabled: rc.Enabled, Severity: Severity(rc.Severity), Options: copyOptions(rc.Options)}
+	}
+	var plugins []Plugin
+	for _, p := range cfg.Plugins {
+		plugins = append(plugins, Plugin{Name: p.Name, Command: append([]string(nil), p.Command...), TimeoutSeconds: p.TimeoutSeconds})
+	}
+	var custom map[string]CustomRule
+	for name, c := range cfg.CustomRules {
+		if custom == nil {
+			custom = make(map[string]CustomRule, len(cfg.CustomRules))
+		}
+		custom[name] = CustomRule(c)
+	}
+	return Config{
+		Default:     cfg.Default,
+		Rules:       rules,
+		Plugins:     plugins,
+		CustomRules: custom,
+		Ignore:      append([]string(nil), cfg.Ignore...),
+		MinSeverity: Severity(cfg.MinSeverity),
+	}
+}
+
+func (c Config) toInternal() config.Config {
+	rules := make(map[string]*config.RuleConfig, len(c.Rules))
+	for name, rc := range c.Rules {
+		sev := config.RuleSeverity(rc.Severity)
+		if sev == "" {
+			sev = config.SeverityError
+		}
+		enabled := rc.Enabled && sev != config.SeverityOff
+		rules[name] = &config.RuleConfig{Enabled: enabled, Severity: sev, Options: copyOptions(rc.Options)}
+	}
+	minSeverity := config.RuleSeverity(c.MinSeverity)
+	if minSeverity == "" {
+		minSeverity = config.SeverityWarning
+	}
+	var plugins []config.PluginConfig
+	for _, p := range c.Plugins {
+		plugins = append(plugins, config.PluginConfig{Name: p.Name, Command: append([]string(nil), p.Command...), TimeoutSeconds: p.TimeoutSeconds})
+	}
+	var custom map[string]config.CustomRuleConfig
+	for name, cr := range c.CustomRules {
+		if custom == nil {
+			custom = make(map[string]config.CustomRuleConfig, len(c.CustomRules))
+		}
+		custom[name] = config.CustomRuleConfig(cr)
+	}
+	return config.Config{
+		Default:      c.Default,
+		Rules:        rules,
+		Plugins:      plugins,
+		CustomRules:  custom,
+		Ignore:       append([]string(nil), c.Ignore...),
+		OutputFormat: "text",
+		MinSeverity:  minSeverity,
+	}
+}
+
+func copyOptions(opts map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(opts))
+	for k, v := range opts {
+		out[k] = v
+	}
+	return out
+}
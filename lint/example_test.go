@@ -0,0 +1,23 @@
+package lint_test
+
+import (
+	"fmt"
+
+	"github.com/shinagawa-web/gomarklint/v3/lint"
+)
+
+func Example() {
+	cfg := lint.DefaultConfig()
+	cfg.Rules["heading-level"] = lint.RuleConfig{Enabled: true, Severity: lint.SeverityWarning, Options: map[string]interface{}{"minLevel": float64(2)}}
+
+	l, err := lint.New(cfg)
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range l.LintBytes("generated.md", []byte("# Generated\n\nSee https://example.com\n")) {
+		fmt.Printf("%s:%d:%d %s %s\n", e.File, e.Line, e.Column, e.Severity, e.Rule)
+	}
+	// Output:
+	// generated.md:1:1 warning heading-level
+	// generated.md:3:5 error no-bare-urls
+}
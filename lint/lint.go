@@ -0,0 +1,142 @@
+// Package lint is the Go API for embedding gomarklint: it lints Markdown
+// in-process with the same rules and configuration as the CLI.
+//
+// # Compatibility
+//
+// This package follows semantic versioning with the module. Within v3 its
+// exported identifiers are not removed or changed incompatibly; new rules,
+// options, fields and functions may be added. Which violations a rule reports
+// and the wording of messages are not covered and may change between
+// releases, as they do for the CLI. Everything under internal/ may change at
+// any time.
+package lint
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/file"
+	"github.com/shinagawa-web/gomarklint/v3/internal/linter"
+	"github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+// LintError is a single violation. Line is 1-based. Column and EndColumn
+// are 1-based character columns and EndColumn is exclusive; all three are
+// zero when a rule reports only a line.
+type LintError struct {
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Column    int      `json:"column,omitempty"`
+	EndLine   int      `json:"end_line,omitempty"`
+	EndColumn int      `json:"end_column,omitempty"`
+	Rule      string   `json:"rule"`
+	Message   string   `json:"message"`
+	Severity  Severity `json:"severity"`
+}
+
+// Linter lints Markdown with a fixed configuration. It is safe for
+// concurrent use.
+type Linter struct {
+	linter *linter.Linter
+	cfg    config.Config
+}
+
+// New returns a Linter for cfg, or an error if a rule option is invalid.
+func New(cfg Config) (*Linter, error) {
+	ic := cfg.toInternal()
+	if err := config.Validate(ic); err != nil {
+		return nil, err
+	}
+	l, err := linter.New(ic)
+	if err != nil {
+		return nil, err
+	}
+	return &Linter{linter: l, cfg: ic}, nil
+}
+
+// LintBytes lints content as if it were read from the file name, which is
+// reported in each LintError. Ignore patterns are not applied.
+func (l *Linter) LintBytes(name string, content []byte) []LintError {
+	errs, _, _ := l.linter.LintContent(name, string(content))
+	return l.convert(errs)
+}
+
+// LintFile reads and lints the file at path. Ignore patterns are not
+// applied.
+func (l *Linter) LintFile(path string) ([]LintError, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.LintBytes(path, content), nil
+}
+
+// LintFS lints every .md file under root in fsys, skipping hidden
+// directories and files that match the config's ignore patterns, as the CLI
+// does for directories. Use "." for the whole file system. Results are
+// sorted by file, then position.
+func (l *Linter) LintFS(fsys fs.FS, root string) ([]LintError, error) {
+	var names []string
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if path.Ext(p) == ".md" && !file.ShouldIgnore(p, l.cfg.Ignore) {
+			names = append(names, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var all []LintError
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, l.LintBytes(name, content)...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return all, nil
+}
+
+func (l *Linter) convert(errs []rule.LintError) []LintError {
+	out := make([]LintError, 0, len(errs))
+	for _, e := range errs {
+		if l.cfg.MinSeverity == config.SeverityError && e.Severity == string(config.SeverityWarning) {
+			continue
+		}
+		out = append(out, LintError{
+			File:      e.File,
+			Line:      e.Line,
+			Column:    e.Column,
+			EndLine:   e.EndLine,
+			EndColumn: e.EndColumn,
+			Rule:      e.Rule,
+			Message:   e.Message,
+			Severity:  Severity(e.Severity),
+		})
+	}
+	return out
+}
@@ -0,0 +1,254 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/shinagawa-web/gomarklint/v3/lint"
+)
+
+func TestLintBytes(t *testing.T) {
+	l, err := lint.New(lint.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := l.LintBytes("doc.md", []byte("## Title\n\nSee https://example.com\n"))
+	want := []lint.LintError{{
+		File:      "doc.md",
+		Line:      3,
+		Column:    5,
+		EndLine:   3,
+		EndColumn: 24,
+		Rule:      "no-bare-urls",
+		Message:   "no-bare-urls: bare URL found, use angle brackets or a Markdown link: https://example.com",
+		Severity:  lint.SeverityError,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LintBytes =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	l, err := lint.New(lint.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "doc.md")
+	if err := os.WriteFile(path, []byte("## Title\n\n\ntext\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := l.LintFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Rule != "no-multiple-blank-lines" || got[0].File != path {
+		t.Errorf("unexpected errors: %+v", got)
+	}
+
+	if _, err := l.LintFile(filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestLintFS(t *testing.T) {
+	cfg := lint.DefaultConfig()
+	cfg.Ignore = []string{"vendor/**"}
+	l, err := lint.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"b.md":             {Data: []byte("# Title\n")},
+		"docs/a.md":        {Data: []byte("## Title\ntext\n")},
+		"docs/notes.txt":   {Data: []byte("# not markdown")},
+		"vendor/x.md":      {Data: []byte("# Title")},
+		".hidden/y.md":     {Data: []byte("# Title")},
+		"docs/clean.md":    {Data: []byte("## Title\n")},
+		"docs/.private.md": {Data: []byte("# Title")},
+	}
+	got, err := l.LintFS(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, e := range got {
+		keys = append(keys, e.File+":"+e.Rule)
+	}
+	want := []string{
+		"b.md:heading-level",
+		"docs/.private.md:heading-level",
+		"docs/.private.md:final-blank-line",
+		"docs/a.md:blanks-around-headings",
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("LintFS files/rules = %v, want %v", keys, want)
+	}
+
+	sub, err := l.LintFS(fsys, "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range sub {
+		if !strings.HasPrefix(e.File, "docs/") {
+			t.Errorf("unexpected file outside root: %s", e.File)
+		}
+	}
+
+	if _, err := l.LintFS(fsys, "missing"); err == nil {
+		t.Error("expected error for a missing root")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("rules can be switched off", func(t *testing.T) {
+		cfg := lint.DefaultConfig()
+		cfg.Rules["heading-level"] = lint.RuleConfig{Severity: lint.SeverityOff}
+		l, err := lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := l.LintBytes("doc.md", []byte("# Title\n")); len(got) != 0 {
+			t.Errorf("expected no errors, got %+v", got)
+		}
+	})
+
+	t.Run("warnings are dropped at error severity", func(t *testing.T) {
+		cfg := lint.DefaultConfig()
+		cfg.Rules["final-blank-line"] = lint.RuleConfig{Enabled: true, Severity: lint.SeverityWarning}
+		l, err := lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := l.LintBytes("doc.md", []byte("## Title")); len(got) != 1 || got[0].Severity != lint.SeverityWarning {
+			t.Errorf("expected one warning, got %+v", got)
+		}
+
+		cfg.MinSeverity = lint.SeverityError
+		l, err = lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := l.LintBytes("doc.md", []byte("## Title")); len(got) != 0 {
+			t.Errorf("expected warnings dropped, got %+v", got)
+		}
+	})
+
+	t.Run("parse applies file defaults", func(t *testing.T) {
+		cfg, err := lint.ParseConfig([]byte(`{"default": true, "rules": {"heading-level": {"minLevel": 1}}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Rules["external-link"].Enabled {
+			t.Error("expected external-link to stay disabled")
+		}
+		l, err := lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := l.LintBytes("doc.md", []byte("# Title\n")); len(got) != 0 {
+			t.Errorf("expected no errors, got %+v", got)
+		}
+	})
+
+	t.Run("invalid input is rejected", func(t *testing.T) {
+		if _, err := lint.ParseConfig([]byte(`{"unknown": true}`)); err == nil {
+			t.Error("expected error for unknown field")
+		}
+		cfg := lint.DefaultConfig()
+		cfg.Rules["consistent-list-marker"] = lint.RuleConfig{Enabled: true, Options: map[string]interface{}{"style": "star"}}
+		if _, err := lint.New(cfg); err == nil {
+			t.Error("expected error for invalid option")
+		}
+		cfg = lint.DefaultConfig()
+		cfg.MinSeverity = "info"
+		if _, err := lint.New(cfg); err == nil {
+			t.Error("expected error for invalid severity")
+		}
+	})
+
+	t.Run("plugins round-trip and run", func(t *testing.T) {
+		script := filepath.Join(t.TempDir(), "plugin.sh")
+		if err := os.WriteFile(script, []byte("#!/bin/sh\ncat >/dev/null\necho '{\"findings\":[{\"line\":1,\"message\":\"no titles\"}]}'\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := lint.ParseConfig([]byte(`{"default": false, "rules": {}, "plugins": [{"name": "house-style", "command": ["` + script + `"]}]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []lint.Plugin{{Name: "house-style", Command: []string{script}}}; !reflect.DeepEqual(cfg.Plugins, want) {
+			t.Fatalf("Plugins = %+v, want %+v", cfg.Plugins, want)
+		}
+		l, err := lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := l.LintBytes("doc.md", []byte("# Title\n"))
+		if len(got) != 1 || got[0].Rule != "house-style" || got[0].Message != "house-style: no titles" {
+			t.Errorf("expected the plugin's finding, got %+v", got)
+		}
+	})
+
+	t.Run("custom rules round-trip and run", func(t *testing.T) {
+		cfg, err := lint.ParseConfig([]byte(`{"default": false, "rules": {"no-utilize": "warning"}, "custom-rules": {"no-utilize": {"pattern": "utilize", "message": "say use"}}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[string]lint.CustomRule{"no-utilize": {Pattern: "utilize", Message: "say use"}}; !reflect.DeepEqual(cfg.CustomRules, want) {
+			t.Fatalf("CustomRules = %+v, want %+v", cfg.CustomRules, want)
+		}
+		l, err := lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := l.LintBytes("doc.md", []byte("We utilize it.\n"))
+		if len(got) != 1 || got[0].Severity != lint.SeverityWarning || got[0].Message != "no-utilize: say use" || got[0].Column != 4 {
+			t.Errorf("expected the custom rule's finding, got %+v", got)
+		}
+	})
+
+	t.Run("config is copied", func(t *testing.T) {
+		cfg := lint.DefaultConfig()
+		l, err := lint.New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Rules["heading-level"] = lint.RuleConfig{Enabled: false}
+		if got := l.LintBytes("doc.md", []byte("# Title\n")); len(got) != 1 {
+			t.Errorf("expected the linter to keep its config, got %+v", got)
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gomarklint.json")
+	if err := os.WriteFile(path, []byte(`{"default": false, "rules": {"no-hard-tabs": true}, "ignore": ["x/**"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := lint.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Default || !cfg.Rules["no-hard-tabs"].Enabled || !reflect.DeepEqual(cfg.Ignore, []string{"x/**"}) {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if _, err := lint.LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestRuleNames(t *testing.T) {
+	names := lint.RuleNames()
+	cfg := lint.DefaultConfig()
+	for _, name := range names {
+		if _, ok := cfg.Rules[name]; !ok {
+			t.Errorf("rule %s missing from DefaultConfig", name)
+		}
+	}
+	if len(names) != len(cfg.Rules) {
+		t.Errorf("RuleNames has %d rules, DefaultConfig %d", len(names), len(cfg.Rules))
+	}
+}
@@ -0,0 +1,98 @@
+// Package rule lets Go packages add lint rules to gomarklint without forking
+// it. A rule registered from an init function runs alongside the built-in
+// rules in every linter created afterwards: a CLI built around cmd.Execute,
+// the lint package and the language server.
+//
+// Rules are configured like built-in ones, under their name in the "rules"
+// object of .gomarklint.json. When the config does not list a rule, it runs
+// at its DefaultSeverity if "default" is true. Options in the config are
+// validated against the rule's declared Options before any file is linted.
+//
+// This package follows the same compatibility promise as the lint package.
+package rule
+
+import (
+	"github.com/shinagawa-web/gomarklint/v3/internal/config"
+	"github.com/shinagawa-web/gomarklint/v3/internal/preprocess"
+	internal "github.com/shinagawa-web/gomarklint/v3/internal/rule"
+)
+
+type (
+	// Rule is a lint rule. Check receives the lines after any frontmatter;
+	// the first is line offset+1 of the file, so a violation on ctx.Line(i)
+	// is reported at Line offset+i+1. The linter sets Rule and Severity on
+	// the returned errors.
+	Rule = internal.Rule
+	// Option declares one option a rule accepts.
+	Option = internal.Option
+	// OptionType is the JSON type of an option.
+	OptionType = internal.OptionType
+	// Options holds a rule's option values, with defaults filled in.
+	Options = internal.Options
+	// LintError is a violation. Column and EndColumn are 1-based character
+	// columns and EndColumn is exclusive; they may be left zero.
+	LintError = internal.LintError
+	// Fix is an optional machine-applicable correction attached to a
+	// LintError, used by --fix and the language server.
+	Fix = internal.Fix
+	// Edit is one text replacement in a Fix.
+	Edit = internal.Edit
+	// Context is one file's lines, classified in a single pass: whether each
+	// line is in a fenced or indented code block, an HTML block or comment,
+	// and a sanitized copy with inline code and comments blanked out.
+	Context = preprocess.Context
+	// FenceSpan is the line range of one fenced code block.
+	FenceSpan = preprocess.FenceSpan
+	// TableSpan is the line range of one pipe table: its header, delimiter
+	// and body rows.
+	TableSpan = preprocess.TableSpan
+	// Severity is the level a rule reports at.
+	Severity = config.RuleSeverity
+)
+
+const (
+	SeverityError   = config.SeverityError
+	SeverityWarning = config.SeverityWarning
+)
+
+const (
+	OptionString     = internal.OptionString     // string
+	OptionInt        = internal.OptionInt        // int
+	OptionBool       = internal.OptionBool       // bool
+	OptionStringList = internal.OptionStringList // []string
+	OptionIntList    = internal.OptionIntList    // []int
+	OptionObject     = internal.OptionObject     // map[string]interface{}
+)
+
+// Register adds r to the rules every linter runs. Call it from an init
+// function; it panics if a rule with the same name is already registered.
+func Register(r Rule) {
+	internal.Register(r)
+}
+
+// Names returns the name of every registered rule, built-in rules first.
+func Names() []string {
+	rules := internal.Registered()
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.Name()
+	}
+	return names
+}
+
+// Scan builds the Context for lines, as the linter does before calling
+// Check. It is useful for testing rules.
+func Scan(lines []string) *Context {
+	return preprocess.Scan(lines)
+}
+
+// IntRange returns an Option.Validate function accepting integers in
+// [min, max].
+func IntRange(min, max int) func(interface{}) error {
+	return internal.IntRange(min, max)
+}
+
+// IntAtLeast returns an Option.Validate function accepting integers >= min.
+func IntAtLeast(min int) func(interface{}) error {
+	return internal.IntAtLeast(min)
+}
@@ -0,0 +1,103 @@
+package rule_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinagawa-web/gomarklint/v3/lint"
+	"github.com/shinagawa-web/gomarklint/v3/rule"
+)
+
+// noTODO flags lines containing a marker, outside code blocks.
+type noTODO struct{}
+
+func (noTODO) Name() string                   { return "test-no-todo" }
+func (noTODO) DefaultSeverity() rule.Severity { return rule.SeverityWarning }
+
+func (noTODO) Options() []rule.Option {
+	return []rule.Option{{Name: "marker", Type: rule.OptionString, Default: "TODO"}}
+}
+
+func (noTODO) Check(path string, ctx *rule.Context, offset int, opts rule.Options) []rule.LintError {
+	var errs []rule.LintError
+	marker := opts.String("marker")
+	for i := 0; i < ctx.Len(); i++ {
+		if ctx.InFencedCode(i) {
+			continue
+		}
+		if col := strings.Index(ctx.Line(i), marker); col >= 0 {
+			errs = append(errs, rule.LintError{File: path, Line: offset + i + 1, Column: col + 1, Message: marker + " left in text"})
+		}
+	}
+	return errs
+}
+
+func init() {
+	rule.Register(noTODO{})
+}
+
+const doc = "---\ntitle: x\n---\n\n## Notes\n\nTODO: write\n\n```sh\n# TODO in code\n```\n\nFIXME later\n"
+
+func TestRegisteredRuleRunsWithBuiltins(t *testing.T) {
+	l, err := lint.New(lint.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := l.LintBytes("doc.md", []byte(doc))
+	if len(errs) != 1 {
+		t.Fatalf("expected one violation, got %+v", errs)
+	}
+	want := lint.LintError{File: "doc.md", Line: 7, Column: 1, Rule: "test-no-todo", Message: "TODO left in text", Severity: lint.SeverityWarning}
+	if errs[0] != want {
+		t.Errorf("got %+v, want %+v", errs[0], want)
+	}
+}
+
+func TestRegisteredRuleIsConfigured(t *testing.T) {
+	cfg, err := lint.ParseConfig([]byte(`{"rules": {"test-no-todo": {"severity": "error", "marker": "FIXME"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := lint.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := l.LintBytes("doc.md", []byte(doc))
+	if len(errs) != 1 || errs[0].Line != 13 || errs[0].Severity != lint.SeverityError {
+		t.Errorf("unexpected violations: %+v", errs)
+	}
+
+	cfg, err = lint.ParseConfig([]byte(`{"rules": {"test-no-todo": {"marker": 1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lint.New(cfg); err == nil || !strings.Contains(err.Error(), "test-no-todo.marker: expected string") {
+		t.Errorf("expected option validation error, got %v", err)
+	}
+
+	cfg, err = lint.ParseConfig([]byte(`{"default": false, "rules": {"final-blank-line": true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, err = lint.New(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if errs := l.LintBytes("doc.md", []byte(doc)); len(errs) != 0 {
+		t.Errorf("expected unlisted rule to be off with default false, got %+v", errs)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := rule.Names()
+	if names[0] != "final-blank-line" || names[len(names)-1] != "test-no-todo" {
+		t.Errorf("expected built-ins first and registered rules last, got %v", names)
+	}
+}
+
+func TestScanTableSpans(t *testing.T) {
+	ctx := rule.Scan([]string{"Intro", "", "| a | b |", "| - | - |", "| 1 | 2 |", ""})
+	want := []rule.TableSpan{{Start: 2, End: 4}}
+	if got := ctx.TableSpans(); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("TableSpans() = %+v, want %+v", got, want)
+	}
+}